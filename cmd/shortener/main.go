@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/romanp1989/go-shortener/internal/app"
 	"html/template"
@@ -36,8 +37,18 @@ func main() {
 		log.Fatal(err)
 	}
 
-	go app.RunGRPCServer(cfg)
-	app.RunServer(cfg)
+	// HTTP и gRPC серверы работают с одним хранилищем
+	appService := app.NewAppService(cfg)
+
+	// фоновые задачи сервиса запускаются один раз и останавливаются после остановки сервера
+	ctx, cancel := context.WithCancel(context.Background())
+	appService.Start(ctx)
+
+	go app.RunGRPCServer(cfg, appService)
+	app.RunServer(cfg, appService)
+
+	cancel()
+	appService.Wait()
 
 }
 
@@ -30,8 +30,15 @@ type App struct {
 	chi         *chi.Mux
 }
 
+// NewAppService factory for create storage and application service shared by HTTP and gRPC servers.
+// File storage is loaded into memory once, so the servers must not create their own storages
+func NewAppService(cfg *config.ConfigENV) *shortener_service.ShortenerService {
+	s := storage.Init(cfg.DatabaseDsn, cfg.FileStorage)
+	return shortener_service.NewShortenerService(s, cfg)
+}
+
 // RunServer run application server
-func RunServer(cfg *config.ConfigENV) error {
+func RunServer(cfg *config.ConfigENV, appService *shortener_service.ShortenerService) error {
 	var err error
 
 	errChan := make(chan error, 1)
@@ -43,9 +50,6 @@ func RunServer(cfg *config.ConfigENV) error {
 
 	logger.Log.Info("Running server on ", zap.String("port", cfg.ServerAddress))
 
-	s := storage.Init(cfg.DatabaseDsn, cfg.FileStorage)
-
-	appService := shortener_service.NewShortenerService(s, cfg)
 	jwtService := auth.NewJwtService(cfg.SecretKey)
 
 	h := handlers.New(appService)
@@ -100,17 +104,17 @@ func RunServer(cfg *config.ConfigENV) error {
 	}
 }
 
-func RunGRPCServer(cfg *config.ConfigENV) error {
+// RunGRPCServer run gRPC server with application service shared with HTTP server
+func RunGRPCServer(cfg *config.ConfigENV, appService *shortener_service.ShortenerService) error {
 	var err error
 
-	s := storage.Init(cfg.DatabaseDsn, cfg.FileStorage)
 	jwtService := auth.NewJwtService(cfg.SecretKey)
 
-	appService := shortener_service.NewShortenerService(s, cfg)
 	h := grpcHandlers.New(appService)
 	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor([]grpc.UnaryServerInterceptor{
-		interceptors.AuthInterceptor(jwtService),
+		interceptors.AuthInterceptor(jwtService, appService),
 		interceptors.IPRestrictionInterceptor(cfg.TrustedSubnet),
+		interceptors.IdempotencyInterceptor(appService),
 	}...))
 	proto.RegisterInternalServer(grpcServer, h)
 
@@ -29,7 +29,7 @@ func TestRunServer(t *testing.T) {
 				ServerAddress: "http://localhost:8080",
 				BaseURL:       "http://localhost:8080",
 			}
-			err := RunServer(cfg)
+			err := RunServer(cfg, NewAppService(cfg))
 			require.NoError(t, err)
 		})
 	}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gofrs/uuid"
 	"github.com/golang-jwt/jwt/v4"
@@ -16,10 +17,11 @@ type JWTService struct {
 	TokenName string
 }
 
-// Claims JWT claims
+// Claims JWT claims, session id is set in tokens of registered users' login sessions
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID *uuid.UUID
+	UserID    *uuid.UUID
+	SessionID string `json:",omitempty"`
 }
 
 func NewJwtService(secretKey string) *JWTService {
@@ -65,6 +67,19 @@ func (j *JWTService) CreateToken(userID *uuid.UUID, secretKey string) (string, e
 	return tokenString, nil
 }
 
+// CreateSessionToken Function create auth token of login session with userID, token expires with session
+func (j *JWTService) CreateSessionToken(userID *uuid.UUID, sessionID string, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+
+	return token.SignedString([]byte(j.secretKey))
+}
+
 // EnsureRandom Function generate random uuid
 func (j *JWTService) EnsureRandom() (res uuid.UUID) {
 	return uuid.Must(uuid.NewV4())
@@ -72,6 +87,27 @@ func (j *JWTService) EnsureRandom() (res uuid.UUID) {
 
 // GetUserID Function for get userID from auth token
 func (j *JWTService) GetUserID(tokenString string) (*uuid.UUID, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims.UserID, nil
+}
+
+// ParseToken Function for get claims from valid not expired auth token
+func (j *JWTService) ParseToken(tokenString string) (*Claims, error) {
+	return j.parse(tokenString)
+}
+
+// ParseExpiredToken Function for get claims from auth token with valid signature, expiration time isn't checked.
+// It's used to prove ownership of anonymous user id after cookie expiration
+func (j *JWTService) ParseExpiredToken(tokenString string) (*Claims, error) {
+	return j.parse(tokenString, jwt.WithoutClaimsValidation())
+}
+
+// parse Function for check signature of auth token and get its claims
+func (j *JWTService) parse(tokenString string, options ...jwt.ParserOption) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims,
 		func(t *jwt.Token) (interface{}, error) {
@@ -79,15 +115,14 @@ func (j *JWTService) GetUserID(tokenString string) (*uuid.UUID, error) {
 				return nil, fmt.Errorf("неизвестный алгоритм подписи: %v", t.Header["alg"])
 			}
 			return []byte(j.secretKey), nil
-		})
+		}, options...)
 	if err != nil {
 		return nil, err
 	}
 
 	if !token.Valid {
-		return nil, err
+		return nil, errors.New("невалидный токен")
 	}
 
-	fmt.Println("токен валидный", claims.UserID)
-	return claims.UserID, nil
+	return claims, nil
 }
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$") {
+		t.Errorf("HashPassword() = %v, want argon2id PHC string", hash)
+	}
+
+	other, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if hash == other {
+		t.Errorf("HashPassword() returns same hash for different salts")
+	}
+}
+
+func TestVerifyPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:     "Valid_password",
+			hash:     hash,
+			password: "secret",
+			want:     true,
+		},
+		{
+			name:     "Invalid_password",
+			hash:     hash,
+			password: "Secret",
+			want:     false,
+		},
+		{
+			name:     "Invalid_hash",
+			hash:     "$2a$10$invalid",
+			password: "secret",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyPassword(tt.hash, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyPassword() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
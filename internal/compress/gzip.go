@@ -37,6 +37,12 @@ func (c *compressWriter) WriteHeader(statusCode int) {
 	c.w.WriteHeader(statusCode)
 }
 
+// Flush send buffered compressed data to client, used for streaming responses
+func (c *compressWriter) Flush() {
+	_ = c.zw.Flush()
+	_ = http.NewResponseController(c.w).Flush()
+}
+
 // Close Function for stop gzip writer
 func (c *compressWriter) Close() error {
 	return c.zw.Close()
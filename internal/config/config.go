@@ -33,15 +33,30 @@ import (
 
 // ConfigENV env configuration params
 type ConfigENV struct {
-	ServerAddress     string `env:"SERVER_ADDRESS" json:"server_address,omitempty"`
-	GRPCServerAddress string `env:"GRPC_SERVER_ADDRESS" json:"grpc_server_address,omitempty"`
-	BaseURL           string `env:"BASE_URL" json:"base_url,omitempty"`
-	LogLevel          string `env:"LOG_LEVEL"`
-	FileStorage       string `env:"FILE_STORAGE_PATH" json:"file_storage_path,omitempty"`
-	DatabaseDsn       string `env:"DATABASE_DSN" json:"database_dsn,omitempty"`
-	SecretKey         string `env:"SECRET_KEY"`
-	TrustedSubnet     string `env:"TRUSTED_SUBNET"`
-	HTTPS             HTTPSConfig
+	ServerAddress        string        `env:"SERVER_ADDRESS" json:"server_address,omitempty"`
+	GRPCServerAddress    string        `env:"GRPC_SERVER_ADDRESS" json:"grpc_server_address,omitempty"`
+	BaseURL              string        `env:"BASE_URL" json:"base_url,omitempty"`
+	Domains              string        `env:"DOMAINS" json:"domains,omitempty"`
+	LogLevel             string        `env:"LOG_LEVEL"`
+	FileStorage          string        `env:"FILE_STORAGE_PATH" json:"file_storage_path,omitempty"`
+	DatabaseDsn          string        `env:"DATABASE_DSN" json:"database_dsn,omitempty"`
+	SecretKey            string        `env:"SECRET_KEY"`
+	TrustedSubnet        string        `env:"TRUSTED_SUBNET"`
+	IdempotencyTTL       time.Duration `env:"IDEMPOTENCY_TTL"`
+	AlwaysInterstitial   bool          `env:"ALWAYS_INTERSTITIAL" json:"always_interstitial,omitempty"`
+	GeoHeader            string        `env:"GEO_HEADER" json:"geo_header,omitempty"`
+	CanonicalizeURLs     bool          `env:"CANONICALIZE_URLS" json:"canonicalize_urls,omitempty"`
+	StripTrackingParams  bool          `env:"STRIP_TRACKING_PARAMS" json:"strip_tracking_params,omitempty"`
+	AllowedSchemes       string        `env:"ALLOWED_SCHEMES" json:"allowed_schemes,omitempty"`
+	URLPolicyFile        string        `env:"URL_POLICY_FILE" json:"url_policy_file,omitempty"`
+	URLPolicyReload      time.Duration `env:"URL_POLICY_RELOAD"`
+	LinkCheckInterval    time.Duration `env:"LINK_CHECK_INTERVAL"`
+	LinkCheckConcurrency int           `env:"LINK_CHECK_CONCURRENCY"`
+	LinkCheckHostDelay   time.Duration `env:"LINK_CHECK_HOST_DELAY"`
+	FetchPageMetadata    bool          `env:"FETCH_PAGE_METADATA" json:"fetch_page_metadata,omitempty"`
+	PageFetchWorkers     int           `env:"PAGE_FETCH_WORKERS"`
+	ClaimTokenMaxAge     time.Duration `env:"CLAIM_TOKEN_MAX_AGE"`
+	HTTPS                HTTPSConfig
 }
 
 // HTTPSConfig https config struct with key, pem
@@ -69,12 +84,27 @@ func ParseFlags() (*ConfigENV, error) {
 	flag.StringVar(&cfg.ServerAddress, "a", ":8080", "port to run server")
 	flag.StringVar(&cfg.GRPCServerAddress, "g", ":3200", "port to run grpc server")
 	flag.StringVar(&cfg.BaseURL, "b", "http://localhost:8080", "address to run server")
+	flag.StringVar(&cfg.Domains, "dm", "", "Comma-separated base URLs of additional short link domains")
 	flag.StringVar(&cfg.LogLevel, "l", "info", "log level")
 	flag.StringVar(&cfg.FileStorage, "f", "/tmp/shortener.txt", "file storage")
 	flag.StringVar(&cfg.DatabaseDsn, "d", "", "Database DSN")
 	flag.StringVar(&cfg.SecretKey, "sk", "sdfsdfsadfsdafasfsaf", "Secret key")
 	flag.BoolVar(&cfg.HTTPS.Enable, "s", false, "Enable HTTPS")
 	flag.StringVar(&cfg.TrustedSubnet, "t", "", "Trusted subnet")
+	flag.DurationVar(&cfg.IdempotencyTTL, "it", 24*time.Hour, "Idempotency keys storage window")
+	flag.BoolVar(&cfg.AlwaysInterstitial, "ai", false, "Show preview page instead of redirect for all links")
+	flag.StringVar(&cfg.GeoHeader, "gh", "X-Country-Code", "Header with client country code set by edge proxy")
+	flag.BoolVar(&cfg.CanonicalizeURLs, "cu", false, "Normalize original URLs before hashing and saving")
+	flag.BoolVar(&cfg.StripTrackingParams, "stp", false, "Remove tracking parameters from original URLs")
+	flag.StringVar(&cfg.AllowedSchemes, "as", "http,https", "Comma-separated schemes allowed for destination URLs")
+	flag.StringVar(&cfg.URLPolicyFile, "up", "", "JSON file with allow and deny lists of destination domains")
+	flag.DurationVar(&cfg.URLPolicyReload, "upr", 30*time.Second, "URL policy file reload check interval")
+	flag.DurationVar(&cfg.LinkCheckInterval, "lci", 0, "Link destinations check interval, 0 disables checker")
+	flag.IntVar(&cfg.LinkCheckConcurrency, "lcc", 4, "Max count of hosts checked concurrently")
+	flag.DurationVar(&cfg.LinkCheckHostDelay, "lcd", time.Second, "Delay between check requests to one host")
+	flag.BoolVar(&cfg.FetchPageMetadata, "fpm", false, "Fetch title, description and image of destination page on link creation")
+	flag.IntVar(&cfg.PageFetchWorkers, "pfw", 2, "Count of destination pages fetched concurrently")
+	flag.DurationVar(&cfg.ClaimTokenMaxAge, "cta", 30*24*time.Hour, "Max time after expiration of anonymous user token when its links can be claimed")
 	flag.Parse()
 
 	err := env.Parse(&cfg)
@@ -103,6 +133,7 @@ func ParseFlags() (*ConfigENV, error) {
 		cfg.ServerAddress = cmp.Or(cfg.ServerAddress, fCfg.ServerAddress)
 		cfg.GRPCServerAddress = cmp.Or(cfg.GRPCServerAddress, fCfg.GRPCServerAddress)
 		cfg.BaseURL = cmp.Or(cfg.BaseURL, fCfg.BaseURL)
+		cfg.Domains = cmp.Or(cfg.Domains, fCfg.Domains)
 		cfg.FileStorage = cmp.Or(cfg.FileStorage, fCfg.FileStorage)
 		cfg.DatabaseDsn = cmp.Or(cfg.DatabaseDsn, fCfg.DatabaseDsn)
 		cfg.HTTPS.Enable = cmp.Or(cfg.HTTPS.Enable, fCfg.HTTPS.Enable)
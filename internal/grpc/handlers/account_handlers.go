@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net"
+)
+
+// Register handler for creating account with login session, account keeps links of current anonymous user
+func (gh *GRPCHandlers) Register(ctx context.Context, req *shortener.RequestAccount) (*shortener.ResponseSession, error) {
+	session, err := gh.appService.Register(ctx, auth.UIDFromContext(ctx), models.AccountRequest{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		logger.Log.Debug("Ошибка регистрации пользователя", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return protoSession(session), nil
+}
+
+// Login handler for creating login session of account, links of current anonymous user are merged into account if claim is set
+func (gh *GRPCHandlers) Login(ctx context.Context, req *shortener.RequestAccount) (*shortener.ResponseSession, error) {
+	session, err := gh.appService.Login(ctx, auth.UIDFromContext(ctx), clientAddr(ctx), models.AccountRequest{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+		Claim:    req.GetClaim(),
+	})
+	if err != nil {
+		logger.Log.Debug("Ошибка входа пользователя", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return protoSession(session), nil
+}
+
+// Logout handler for finishing login session of auth token
+func (gh *GRPCHandlers) Logout(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return &empty.Empty{}, nil
+	}
+
+	if values := md.Get("auth"); len(values) > 0 {
+		if err := gh.appService.Logout(ctx, values[0]); err != nil {
+			logger.Log.Debug("Ошибка завершения сессии", zap.Error(err))
+			return nil, problem.GRPCError(err)
+		}
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// GetAccount handler for getting account of registered user
+func (gh *GRPCHandlers) GetAccount(ctx context.Context, req *empty.Empty) (*shortener.ResponseAccount, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	account, err := gh.appService.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseAccount{
+		UserId:    account.UserID.String(),
+		Email:     account.Email,
+		CreatedAt: timestamppb.New(account.CreatedAt),
+	}, nil
+}
+
+// ClaimURLs handler for merging links and collections of anonymous user into account by token of anonymous user
+func (gh *GRPCHandlers) ClaimURLs(ctx context.Context, req *shortener.RequestClaim) (*shortener.ResponseClaim, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	claimed, err := gh.appService.Claim(ctx, userID, req.GetToken())
+	if err != nil {
+		logger.Log.Debug("Ошибка переноса ссылок анонимного пользователя", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseClaim{Claimed: int64(claimed)}, nil
+}
+
+// clientAddr function returns IP of client for limiting password attempts
+func clientAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// protoSession function converts login session to gRPC message
+func protoSession(session models.AccountSession) *shortener.ResponseSession {
+	return &shortener.ResponseSession{
+		Token:     session.Token,
+		UserId:    session.Account.UserID.String(),
+		Email:     session.Account.Email,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		Claimed:   int64(session.Claimed),
+	}
+}
@@ -0,0 +1,169 @@
+package grpc
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListCollections handler for getting user's collections with count of their links
+func (gh *GRPCHandlers) ListCollections(ctx context.Context, req *empty.Empty) (*shortener.ResponseListCollections, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	collections, err := gh.appService.ListCollections(ctx, userID)
+	if err != nil {
+		logger.Log.Debug("Ошибка получения коллекций пользователя", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	response := &shortener.ResponseListCollections{Items: make([]*shortener.Collection, 0, len(collections))}
+	for _, collection := range collections {
+		response.Items = append(response.Items, protoCollection(collection))
+	}
+
+	return response, nil
+}
+
+// CreateCollection handler for creating user's collection
+func (gh *GRPCHandlers) CreateCollection(ctx context.Context, req *shortener.RequestCreateCollection) (*shortener.ResponseCollection, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	collection, err := gh.appService.CreateCollection(ctx, userID, models.CollectionRequest{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		logger.Log.Debug("Ошибка создания коллекции", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseCollection{Collection: protoCollection(collection)}, nil
+}
+
+// GetCollection handler for getting user's collection by id
+func (gh *GRPCHandlers) GetCollection(ctx context.Context, req *shortener.RequestCollection) (*shortener.ResponseCollection, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	collection, err := gh.appService.GetCollection(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseCollection{Collection: protoCollection(collection)}, nil
+}
+
+// UpdateCollection handler for renaming user's collection, omitted fields aren't changed
+func (gh *GRPCHandlers) UpdateCollection(ctx context.Context, req *shortener.RequestUpdateCollection) (*shortener.ResponseCollection, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	collection, err := gh.appService.UpdateCollection(ctx, userID, req.GetId(), models.CollectionUpdateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		logger.Log.Debug("Ошибка изменения коллекции", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseCollection{Collection: protoCollection(collection)}, nil
+}
+
+// DeleteCollection handler for deleting user's collection, its links are kept without collection
+func (gh *GRPCHandlers) DeleteCollection(ctx context.Context, req *shortener.RequestCollection) (*empty.Empty, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	if err := gh.appService.DeleteCollection(ctx, userID, req.GetId()); err != nil {
+		logger.Log.Debug("Ошибка удаления коллекции", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// GetCollectionURLs handler for getting not deleted links of user's collection, newest links are first
+func (gh *GRPCHandlers) GetCollectionURLs(ctx context.Context, req *shortener.RequestCollection) (*shortener.ResponseCollectionURLs, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	links, err := gh.appService.CollectionLinks(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	response := &shortener.ResponseCollectionURLs{Items: make([]*shortener.CollectionLink, 0, len(links))}
+	for _, link := range links {
+		response.Items = append(response.Items, &shortener.CollectionLink{
+			Id:          link.ID,
+			ShortUrl:    link.ShortURL,
+			OriginalUrl: link.OriginalURL,
+			Title:       link.Title,
+		})
+	}
+
+	return response, nil
+}
+
+// AddCollectionURLs handler for moving user's links to collection
+func (gh *GRPCHandlers) AddCollectionURLs(ctx context.Context, req *shortener.RequestCollectionURLs) (*empty.Empty, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	if err := gh.appService.AddCollectionLinks(ctx, userID, req.GetId(), req.GetLinkIds()); err != nil {
+		logger.Log.Debug("Ошибка добавления ссылок в коллекцию", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// DeleteCollectionURLs handler for deleting all links of user's collection, collection is kept
+func (gh *GRPCHandlers) DeleteCollectionURLs(ctx context.Context, req *shortener.RequestCollection) (*empty.Empty, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	if err := gh.appService.DeleteCollectionLinks(ctx, userID, req.GetId()); err != nil {
+		logger.Log.Debug("Ошибка удаления ссылок коллекции", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// protoCollection function converts collection to gRPC message
+func protoCollection(collection models.Collection) *shortener.Collection {
+	return &shortener.Collection{
+		Id:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		Links:       int64(collection.Links),
+		CreatedAt:   timestamppb.New(collection.CreatedAt),
+	}
+}
@@ -5,15 +5,15 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/romanp1989/go-shortener/internal/auth"
 	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
 )
 
 // DeleteURLs function for delete urls
 func (gh *GRPCHandlers) DeleteURLs(ctx context.Context, req *shortener.RequestDeleteURLs) (*empty.Empty, error) {
 	userID := auth.UIDFromContext(ctx)
 	if userID == nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
 	}
 
 	go gh.appService.DeleteURLs(userID, req.GetShortUrls())
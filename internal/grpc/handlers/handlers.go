@@ -2,17 +2,15 @@ package grpc
 
 import (
 	"context"
-	"errors"
 	"github.com/romanp1989/go-shortener/internal/auth"
 	proto "github.com/romanp1989/go-shortener/internal/grpc/proto"
 	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
 	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
 	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
-	"github.com/romanp1989/go-shortener/internal/storage"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"net/url"
+	"net/http"
 )
 
 // Handlers handlers
@@ -31,24 +29,13 @@ func New(appService *shortener_service.ShortenerService) *GRPCHandlers {
 func (gh *GRPCHandlers) Encode(ctx context.Context, req *shortener.RequestEncode) (*shortener.ResponseEncode, error) {
 	userID := auth.UIDFromContext(ctx)
 	if userID == nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
-	}
-
-	if _, err := url.ParseRequestURI(req.GetUrl()); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
 	}
 
 	shortURL, err := gh.appService.Encode(ctx, req.GetUrl())
-
 	if err != nil {
 		logger.Log.Debug("Ошибка добавления данных", zap.Error(err))
-
-		var errConflict *storage.URLConflictError
-		if errors.As(err, &errConflict) {
-			return nil, status.Error(codes.FailedPrecondition, err.Error())
-		} else {
-			return nil, status.Error(codes.Internal, err.Error())
-		}
+		return nil, conflictError(err, shortURL)
 	}
 
 	return &shortener.ResponseEncode{ShortUrl: shortURL}, nil
@@ -59,25 +46,42 @@ func (gh *GRPCHandlers) Decode(ctx context.Context, req *shortener.RequestDecode
 	id := req.GetUrl()
 
 	if id == "" {
-		return nil, status.Error(codes.InvalidArgument, "url is required")
+		return nil, problem.GRPCStatus(problem.New(http.StatusBadRequest, problem.CodeBadRequest, "url is required")).Err()
+	}
+	// одинаковый код на разных доменах ведет на разные ссылки, домен берется из :authority как Host в REST
+	id = gh.appService.HostLinkKey(authority(ctx), id)
+
+	// пароль вводится только на странице ссылки, через gRPC защищенная ссылка не открывается
+	res, err := gh.appService.Resolve(ctx, id)
+	if err != nil {
+		logger.Log.Debug("error resolve url", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+	if res.Fallback != "" {
+		return &shortener.ResponseDecode{Result: res.Fallback}, nil
+	}
+	if res.Protected {
+		return nil, problem.GRPCError(shortener_service.ErrLinkPasswordRequired)
 	}
 
 	fullURL, err := gh.appService.Decode(id)
 	if err != nil {
-		var errURLDeleted *storage.AlreadyDeleted
-		if errors.As(err, &errURLDeleted) {
-			return nil, status.Error(codes.FailedPrecondition, err.Error())
-		}
 		logger.Log.Debug("error get url response", zap.Error(err))
-
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, problem.GRPCError(err)
 	}
 
 	if fullURL != "" {
+		if len(res.Rules) > 0 || len(res.Variants) > 0 || res.UTM != nil {
+			target := gh.appService.Destination(res, gh.clientInfo(ctx))
+			if target.Variant != "" {
+				gh.appService.CountVariantClick(ctx, res.Link.ID, target.Variant)
+			}
+			fullURL = target.URL
+		}
 		return &shortener.ResponseDecode{Result: fullURL}, nil
 	}
 
-	return nil, status.Error(codes.NotFound, "url not found")
+	return nil, problem.GRPCStatus(problem.New(http.StatusNotFound, problem.CodeNotFound, "url not found")).Err()
 }
 
 // Shorten handler for creating a shortened URL based on the original one
@@ -86,51 +90,76 @@ func (gh *GRPCHandlers) Shorten(ctx context.Context, req *shortener.RequestShort
 
 	userID := auth.UIDFromContext(ctx)
 	if userID == nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
 	}
 
 	shortURL, err := gh.appService.Shorten(ctx, req.GetUrl(), userID)
 	if err != nil {
 		logger.Log.Debug("Ошибка добавления данных", zap.Error(err))
-
-		var errConflict *storage.URLConflictError
-		if errors.As(err, &errConflict) {
-			return nil, status.Error(codes.FailedPrecondition, err.Error())
-		} else {
-			return nil, status.Error(codes.Internal, err.Error())
-		}
+		return nil, conflictError(err, shortURL)
 	}
 
 	return &shortener.ResponseShorten{Result: shortURL}, nil
 }
 
-// SaveBatch handler for creating a shortened URL based on the original one
+// SaveBatch handler for creating a shortened URL based on the original one.
+// Every item gets its own status, valid items are saved even if other items are invalid
 func (gh *GRPCHandlers) SaveBatch(ctx context.Context, req *shortener.RequestSaveBatch) (*shortener.ResponseSaveBatch, error) {
-	var err error
-	var shortURL string
-
 	userID := auth.UIDFromContext(ctx)
 	if userID == nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
 	}
 
-	response := &shortener.ResponseSaveBatch{Items: make([]*shortener.Item, len(req.GetItems()))}
+	if len(req.GetItems()) == 0 {
+		return nil, problem.GRPCStatus(problem.New(http.StatusBadRequest, problem.CodeEmptyBatch, "items are required")).Err()
+	}
 
+	batchReq := make([]models.BatchShortenRequest, 0, len(req.GetItems()))
 	for _, value := range req.GetItems() {
-		if _, err = url.ParseRequestURI(value.GetUrl()); err != nil {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
+		batchReq = append(batchReq, models.BatchShortenRequest{
+			CorrelationID: value.GetCorrelationId(),
+			OriginalURL:   value.GetUrl(),
+		})
+	}
+
+	resp, err := gh.appService.SaveBatch(ctx, batchReq, userID)
+	if err != nil {
+		logger.Log.Debug("error urls save", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
 
-		shortURL, err = gh.appService.Shorten(ctx, value.GetUrl(), userID)
-		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
+	response := &shortener.ResponseSaveBatch{Items: make([]*shortener.Item, 0, len(resp))}
+	saved := false
+	for _, item := range resp {
+		if item.Status != models.BatchStatusInvalid {
+			saved = true
 		}
 
 		response.Items = append(response.Items, &shortener.Item{
-			CorrelationId: value.GetCorrelationId(),
-			Url:           shortURL,
+			CorrelationId: item.CorrelationID,
+			Url:           item.ShortURL,
+			Status:        item.Status,
+			Error:         item.Error,
 		})
 	}
 
+	// результаты по элементам передаются в деталях ошибки, как тело ответа с кодом 400 в REST API
+	if !saved {
+		st := problem.GRPCStatus(problem.New(http.StatusBadRequest, problem.CodeBatchInvalid, "all urls are invalid"))
+		if detailed, err := st.WithDetails(response); err == nil {
+			st = detailed
+		}
+		return nil, st.Err()
+	}
+
 	return response, nil
 }
+
+// conflictError function maps save error to gRPC error, short URL of existing URL is passed in details metadata
+func conflictError(err error, shortURL string) error {
+	p := problem.From(err)
+	if p.Code == problem.CodeURLConflict {
+		p.With("result", shortURL)
+	}
+	return problem.GRPCStatus(p).Err()
+}
@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"context"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net/http"
+)
+
+// GetLink handler for inspecting short link without redirect, metadata is returned for owner only
+func (gh *GRPCHandlers) GetLink(ctx context.Context, req *shortener.RequestGetLink) (*shortener.ResponseGetLink, error) {
+	if req.GetId() == "" {
+		return nil, problem.GRPCStatus(problem.New(http.StatusBadRequest, problem.CodeBadRequest, "id is required")).Err()
+	}
+
+	info, err := gh.appService.GetLinkInfo(ctx, auth.UIDFromContext(ctx), req.GetId())
+	if err != nil {
+		logger.Log.Debug("Ошибка получения информации о ссылке", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	response := &shortener.ResponseGetLink{
+		Id:                info.ID,
+		ShortUrl:          info.ShortURL,
+		OriginalUrl:       info.OriginalURL,
+		CreatedAt:         timestamppb.New(info.CreatedAt),
+		State:             info.State,
+		Clicks:            info.Clicks,
+		PasswordProtected: info.PasswordProtected,
+		MaxClicks:         info.MaxClicks,
+	}
+	if info.ActiveFrom != nil {
+		response.ActiveFrom = timestamppb.New(*info.ActiveFrom)
+	}
+	if info.ActiveUntil != nil {
+		response.ActiveUntil = timestamppb.New(*info.ActiveUntil)
+	}
+	for _, variant := range info.Variants {
+		response.Variants = append(response.Variants, &shortener.Variant{
+			Id:     variant.ID,
+			Url:    variant.URL,
+			Weight: int32(variant.Weight),
+			Clicks: variant.Clicks,
+		})
+	}
+	if info.Metadata != nil {
+		response.Metadata = &shortener.LinkMetadata{
+			Title: info.Metadata.Title,
+			Notes: info.Metadata.Notes,
+			Tags:  info.Metadata.Tags,
+		}
+	}
+
+	return response, nil
+}
+
+// GetLinkRules handler for getting redirect rules of user's link
+func (gh *GRPCHandlers) GetLinkRules(ctx context.Context, req *shortener.RequestGetLink) (*shortener.ResponseLinkRules, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	link, err := gh.appService.GetLink(ctx, userID, req.GetId())
+	if err != nil {
+		logger.Log.Debug("Ошибка получения правил перенаправления", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseLinkRules{Rules: protoRules(link.Rules)}, nil
+}
+
+// SetLinkRules handler for replacing redirect rules of user's link, empty list removes rules
+func (gh *GRPCHandlers) SetLinkRules(ctx context.Context, req *shortener.RequestSetLinkRules) (*shortener.ResponseLinkRules, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	rules := make([]models.RedirectRule, 0, len(req.GetRules()))
+	for _, rule := range req.GetRules() {
+		rules = append(rules, models.RedirectRule{
+			Device:   rule.GetDevice(),
+			Language: rule.GetLanguage(),
+			Country:  rule.GetCountry(),
+			URL:      rule.GetUrl(),
+		})
+	}
+
+	link, err := gh.appService.UpdateLink(ctx, userID, req.GetId(), models.LinkUpdateRequest{Rules: &rules})
+	if err != nil {
+		logger.Log.Debug("Ошибка сохранения правил перенаправления", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseLinkRules{Rules: protoRules(link.Rules)}, nil
+}
+
+// GetQRCode handler for QR code image with full short URL of existing not deleted link
+func (gh *GRPCHandlers) GetQRCode(ctx context.Context, req *shortener.RequestQRCode) (*shortener.ResponseQRCode, error) {
+	if req.GetId() == "" {
+		return nil, problem.GRPCStatus(problem.New(http.StatusBadRequest, problem.CodeBadRequest, "id is required")).Err()
+	}
+
+	opts := models.QROptions{
+		Size:   int(req.GetSize()),
+		Format: req.GetFormat(),
+		Level:  req.GetLevel(),
+	}
+	if req.Margin != nil {
+		margin := int(req.GetMargin())
+		opts.Margin = &margin
+	}
+
+	code, err := gh.appService.QRCode(ctx, req.GetId(), opts)
+	if err != nil {
+		logger.Log.Debug("Ошибка создания QR-кода", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseQRCode{Content: code.Content, ContentType: code.ContentType}, nil
+}
+
+// protoRules function converts redirect rules to gRPC messages
+func protoRules(rules []models.RedirectRule) []*shortener.RedirectRule {
+	result := make([]*shortener.RedirectRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, &shortener.RedirectRule{
+			Device:   rule.Device,
+			Language: rule.Language,
+			Country:  rule.Country,
+			Url:      rule.URL,
+		})
+	}
+	return result
+}
+
+// clientInfo function returns client attributes for redirect rules from request metadata
+func (gh *GRPCHandlers) clientInfo(ctx context.Context) models.ClientInfo {
+	var client models.ClientInfo
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return client
+	}
+	if values := md.Get("user-agent"); len(values) > 0 {
+		client.UserAgent = values[0]
+	}
+	if values := md.Get("accept-language"); len(values) > 0 {
+		client.AcceptLanguage = values[0]
+	}
+	if gh.appService.Cfg.GeoHeader != "" {
+		if values := md.Get(gh.appService.Cfg.GeoHeader); len(values) > 0 {
+			client.Country = values[0]
+		}
+	}
+	return client
+}
+
+// authority function returns host of request from :authority metadata, empty string is returned if it isn't set
+func authority(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(":authority"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/romanp1989/go-shortener/internal/auth"
 	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
 	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,7 +17,7 @@ import (
 func (gh *GRPCHandlers) GetUserURL(ctx context.Context, req *empty.Empty) (*shortener.ResponseGetUserURL, error) {
 	userID := auth.UIDFromContext(ctx)
 	if userID == nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
 	}
 
 	allUrls, err := gh.appService.GetURLs(ctx, userID)
@@ -34,3 +36,31 @@ func (gh *GRPCHandlers) GetUserURL(ctx context.Context, req *empty.Empty) (*shor
 
 	return response, nil
 }
+
+// SearchURLs handler for full-text search in user's URLs
+func (gh *GRPCHandlers) SearchURLs(ctx context.Context, req *shortener.RequestSearchURLs) (*shortener.ResponseSearchURLs, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	results, err := gh.appService.SearchURLs(ctx, userID, req.GetQuery())
+	if err != nil {
+		logger.Log.Debug("Ошибка при поиске urls пользователя", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	response := &shortener.ResponseSearchURLs{Items: make([]*shortener.SearchItem, 0, len(results))}
+	for _, result := range results {
+		response.Items = append(response.Items, &shortener.SearchItem{
+			ShortUrl:    result.ShortURL,
+			OriginalUrl: result.OriginalURL,
+			Title:       result.Title,
+			Notes:       result.Notes,
+			Tags:        result.Tags,
+			Rank:        result.Rank,
+		})
+	}
+
+	return response, nil
+}
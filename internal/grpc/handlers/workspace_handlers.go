@@ -0,0 +1,202 @@
+package grpc
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListWorkspaces handler for getting user's workspaces with user's role
+func (gh *GRPCHandlers) ListWorkspaces(ctx context.Context, req *empty.Empty) (*shortener.ResponseListWorkspaces, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	workspaces, err := gh.appService.ListWorkspaces(ctx, userID)
+	if err != nil {
+		logger.Log.Debug("Ошибка получения рабочих пространств пользователя", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	response := &shortener.ResponseListWorkspaces{Items: make([]*shortener.Workspace, 0, len(workspaces))}
+	for _, workspace := range workspaces {
+		response.Items = append(response.Items, protoWorkspace(workspace))
+	}
+
+	return response, nil
+}
+
+// CreateWorkspace handler for creating workspace, user becomes its owner
+func (gh *GRPCHandlers) CreateWorkspace(ctx context.Context, req *shortener.RequestCreateWorkspace) (*shortener.ResponseWorkspace, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	workspace, err := gh.appService.CreateWorkspace(ctx, userID, models.WorkspaceRequest{Name: req.GetName()})
+	if err != nil {
+		logger.Log.Debug("Ошибка создания рабочего пространства", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseWorkspace{Workspace: protoWorkspace(workspace)}, nil
+}
+
+// GetWorkspace handler for getting workspace by id with user's role
+func (gh *GRPCHandlers) GetWorkspace(ctx context.Context, req *shortener.RequestWorkspace) (*shortener.ResponseWorkspace, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	workspace, err := gh.appService.GetWorkspace(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseWorkspace{Workspace: protoWorkspace(workspace)}, nil
+}
+
+// UpdateWorkspace handler for renaming workspace by its owner
+func (gh *GRPCHandlers) UpdateWorkspace(ctx context.Context, req *shortener.RequestUpdateWorkspace) (*shortener.ResponseWorkspace, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	workspace, err := gh.appService.UpdateWorkspace(ctx, userID, req.GetId(), models.WorkspaceRequest{Name: req.GetName()})
+	if err != nil {
+		logger.Log.Debug("Ошибка изменения рабочего пространства", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseWorkspace{Workspace: protoWorkspace(workspace)}, nil
+}
+
+// DeleteWorkspace handler for deleting workspace by its owner, links of workspace are returned to their creators
+func (gh *GRPCHandlers) DeleteWorkspace(ctx context.Context, req *shortener.RequestWorkspace) (*empty.Empty, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	if err := gh.appService.DeleteWorkspace(ctx, userID, req.GetId()); err != nil {
+		logger.Log.Debug("Ошибка удаления рабочего пространства", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// GetWorkspaceMembers handler for getting members of workspace with their roles
+func (gh *GRPCHandlers) GetWorkspaceMembers(ctx context.Context, req *shortener.RequestWorkspace) (*shortener.ResponseWorkspaceMembers, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	members, err := gh.appService.WorkspaceMembers(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	response := &shortener.ResponseWorkspaceMembers{Items: make([]*shortener.WorkspaceMember, 0, len(members))}
+	for _, member := range members {
+		response.Items = append(response.Items, protoWorkspaceMember(member))
+	}
+
+	return response, nil
+}
+
+// SetWorkspaceMember handler for adding member to workspace or changing member's role by workspace owner
+func (gh *GRPCHandlers) SetWorkspaceMember(ctx context.Context, req *shortener.RequestWorkspaceMember) (*shortener.ResponseWorkspaceMember, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	memberID, err := uuid.FromString(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "некорректный идентификатор пользователя")
+	}
+
+	member, err := gh.appService.SetWorkspaceMember(ctx, userID, req.GetId(), &memberID, req.GetRole())
+	if err != nil {
+		logger.Log.Debug("Ошибка изменения участника рабочего пространства", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &shortener.ResponseWorkspaceMember{Member: protoWorkspaceMember(member)}, nil
+}
+
+// DeleteWorkspaceMember handler for removing member from workspace by its owner, any member can leave workspace
+func (gh *GRPCHandlers) DeleteWorkspaceMember(ctx context.Context, req *shortener.RequestWorkspaceMember) (*empty.Empty, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	memberID, err := uuid.FromString(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "некорректный идентификатор пользователя")
+	}
+
+	if err = gh.appService.RemoveWorkspaceMember(ctx, userID, req.GetId(), &memberID); err != nil {
+		logger.Log.Debug("Ошибка удаления участника рабочего пространства", zap.Error(err))
+		return nil, problem.GRPCError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// GetWorkspaceURLs handler for getting all URLs of user's workspace
+func (gh *GRPCHandlers) GetWorkspaceURLs(ctx context.Context, req *shortener.RequestWorkspace) (*shortener.ResponseGetUserURL, error) {
+	userID := auth.UIDFromContext(ctx)
+	if userID == nil {
+		return nil, problem.GRPCError(shortener_service.ErrUnauthorized)
+	}
+
+	urls, err := gh.appService.GetWorkspaceURLs(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	response := &shortener.ResponseGetUserURL{Items: make([]*shortener.UserURL, 0, len(urls))}
+	for _, url := range urls {
+		response.Items = append(response.Items, &shortener.UserURL{
+			ShortUrl:    url.ShortURL,
+			OriginalUrl: url.OriginalURL,
+		})
+	}
+
+	return response, nil
+}
+
+// protoWorkspace function converts workspace to gRPC message
+func protoWorkspace(workspace models.Workspace) *shortener.Workspace {
+	return &shortener.Workspace{
+		Id:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      workspace.Role,
+		CreatedAt: timestamppb.New(workspace.CreatedAt),
+	}
+}
+
+// protoWorkspaceMember function converts workspace member to gRPC message
+func protoWorkspaceMember(member models.WorkspaceMember) *shortener.WorkspaceMember {
+	return &shortener.WorkspaceMember{
+		UserId: member.UserID.String(),
+		Role:   member.Role,
+	}
+}
@@ -2,28 +2,46 @@ package interceptors
 
 import (
 	"context"
+	"github.com/gofrs/uuid"
 	"github.com/romanp1989/go-shortener/internal/auth"
+	proto "github.com/romanp1989/go-shortener/internal/grpc/proto"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-// AuthInterceptor GRPC interceptor for authentication
-func AuthInterceptor(jwt *auth.JWTService) grpc.UnaryServerInterceptor {
+// publicMethods methods available without auth token, user is set if token is valid
+var publicMethods = map[string]bool{
+	proto.Internal_Register_FullMethodName: true,
+	proto.Internal_Login_FullMethodName:    true,
+}
+
+// AuthInterceptor GRPC interceptor for authentication, token of login session is valid until session is finished
+func AuthInterceptor(jwt *auth.JWTService, appService *shortener_service.ShortenerService) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
+			if publicMethods[info.FullMethod] {
+				return handler(ctx, req)
+			}
 			return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
 		}
 
 		authHeaders := md.Get("auth")
 		if len(authHeaders) == 0 {
+			if publicMethods[info.FullMethod] {
+				return handler(ctx, req)
+			}
 			return nil, status.Errorf(codes.Unauthenticated, "missing auth header")
 		}
 
-		uid, err := jwt.GetUserID(authHeaders[0])
-		if uid == nil || err != nil {
+		uid := tokenUser(ctx, jwt, appService, authHeaders[0])
+		if uid == nil {
+			if publicMethods[info.FullMethod] {
+				return handler(ctx, req)
+			}
 			return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 		}
 
@@ -34,3 +52,23 @@ func AuthInterceptor(jwt *auth.JWTService) grpc.UnaryServerInterceptor {
 	}
 
 }
+
+// tokenUser function returns user id of valid auth token, token of login session must have active session,
+// token without session isn't accepted for registered user
+func tokenUser(ctx context.Context, jwt *auth.JWTService, appService *shortener_service.ShortenerService, token string) *uuid.UUID {
+	claims, err := jwt.ParseToken(token)
+	if err != nil || claims.UserID == nil {
+		return nil
+	}
+
+	if appService == nil {
+		if claims.SessionID != "" {
+			return nil
+		}
+		return claims.UserID
+	}
+	if appService.CheckSession(ctx, claims) != nil {
+		return nil
+	}
+	return claims.UserID
+}
@@ -0,0 +1,56 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	proto "github.com/romanp1989/go-shortener/internal/grpc/proto"
+	"github.com/romanp1989/go-shortener/internal/models"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestAuthInterceptor_SessionToken(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080", SecretKey: "verycomplexsecretkey"}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	interceptor := AuthInterceptor(auth.NewJwtService(cfg.SecretKey), appService)
+
+	session, err := appService.Register(ctx, nil, models.AccountRequest{Email: "user@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	var calledBy *uuid.UUID
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calledBy = auth.UIDFromContext(ctx)
+		return nil, nil
+	}
+	call := func(method string) error {
+		md := metadata.Pairs("auth", session.Token)
+		_, err := interceptor(metadata.NewIncomingContext(ctx, md), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	require.NoError(t, call(proto.Internal_Shorten_FullMethodName))
+	require.NotNil(t, calledBy)
+	assert.Equal(t, *session.Account.UserID, *calledBy)
+
+	// после выхода токен сессии больше не принимается
+	require.NoError(t, appService.Logout(ctx, session.Token))
+	calledBy = nil
+	err = call(proto.Internal_Shorten_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Nil(t, calledBy)
+
+	// публичный метод выполняется без пользователя
+	require.NoError(t, call(proto.Internal_Login_FullMethodName))
+	assert.Nil(t, calledBy)
+}
@@ -0,0 +1,126 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	proto "github.com/romanp1989/go-shortener/internal/grpc/proto"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// IdempotencyKeyMetadata metadata key with client idempotency key
+const IdempotencyKeyMetadata = "idempotency-key"
+
+// idempotentMethods methods which responses are saved for idempotency key
+var idempotentMethods = map[string]bool{
+	proto.Internal_Encode_FullMethodName:    true,
+	proto.Internal_Shorten_FullMethodName:   true,
+	proto.Internal_SaveBatch_FullMethodName: true,
+}
+
+// statusMessageName saved gRPC error is stored as google.rpc.Status message
+const statusMessageName = "google.rpc.Status"
+
+// IdempotencyInterceptor GRPC interceptor for replay of saved response on retries with the same idempotency-key metadata.
+// Must be used after authentication interceptor, internal errors aren't saved so they can be retried
+func IdempotencyInterceptor(appService *shortener_service.ShortenerService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !idempotentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		keys := md.Get(IdempotencyKeyMetadata)
+		userID := auth.UIDFromContext(ctx)
+		if len(keys) == 0 || keys[0] == "" || userID == nil {
+			return handler(ctx, req)
+		}
+		key := keys[0]
+
+		message, ok := req.(protobuf.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		body, err := protobuf.MarshalOptions{Deterministic: true}.Marshal(message)
+		if err != nil {
+			return nil, problem.GRPCError(err)
+		}
+
+		requestHash := shortener_service.IdempotencyHash([]byte(info.FullMethod), body)
+		record, err := appService.LookupIdempotent(ctx, userID, key, requestHash)
+		if err != nil {
+			logger.Log.Debug("Ошибка проверки ключа идемпотентности", zap.Error(err))
+			return nil, problem.GRPCError(err)
+		}
+
+		if record != nil {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+			return replay(record)
+		}
+		defer appService.ReleaseIdempotent(userID, key)
+
+		resp, respErr := handler(ctx, req)
+
+		saved := models.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+		}
+		if respErr != nil {
+			st := status.Convert(respErr)
+			if st.Code() == codes.Internal || st.Code() == codes.Unknown || st.Code() == codes.Unavailable {
+				return resp, respErr
+			}
+			saved.StatusCode, saved.ContentType = int(st.Code()), statusMessageName
+			saved.Body, err = protobuf.Marshal(st.Proto())
+		} else if message, ok := resp.(protobuf.Message); ok {
+			saved.StatusCode, saved.ContentType = int(codes.OK), string(message.ProtoReflect().Descriptor().FullName())
+			saved.Body, err = protobuf.Marshal(message)
+		} else {
+			return resp, respErr
+		}
+
+		if err == nil {
+			err = appService.SaveIdempotent(ctx, saved)
+		}
+		if err != nil {
+			logger.Log.Debug("Ошибка сохранения ответа по ключу идемпотентности", zap.Error(err))
+		}
+
+		return resp, respErr
+	}
+}
+
+// replay function restores saved response or gRPC error
+func replay(record *models.IdempotencyRecord) (interface{}, error) {
+	if record.ContentType == statusMessageName {
+		st := &spb.Status{}
+		if err := protobuf.Unmarshal(record.Body, st); err != nil {
+			return nil, problem.GRPCError(err)
+		}
+		return nil, status.FromProto(st).Err()
+	}
+
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(record.ContentType))
+	if err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	message := messageType.New().Interface()
+	if err := protobuf.Unmarshal(record.Body, message); err != nil {
+		return nil, problem.GRPCError(err)
+	}
+
+	return message, nil
+}
@@ -0,0 +1,126 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	proto "github.com/romanp1989/go-shortener/internal/grpc/proto"
+	"github.com/romanp1989/go-shortener/internal/grpc/proto/shortener"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"testing"
+)
+
+// idempotentCall calls Shorten through idempotency interceptor with idempotency key of user
+func idempotentCall(interceptor grpc.UnaryServerInterceptor, userID uuid.UUID, url string, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadata, "key"))
+	info := &grpc.UnaryServerInfo{FullMethod: proto.Internal_Shorten_FullMethodName}
+
+	return interceptor(auth.Context(ctx, userID), &shortener.RequestShorten{Url: url}, info, handler)
+}
+
+func newIdempotencyInterceptor() grpc.UnaryServerInterceptor {
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	return IdempotencyInterceptor(shortener_service.NewShortenerService(&storageURLs, &config.ConfigENV{}))
+}
+
+func TestIdempotencyInterceptor_Replay(t *testing.T) {
+	interceptor := newIdempotencyInterceptor()
+	userID := uuid.Must(uuid.NewV4())
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &shortener.ResponseShorten{Result: "http://localhost:8080/6YGS4ZUF"}, nil
+	}
+
+	first, err := idempotentCall(interceptor, userID, "https://ya.ru", handler)
+	require.NoError(t, err)
+	replayed, err := idempotentCall(interceptor, userID, "https://ya.ru", handler)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+	assert.True(t, protobuf.Equal(first.(protobuf.Message), replayed.(protobuf.Message)))
+
+	// ключ другого пользователя не пересекается с ключом первого
+	_, err = idempotentCall(interceptor, uuid.Must(uuid.NewV4()), "https://ya.ru", handler)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyInterceptor_ReplayError(t *testing.T) {
+	interceptor := newIdempotencyInterceptor()
+	userID := uuid.Must(uuid.NewV4())
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, status.Error(codes.InvalidArgument, "некорректный URL")
+	}
+
+	_, err := idempotentCall(interceptor, userID, "ya", handler)
+	require.Error(t, err)
+	_, replayed := idempotentCall(interceptor, userID, "ya", handler)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, codes.InvalidArgument, status.Code(replayed))
+	assert.Equal(t, status.Convert(err).Message(), status.Convert(replayed).Message())
+}
+
+func TestIdempotencyInterceptor_RequestMismatch(t *testing.T) {
+	interceptor := newIdempotencyInterceptor()
+	userID := uuid.Must(uuid.NewV4())
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &shortener.ResponseShorten{Result: "http://localhost:8080/6YGS4ZUF"}, nil
+	}
+
+	_, err := idempotentCall(interceptor, userID, "https://ya.ru", handler)
+	require.NoError(t, err)
+	_, err = idempotentCall(interceptor, userID, "https://dzen.ru", handler)
+
+	assert.Equal(t, 1, calls)
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, problem.CodeIdempotencyKeyReused, info.Reason)
+}
+
+func TestIdempotencyInterceptor_InternalErrorNotSaved(t *testing.T) {
+	interceptor := newIdempotencyInterceptor()
+
+	for _, code := range []codes.Code{codes.Internal, codes.Unknown, codes.Unavailable} {
+		t.Run(code.String(), func(t *testing.T) {
+			userID := uuid.Must(uuid.NewV4())
+			url := "https://ya.ru"
+			_, err := idempotentCall(interceptor, userID, url, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(code, "ошибка хранилища")
+			})
+			assert.Equal(t, code, status.Code(err))
+
+			// ключ освобожден, повтор запроса выполняется заново
+			calls := 0
+			resp, err := idempotentCall(interceptor, userID, url, func(ctx context.Context, req interface{}) (interface{}, error) {
+				calls++
+				return &shortener.ResponseShorten{Result: "http://localhost:8080/6YGS4ZUF"}, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 1, calls)
+			assert.Equal(t, "http://localhost:8080/6YGS4ZUF", resp.(*shortener.ResponseShorten).GetResult())
+		})
+	}
+}
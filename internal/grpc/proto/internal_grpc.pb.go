@@ -21,14 +21,41 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Internal_Encode_FullMethodName     = "/proto.Internal/Encode"
-	Internal_Decode_FullMethodName     = "/proto.Internal/Decode"
-	Internal_Shorten_FullMethodName    = "/proto.Internal/Shorten"
-	Internal_SaveBatch_FullMethodName  = "/proto.Internal/SaveBatch"
-	Internal_GetUserURL_FullMethodName = "/proto.Internal/GetUserURL"
-	Internal_DeleteURLs_FullMethodName = "/proto.Internal/DeleteURLs"
-	Internal_GetStats_FullMethodName   = "/proto.Internal/GetStats"
-	Internal_PingDB_FullMethodName     = "/proto.Internal/PingDB"
+	Internal_Encode_FullMethodName                = "/proto.Internal/Encode"
+	Internal_Decode_FullMethodName                = "/proto.Internal/Decode"
+	Internal_Shorten_FullMethodName               = "/proto.Internal/Shorten"
+	Internal_SaveBatch_FullMethodName             = "/proto.Internal/SaveBatch"
+	Internal_GetUserURL_FullMethodName            = "/proto.Internal/GetUserURL"
+	Internal_SearchURLs_FullMethodName            = "/proto.Internal/SearchURLs"
+	Internal_GetLink_FullMethodName               = "/proto.Internal/GetLink"
+	Internal_GetLinkRules_FullMethodName          = "/proto.Internal/GetLinkRules"
+	Internal_SetLinkRules_FullMethodName          = "/proto.Internal/SetLinkRules"
+	Internal_GetQRCode_FullMethodName             = "/proto.Internal/GetQRCode"
+	Internal_DeleteURLs_FullMethodName            = "/proto.Internal/DeleteURLs"
+	Internal_ListCollections_FullMethodName       = "/proto.Internal/ListCollections"
+	Internal_CreateCollection_FullMethodName      = "/proto.Internal/CreateCollection"
+	Internal_GetCollection_FullMethodName         = "/proto.Internal/GetCollection"
+	Internal_UpdateCollection_FullMethodName      = "/proto.Internal/UpdateCollection"
+	Internal_DeleteCollection_FullMethodName      = "/proto.Internal/DeleteCollection"
+	Internal_GetCollectionURLs_FullMethodName     = "/proto.Internal/GetCollectionURLs"
+	Internal_AddCollectionURLs_FullMethodName     = "/proto.Internal/AddCollectionURLs"
+	Internal_DeleteCollectionURLs_FullMethodName  = "/proto.Internal/DeleteCollectionURLs"
+	Internal_ListWorkspaces_FullMethodName        = "/proto.Internal/ListWorkspaces"
+	Internal_CreateWorkspace_FullMethodName       = "/proto.Internal/CreateWorkspace"
+	Internal_GetWorkspace_FullMethodName          = "/proto.Internal/GetWorkspace"
+	Internal_UpdateWorkspace_FullMethodName       = "/proto.Internal/UpdateWorkspace"
+	Internal_DeleteWorkspace_FullMethodName       = "/proto.Internal/DeleteWorkspace"
+	Internal_GetWorkspaceMembers_FullMethodName   = "/proto.Internal/GetWorkspaceMembers"
+	Internal_SetWorkspaceMember_FullMethodName    = "/proto.Internal/SetWorkspaceMember"
+	Internal_DeleteWorkspaceMember_FullMethodName = "/proto.Internal/DeleteWorkspaceMember"
+	Internal_GetWorkspaceURLs_FullMethodName      = "/proto.Internal/GetWorkspaceURLs"
+	Internal_Register_FullMethodName              = "/proto.Internal/Register"
+	Internal_Login_FullMethodName                 = "/proto.Internal/Login"
+	Internal_Logout_FullMethodName                = "/proto.Internal/Logout"
+	Internal_GetAccount_FullMethodName            = "/proto.Internal/GetAccount"
+	Internal_ClaimURLs_FullMethodName             = "/proto.Internal/ClaimURLs"
+	Internal_GetStats_FullMethodName              = "/proto.Internal/GetStats"
+	Internal_PingDB_FullMethodName                = "/proto.Internal/PingDB"
 )
 
 // InternalClient is the client API for Internal service.
@@ -40,7 +67,34 @@ type InternalClient interface {
 	Shorten(ctx context.Context, in *shortener.RequestShorten, opts ...grpc.CallOption) (*shortener.ResponseShorten, error)
 	SaveBatch(ctx context.Context, in *shortener.RequestSaveBatch, opts ...grpc.CallOption) (*shortener.ResponseSaveBatch, error)
 	GetUserURL(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseGetUserURL, error)
+	SearchURLs(ctx context.Context, in *shortener.RequestSearchURLs, opts ...grpc.CallOption) (*shortener.ResponseSearchURLs, error)
+	GetLink(ctx context.Context, in *shortener.RequestGetLink, opts ...grpc.CallOption) (*shortener.ResponseGetLink, error)
+	GetLinkRules(ctx context.Context, in *shortener.RequestGetLink, opts ...grpc.CallOption) (*shortener.ResponseLinkRules, error)
+	SetLinkRules(ctx context.Context, in *shortener.RequestSetLinkRules, opts ...grpc.CallOption) (*shortener.ResponseLinkRules, error)
+	GetQRCode(ctx context.Context, in *shortener.RequestQRCode, opts ...grpc.CallOption) (*shortener.ResponseQRCode, error)
 	DeleteURLs(ctx context.Context, in *shortener.RequestDeleteURLs, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListCollections(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseListCollections, error)
+	CreateCollection(ctx context.Context, in *shortener.RequestCreateCollection, opts ...grpc.CallOption) (*shortener.ResponseCollection, error)
+	GetCollection(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*shortener.ResponseCollection, error)
+	UpdateCollection(ctx context.Context, in *shortener.RequestUpdateCollection, opts ...grpc.CallOption) (*shortener.ResponseCollection, error)
+	DeleteCollection(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetCollectionURLs(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*shortener.ResponseCollectionURLs, error)
+	AddCollectionURLs(ctx context.Context, in *shortener.RequestCollectionURLs, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeleteCollectionURLs(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListWorkspaces(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseListWorkspaces, error)
+	CreateWorkspace(ctx context.Context, in *shortener.RequestCreateWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspace, error)
+	GetWorkspace(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspace, error)
+	UpdateWorkspace(ctx context.Context, in *shortener.RequestUpdateWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspace, error)
+	DeleteWorkspace(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetWorkspaceMembers(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspaceMembers, error)
+	SetWorkspaceMember(ctx context.Context, in *shortener.RequestWorkspaceMember, opts ...grpc.CallOption) (*shortener.ResponseWorkspaceMember, error)
+	DeleteWorkspaceMember(ctx context.Context, in *shortener.RequestWorkspaceMember, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetWorkspaceURLs(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*shortener.ResponseGetUserURL, error)
+	Register(ctx context.Context, in *shortener.RequestAccount, opts ...grpc.CallOption) (*shortener.ResponseSession, error)
+	Login(ctx context.Context, in *shortener.RequestAccount, opts ...grpc.CallOption) (*shortener.ResponseSession, error)
+	Logout(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetAccount(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseAccount, error)
+	ClaimURLs(ctx context.Context, in *shortener.RequestClaim, opts ...grpc.CallOption) (*shortener.ResponseClaim, error)
 	GetStats(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseGetStats, error)
 	PingDB(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
 }
@@ -103,6 +157,56 @@ func (c *internalClient) GetUserURL(ctx context.Context, in *empty.Empty, opts .
 	return out, nil
 }
 
+func (c *internalClient) SearchURLs(ctx context.Context, in *shortener.RequestSearchURLs, opts ...grpc.CallOption) (*shortener.ResponseSearchURLs, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseSearchURLs)
+	err := c.cc.Invoke(ctx, Internal_SearchURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetLink(ctx context.Context, in *shortener.RequestGetLink, opts ...grpc.CallOption) (*shortener.ResponseGetLink, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseGetLink)
+	err := c.cc.Invoke(ctx, Internal_GetLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetLinkRules(ctx context.Context, in *shortener.RequestGetLink, opts ...grpc.CallOption) (*shortener.ResponseLinkRules, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseLinkRules)
+	err := c.cc.Invoke(ctx, Internal_GetLinkRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) SetLinkRules(ctx context.Context, in *shortener.RequestSetLinkRules, opts ...grpc.CallOption) (*shortener.ResponseLinkRules, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseLinkRules)
+	err := c.cc.Invoke(ctx, Internal_SetLinkRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetQRCode(ctx context.Context, in *shortener.RequestQRCode, opts ...grpc.CallOption) (*shortener.ResponseQRCode, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseQRCode)
+	err := c.cc.Invoke(ctx, Internal_GetQRCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *internalClient) DeleteURLs(ctx context.Context, in *shortener.RequestDeleteURLs, opts ...grpc.CallOption) (*empty.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(empty.Empty)
@@ -113,6 +217,226 @@ func (c *internalClient) DeleteURLs(ctx context.Context, in *shortener.RequestDe
 	return out, nil
 }
 
+func (c *internalClient) ListCollections(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseListCollections, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseListCollections)
+	err := c.cc.Invoke(ctx, Internal_ListCollections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) CreateCollection(ctx context.Context, in *shortener.RequestCreateCollection, opts ...grpc.CallOption) (*shortener.ResponseCollection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseCollection)
+	err := c.cc.Invoke(ctx, Internal_CreateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetCollection(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*shortener.ResponseCollection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseCollection)
+	err := c.cc.Invoke(ctx, Internal_GetCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) UpdateCollection(ctx context.Context, in *shortener.RequestUpdateCollection, opts ...grpc.CallOption) (*shortener.ResponseCollection, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseCollection)
+	err := c.cc.Invoke(ctx, Internal_UpdateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) DeleteCollection(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, Internal_DeleteCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetCollectionURLs(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*shortener.ResponseCollectionURLs, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseCollectionURLs)
+	err := c.cc.Invoke(ctx, Internal_GetCollectionURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) AddCollectionURLs(ctx context.Context, in *shortener.RequestCollectionURLs, opts ...grpc.CallOption) (*empty.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, Internal_AddCollectionURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) DeleteCollectionURLs(ctx context.Context, in *shortener.RequestCollection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, Internal_DeleteCollectionURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) ListWorkspaces(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseListWorkspaces, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseListWorkspaces)
+	err := c.cc.Invoke(ctx, Internal_ListWorkspaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) CreateWorkspace(ctx context.Context, in *shortener.RequestCreateWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspace, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseWorkspace)
+	err := c.cc.Invoke(ctx, Internal_CreateWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetWorkspace(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspace, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseWorkspace)
+	err := c.cc.Invoke(ctx, Internal_GetWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) UpdateWorkspace(ctx context.Context, in *shortener.RequestUpdateWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspace, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseWorkspace)
+	err := c.cc.Invoke(ctx, Internal_UpdateWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) DeleteWorkspace(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*empty.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, Internal_DeleteWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetWorkspaceMembers(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*shortener.ResponseWorkspaceMembers, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseWorkspaceMembers)
+	err := c.cc.Invoke(ctx, Internal_GetWorkspaceMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) SetWorkspaceMember(ctx context.Context, in *shortener.RequestWorkspaceMember, opts ...grpc.CallOption) (*shortener.ResponseWorkspaceMember, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseWorkspaceMember)
+	err := c.cc.Invoke(ctx, Internal_SetWorkspaceMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) DeleteWorkspaceMember(ctx context.Context, in *shortener.RequestWorkspaceMember, opts ...grpc.CallOption) (*empty.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, Internal_DeleteWorkspaceMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetWorkspaceURLs(ctx context.Context, in *shortener.RequestWorkspace, opts ...grpc.CallOption) (*shortener.ResponseGetUserURL, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseGetUserURL)
+	err := c.cc.Invoke(ctx, Internal_GetWorkspaceURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) Register(ctx context.Context, in *shortener.RequestAccount, opts ...grpc.CallOption) (*shortener.ResponseSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseSession)
+	err := c.cc.Invoke(ctx, Internal_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) Login(ctx context.Context, in *shortener.RequestAccount, opts ...grpc.CallOption) (*shortener.ResponseSession, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseSession)
+	err := c.cc.Invoke(ctx, Internal_Login_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) Logout(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, Internal_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) GetAccount(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseAccount, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseAccount)
+	err := c.cc.Invoke(ctx, Internal_GetAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) ClaimURLs(ctx context.Context, in *shortener.RequestClaim, opts ...grpc.CallOption) (*shortener.ResponseClaim, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(shortener.ResponseClaim)
+	err := c.cc.Invoke(ctx, Internal_ClaimURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *internalClient) GetStats(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*shortener.ResponseGetStats, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(shortener.ResponseGetStats)
@@ -142,7 +466,34 @@ type InternalServer interface {
 	Shorten(context.Context, *shortener.RequestShorten) (*shortener.ResponseShorten, error)
 	SaveBatch(context.Context, *shortener.RequestSaveBatch) (*shortener.ResponseSaveBatch, error)
 	GetUserURL(context.Context, *empty.Empty) (*shortener.ResponseGetUserURL, error)
+	SearchURLs(context.Context, *shortener.RequestSearchURLs) (*shortener.ResponseSearchURLs, error)
+	GetLink(context.Context, *shortener.RequestGetLink) (*shortener.ResponseGetLink, error)
+	GetLinkRules(context.Context, *shortener.RequestGetLink) (*shortener.ResponseLinkRules, error)
+	SetLinkRules(context.Context, *shortener.RequestSetLinkRules) (*shortener.ResponseLinkRules, error)
+	GetQRCode(context.Context, *shortener.RequestQRCode) (*shortener.ResponseQRCode, error)
 	DeleteURLs(context.Context, *shortener.RequestDeleteURLs) (*empty.Empty, error)
+	ListCollections(context.Context, *empty.Empty) (*shortener.ResponseListCollections, error)
+	CreateCollection(context.Context, *shortener.RequestCreateCollection) (*shortener.ResponseCollection, error)
+	GetCollection(context.Context, *shortener.RequestCollection) (*shortener.ResponseCollection, error)
+	UpdateCollection(context.Context, *shortener.RequestUpdateCollection) (*shortener.ResponseCollection, error)
+	DeleteCollection(context.Context, *shortener.RequestCollection) (*empty.Empty, error)
+	GetCollectionURLs(context.Context, *shortener.RequestCollection) (*shortener.ResponseCollectionURLs, error)
+	AddCollectionURLs(context.Context, *shortener.RequestCollectionURLs) (*empty.Empty, error)
+	DeleteCollectionURLs(context.Context, *shortener.RequestCollection) (*empty.Empty, error)
+	ListWorkspaces(context.Context, *empty.Empty) (*shortener.ResponseListWorkspaces, error)
+	CreateWorkspace(context.Context, *shortener.RequestCreateWorkspace) (*shortener.ResponseWorkspace, error)
+	GetWorkspace(context.Context, *shortener.RequestWorkspace) (*shortener.ResponseWorkspace, error)
+	UpdateWorkspace(context.Context, *shortener.RequestUpdateWorkspace) (*shortener.ResponseWorkspace, error)
+	DeleteWorkspace(context.Context, *shortener.RequestWorkspace) (*empty.Empty, error)
+	GetWorkspaceMembers(context.Context, *shortener.RequestWorkspace) (*shortener.ResponseWorkspaceMembers, error)
+	SetWorkspaceMember(context.Context, *shortener.RequestWorkspaceMember) (*shortener.ResponseWorkspaceMember, error)
+	DeleteWorkspaceMember(context.Context, *shortener.RequestWorkspaceMember) (*empty.Empty, error)
+	GetWorkspaceURLs(context.Context, *shortener.RequestWorkspace) (*shortener.ResponseGetUserURL, error)
+	Register(context.Context, *shortener.RequestAccount) (*shortener.ResponseSession, error)
+	Login(context.Context, *shortener.RequestAccount) (*shortener.ResponseSession, error)
+	Logout(context.Context, *empty.Empty) (*empty.Empty, error)
+	GetAccount(context.Context, *empty.Empty) (*shortener.ResponseAccount, error)
+	ClaimURLs(context.Context, *shortener.RequestClaim) (*shortener.ResponseClaim, error)
 	GetStats(context.Context, *empty.Empty) (*shortener.ResponseGetStats, error)
 	PingDB(context.Context, *empty.Empty) (*empty.Empty, error)
 	mustEmbedUnimplementedInternalServer()
@@ -170,9 +521,90 @@ func (UnimplementedInternalServer) SaveBatch(context.Context, *shortener.Request
 func (UnimplementedInternalServer) GetUserURL(context.Context, *empty.Empty) (*shortener.ResponseGetUserURL, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserURL not implemented")
 }
+func (UnimplementedInternalServer) SearchURLs(context.Context, *shortener.RequestSearchURLs) (*shortener.ResponseSearchURLs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchURLs not implemented")
+}
+func (UnimplementedInternalServer) GetLink(context.Context, *shortener.RequestGetLink) (*shortener.ResponseGetLink, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLink not implemented")
+}
+func (UnimplementedInternalServer) GetLinkRules(context.Context, *shortener.RequestGetLink) (*shortener.ResponseLinkRules, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkRules not implemented")
+}
+func (UnimplementedInternalServer) SetLinkRules(context.Context, *shortener.RequestSetLinkRules) (*shortener.ResponseLinkRules, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkRules not implemented")
+}
+func (UnimplementedInternalServer) GetQRCode(context.Context, *shortener.RequestQRCode) (*shortener.ResponseQRCode, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQRCode not implemented")
+}
 func (UnimplementedInternalServer) DeleteURLs(context.Context, *shortener.RequestDeleteURLs) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteURLs not implemented")
 }
+func (UnimplementedInternalServer) ListCollections(context.Context, *empty.Empty) (*shortener.ResponseListCollections, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCollections not implemented")
+}
+func (UnimplementedInternalServer) CreateCollection(context.Context, *shortener.RequestCreateCollection) (*shortener.ResponseCollection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCollection not implemented")
+}
+func (UnimplementedInternalServer) GetCollection(context.Context, *shortener.RequestCollection) (*shortener.ResponseCollection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCollection not implemented")
+}
+func (UnimplementedInternalServer) UpdateCollection(context.Context, *shortener.RequestUpdateCollection) (*shortener.ResponseCollection, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCollection not implemented")
+}
+func (UnimplementedInternalServer) DeleteCollection(context.Context, *shortener.RequestCollection) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCollection not implemented")
+}
+func (UnimplementedInternalServer) GetCollectionURLs(context.Context, *shortener.RequestCollection) (*shortener.ResponseCollectionURLs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCollectionURLs not implemented")
+}
+func (UnimplementedInternalServer) AddCollectionURLs(context.Context, *shortener.RequestCollectionURLs) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCollectionURLs not implemented")
+}
+func (UnimplementedInternalServer) DeleteCollectionURLs(context.Context, *shortener.RequestCollection) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCollectionURLs not implemented")
+}
+func (UnimplementedInternalServer) ListWorkspaces(context.Context, *empty.Empty) (*shortener.ResponseListWorkspaces, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaces not implemented")
+}
+func (UnimplementedInternalServer) CreateWorkspace(context.Context, *shortener.RequestCreateWorkspace) (*shortener.ResponseWorkspace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkspace not implemented")
+}
+func (UnimplementedInternalServer) GetWorkspace(context.Context, *shortener.RequestWorkspace) (*shortener.ResponseWorkspace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspace not implemented")
+}
+func (UnimplementedInternalServer) UpdateWorkspace(context.Context, *shortener.RequestUpdateWorkspace) (*shortener.ResponseWorkspace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWorkspace not implemented")
+}
+func (UnimplementedInternalServer) DeleteWorkspace(context.Context, *shortener.RequestWorkspace) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkspace not implemented")
+}
+func (UnimplementedInternalServer) GetWorkspaceMembers(context.Context, *shortener.RequestWorkspace) (*shortener.ResponseWorkspaceMembers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceMembers not implemented")
+}
+func (UnimplementedInternalServer) SetWorkspaceMember(context.Context, *shortener.RequestWorkspaceMember) (*shortener.ResponseWorkspaceMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWorkspaceMember not implemented")
+}
+func (UnimplementedInternalServer) DeleteWorkspaceMember(context.Context, *shortener.RequestWorkspaceMember) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkspaceMember not implemented")
+}
+func (UnimplementedInternalServer) GetWorkspaceURLs(context.Context, *shortener.RequestWorkspace) (*shortener.ResponseGetUserURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceURLs not implemented")
+}
+func (UnimplementedInternalServer) Register(context.Context, *shortener.RequestAccount) (*shortener.ResponseSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedInternalServer) Login(context.Context, *shortener.RequestAccount) (*shortener.ResponseSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedInternalServer) Logout(context.Context, *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedInternalServer) GetAccount(context.Context, *empty.Empty) (*shortener.ResponseAccount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedInternalServer) ClaimURLs(context.Context, *shortener.RequestClaim) (*shortener.ResponseClaim, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimURLs not implemented")
+}
 func (UnimplementedInternalServer) GetStats(context.Context, *empty.Empty) (*shortener.ResponseGetStats, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
 }
@@ -290,78 +722,564 @@ func _Internal_GetUserURL_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Internal_DeleteURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(shortener.RequestDeleteURLs)
+func _Internal_SearchURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestSearchURLs)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InternalServer).DeleteURLs(ctx, in)
+		return srv.(InternalServer).SearchURLs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Internal_DeleteURLs_FullMethodName,
+		FullMethod: Internal_SearchURLs_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InternalServer).DeleteURLs(ctx, req.(*shortener.RequestDeleteURLs))
+		return srv.(InternalServer).SearchURLs(ctx, req.(*shortener.RequestSearchURLs))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Internal_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _Internal_GetLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestGetLink)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InternalServer).GetStats(ctx, in)
+		return srv.(InternalServer).GetLink(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Internal_GetStats_FullMethodName,
+		FullMethod: Internal_GetLink_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InternalServer).GetStats(ctx, req.(*empty.Empty))
+		return srv.(InternalServer).GetLink(ctx, req.(*shortener.RequestGetLink))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Internal_PingDB_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _Internal_GetLinkRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestGetLink)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InternalServer).PingDB(ctx, in)
+		return srv.(InternalServer).GetLinkRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Internal_PingDB_FullMethodName,
+		FullMethod: Internal_GetLinkRules_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InternalServer).PingDB(ctx, req.(*empty.Empty))
+		return srv.(InternalServer).GetLinkRules(ctx, req.(*shortener.RequestGetLink))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// Internal_ServiceDesc is the grpc.ServiceDesc for Internal service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Internal_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.Internal",
-	HandlerType: (*InternalServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Encode",
-			Handler:    _Internal_Encode_Handler,
-		},
-		{
-			MethodName: "Decode",
-			Handler:    _Internal_Decode_Handler,
-		},
-		{
-			MethodName: "Shorten",
-			Handler:    _Internal_Shorten_Handler,
+func _Internal_SetLinkRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestSetLinkRules)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).SetLinkRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_SetLinkRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).SetLinkRules(ctx, req.(*shortener.RequestSetLinkRules))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetQRCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestQRCode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetQRCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetQRCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetQRCode(ctx, req.(*shortener.RequestQRCode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_DeleteURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestDeleteURLs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).DeleteURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_DeleteURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).DeleteURLs(ctx, req.(*shortener.RequestDeleteURLs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_ListCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).ListCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_ListCollections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).ListCollections(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_CreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestCreateCollection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).CreateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_CreateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).CreateCollection(ctx, req.(*shortener.RequestCreateCollection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestCollection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetCollection(ctx, req.(*shortener.RequestCollection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_UpdateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestUpdateCollection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).UpdateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_UpdateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).UpdateCollection(ctx, req.(*shortener.RequestUpdateCollection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_DeleteCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestCollection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).DeleteCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_DeleteCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).DeleteCollection(ctx, req.(*shortener.RequestCollection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetCollectionURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestCollection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetCollectionURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetCollectionURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetCollectionURLs(ctx, req.(*shortener.RequestCollection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_AddCollectionURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestCollectionURLs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).AddCollectionURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_AddCollectionURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).AddCollectionURLs(ctx, req.(*shortener.RequestCollectionURLs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_DeleteCollectionURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestCollection)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).DeleteCollectionURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_DeleteCollectionURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).DeleteCollectionURLs(ctx, req.(*shortener.RequestCollection))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_ListWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).ListWorkspaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_ListWorkspaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).ListWorkspaces(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_CreateWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestCreateWorkspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).CreateWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_CreateWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).CreateWorkspace(ctx, req.(*shortener.RequestCreateWorkspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestWorkspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetWorkspace(ctx, req.(*shortener.RequestWorkspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_UpdateWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestUpdateWorkspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).UpdateWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_UpdateWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).UpdateWorkspace(ctx, req.(*shortener.RequestUpdateWorkspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_DeleteWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestWorkspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).DeleteWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_DeleteWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).DeleteWorkspace(ctx, req.(*shortener.RequestWorkspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetWorkspaceMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestWorkspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetWorkspaceMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetWorkspaceMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetWorkspaceMembers(ctx, req.(*shortener.RequestWorkspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_SetWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestWorkspaceMember)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).SetWorkspaceMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_SetWorkspaceMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).SetWorkspaceMember(ctx, req.(*shortener.RequestWorkspaceMember))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_DeleteWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestWorkspaceMember)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).DeleteWorkspaceMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_DeleteWorkspaceMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).DeleteWorkspaceMember(ctx, req.(*shortener.RequestWorkspaceMember))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetWorkspaceURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestWorkspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetWorkspaceURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetWorkspaceURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetWorkspaceURLs(ctx, req.(*shortener.RequestWorkspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestAccount)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).Register(ctx, req.(*shortener.RequestAccount))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestAccount)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).Login(ctx, req.(*shortener.RequestAccount))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).Logout(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetAccount(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_ClaimURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(shortener.RequestClaim)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).ClaimURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_ClaimURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).ClaimURLs(ctx, req.(*shortener.RequestClaim))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).GetStats(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_PingDB_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).PingDB(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Internal_PingDB_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).PingDB(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Internal_ServiceDesc is the grpc.ServiceDesc for Internal service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Internal_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Internal",
+	HandlerType: (*InternalServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Encode",
+			Handler:    _Internal_Encode_Handler,
+		},
+		{
+			MethodName: "Decode",
+			Handler:    _Internal_Decode_Handler,
+		},
+		{
+			MethodName: "Shorten",
+			Handler:    _Internal_Shorten_Handler,
 		},
 		{
 			MethodName: "SaveBatch",
@@ -371,10 +1289,118 @@ var Internal_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserURL",
 			Handler:    _Internal_GetUserURL_Handler,
 		},
+		{
+			MethodName: "SearchURLs",
+			Handler:    _Internal_SearchURLs_Handler,
+		},
+		{
+			MethodName: "GetLink",
+			Handler:    _Internal_GetLink_Handler,
+		},
+		{
+			MethodName: "GetLinkRules",
+			Handler:    _Internal_GetLinkRules_Handler,
+		},
+		{
+			MethodName: "SetLinkRules",
+			Handler:    _Internal_SetLinkRules_Handler,
+		},
+		{
+			MethodName: "GetQRCode",
+			Handler:    _Internal_GetQRCode_Handler,
+		},
 		{
 			MethodName: "DeleteURLs",
 			Handler:    _Internal_DeleteURLs_Handler,
 		},
+		{
+			MethodName: "ListCollections",
+			Handler:    _Internal_ListCollections_Handler,
+		},
+		{
+			MethodName: "CreateCollection",
+			Handler:    _Internal_CreateCollection_Handler,
+		},
+		{
+			MethodName: "GetCollection",
+			Handler:    _Internal_GetCollection_Handler,
+		},
+		{
+			MethodName: "UpdateCollection",
+			Handler:    _Internal_UpdateCollection_Handler,
+		},
+		{
+			MethodName: "DeleteCollection",
+			Handler:    _Internal_DeleteCollection_Handler,
+		},
+		{
+			MethodName: "GetCollectionURLs",
+			Handler:    _Internal_GetCollectionURLs_Handler,
+		},
+		{
+			MethodName: "AddCollectionURLs",
+			Handler:    _Internal_AddCollectionURLs_Handler,
+		},
+		{
+			MethodName: "DeleteCollectionURLs",
+			Handler:    _Internal_DeleteCollectionURLs_Handler,
+		},
+		{
+			MethodName: "ListWorkspaces",
+			Handler:    _Internal_ListWorkspaces_Handler,
+		},
+		{
+			MethodName: "CreateWorkspace",
+			Handler:    _Internal_CreateWorkspace_Handler,
+		},
+		{
+			MethodName: "GetWorkspace",
+			Handler:    _Internal_GetWorkspace_Handler,
+		},
+		{
+			MethodName: "UpdateWorkspace",
+			Handler:    _Internal_UpdateWorkspace_Handler,
+		},
+		{
+			MethodName: "DeleteWorkspace",
+			Handler:    _Internal_DeleteWorkspace_Handler,
+		},
+		{
+			MethodName: "GetWorkspaceMembers",
+			Handler:    _Internal_GetWorkspaceMembers_Handler,
+		},
+		{
+			MethodName: "SetWorkspaceMember",
+			Handler:    _Internal_SetWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "DeleteWorkspaceMember",
+			Handler:    _Internal_DeleteWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "GetWorkspaceURLs",
+			Handler:    _Internal_GetWorkspaceURLs_Handler,
+		},
+		{
+			MethodName: "Register",
+			Handler:    _Internal_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Internal_Login_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Internal_Logout_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _Internal_GetAccount_Handler,
+		},
+		{
+			MethodName: "ClaimURLs",
+			Handler:    _Internal_ClaimURLs_Handler,
+		},
 		{
 			MethodName: "GetStats",
 			Handler:    _Internal_GetStats_Handler,
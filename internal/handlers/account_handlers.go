@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// authCookieName name of cookie with auth token
+const authCookieName = "auth"
+
+// Register handler for creating account with login session, account keeps links of current anonymous user
+// @Accept json email and password
+// @Success 201 {json} account with session token, auth cookie is set
+// @Failure 400 {problem} bad request if JSON, email or password is invalid
+// @Failure 409 {problem} error if email is registered already
+func (h *Handlers) Register() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		var req models.AccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		session, err := h.appService.Register(ctx, auth.UIDFromContext(ctx), req)
+		if err != nil {
+			logger.Log.Debug("Ошибка регистрации пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		setAuthCookie(w, r, session)
+		writeJSON(w, r, http.StatusCreated, session)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// Login handler for creating login session of account, links of current anonymous user are merged into account if claim is set
+// @Accept json email, password and claim flag
+// @Success 200 {json} account with session token, auth cookie is set
+// @Failure 400 {problem} bad request if JSON is invalid
+// @Failure 401 {problem} error if email or password is wrong
+// @Failure 429 {problem} error if wrong password is entered too many times for email or from client
+func (h *Handlers) Login() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		var req models.AccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		session, err := h.appService.Login(ctx, auth.UIDFromContext(ctx), clientIP(r), req)
+		if err != nil {
+			logger.Log.Debug("Ошибка входа пользователя", zap.Error(err))
+			if errors.Is(err, shortenerservice.ErrTooManyPasswordAttempts) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(shortenerservice.PasswordAttemptsWindow.Seconds())))
+			}
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		setAuthCookie(w, r, session)
+		writeJSON(w, r, http.StatusOK, session)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// Logout handler for finishing login session of auth cookie, auth cookie is removed
+// @Success 204 session is finished
+// @Failure 500 {problem} internal error if session can't be deleted
+func (h *Handlers) Logout() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		if cookie, err := r.Cookie(authCookieName); err == nil && cookie.Value != "" {
+			if err = h.appService.Logout(ctx, cookie.Value); err != nil {
+				logger.Log.Debug("Ошибка завершения сессии", zap.Error(err))
+				problem.WriteError(w, r, err)
+				return
+			}
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     authCookieName,
+			Value:    "",
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// GetAccount handler for getting account of registered user
+// @Success 200 {json} account
+// @Failure 401 {problem} error if user unauthorized or session is finished
+// @Failure 403 {problem} error if user isn't registered
+func (h *Handlers) GetAccount() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		account, err := h.appService.GetAccount(ctx, userID)
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, account)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// ClaimURLs handler for merging links and collections of anonymous user into account by token of anonymous user
+// @Accept json auth token of anonymous user
+// @Success 200 {json} count of claimed links
+// @Failure 400 {problem} bad request if JSON or token is invalid
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 403 {problem} error if user isn't registered
+func (h *Handlers) ClaimURLs() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.ClaimRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		claimed, err := h.appService.Claim(ctx, userID, req.Token)
+		if err != nil {
+			logger.Log.Debug("Ошибка переноса ссылок анонимного пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, models.ClaimResponse{Claimed: claimed})
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// setAuthCookie function sets auth cookie with token of login session
+func setAuthCookie(w http.ResponseWriter, r *http.Request, session models.AccountSession) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     authCookieName,
+		Value:    session.Token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
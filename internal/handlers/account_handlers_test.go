@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/middlewares"
+	"github.com/romanp1989/go-shortener/internal/models"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlers_Accounts(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+		SecretKey:     "verycomplexsecretkey",
+	}
+	jwtService := auth.NewJwtService(cfg.SecretKey)
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+	m := middlewares.Middleware{Cfg: cfg, JwtService: jwtService, AppService: appService}
+
+	r := chi.NewRouter()
+	r.With(m.AuthMiddlewareSet).Post("/api/v2/links", handler.CreateLink())
+	r.With(m.AuthMiddlewareRead).Get("/api/user/urls", handler.GetURLs())
+	r.With(m.AuthMiddlewareOptional).Post("/api/user/register", handler.Register())
+	r.With(m.AuthMiddlewareOptional).Post("/api/user/login", handler.Login())
+	r.Post("/api/user/logout", handler.Logout())
+	r.With(m.AuthMiddlewareRead).Get("/api/user/account", handler.GetAccount())
+	r.With(m.AuthMiddlewareRead).Post("/api/user/claim", handler.ClaimURLs())
+
+	anonymousToken := func() (uuid.UUID, string) {
+		userID := jwtService.EnsureRandom()
+		token, err := jwtService.CreateToken(&userID, cfg.SecretKey)
+		require.NoError(t, err)
+		return userID, token
+	}
+	send := func(token string, method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: "auth", Value: token})
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	authCookie := func(w *httptest.ResponseRecorder) *http.Cookie {
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == "auth" {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	first, firstToken := anonymousToken()
+	w := send(firstToken, http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = send(firstToken, http.MethodPost, "/api/user/register", `{"email":"user","password":"password123"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_email"`)
+	w = send(firstToken, http.MethodPost, "/api/user/register", `{"email":"user@example.com","password":"short"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"weak_password"`)
+
+	// аккаунт получает идентификатор анонимного пользователя вместе со ссылками
+	w = send(firstToken, http.MethodPost, "/api/user/register", `{"email":" User@Example.com ","password":"password123"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var session models.AccountSession
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(t, first, *session.Account.UserID)
+	assert.Equal(t, "user@example.com", session.Account.Email)
+	assert.NotContains(t, w.Body.String(), "password")
+	cookie := authCookie(w)
+	require.NotNil(t, cookie)
+	assert.Equal(t, session.Token, cookie.Value)
+	assert.True(t, cookie.HttpOnly)
+
+	w = send(session.Token, http.MethodGet, "/api/user/urls", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "https://ya.ru")
+
+	// токен анонимного пользователя не действует после регистрации аккаунта
+	w = send(firstToken, http.MethodGet, "/api/user/urls", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = send(firstToken, http.MethodGet, "/api/user/account", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	_, otherToken := anonymousToken()
+	w = send(otherToken, http.MethodPost, "/api/user/register", `{"email":"user@example.com","password":"password123"}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"email_taken"`)
+
+	// после выхода токен сессии не принимается
+	w = send(session.Token, http.MethodPost, "/api/user/logout", "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	cookie = authCookie(w)
+	require.NotNil(t, cookie)
+	assert.Equal(t, -1, cookie.MaxAge)
+	w = send(session.Token, http.MethodGet, "/api/user/account", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// при входе с claim ссылки анонимного пользователя переносятся в аккаунт
+	_, secondToken := anonymousToken()
+	w = send(secondToken, http.MethodPost, "/api/v2/links", `{"original_url":"https://dzen.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = send(secondToken, http.MethodPost, "/api/user/login", `{"email":"user@example.com","password":"wrongpassword"}`)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_login"`)
+	w = send(secondToken, http.MethodPost, "/api/user/login", `{"email":"nobody@example.com","password":"password123"}`)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_login"`)
+	w = send(secondToken, http.MethodPost, "/api/user/login", `{"email":"user@example.com","password":"password123","claim":true}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(t, first, *session.Account.UserID)
+	assert.Equal(t, 1, session.Claimed)
+
+	w = send(session.Token, http.MethodGet, "/api/user/account", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"email":"user@example.com"`)
+
+	// ссылки анонимного пользователя переносятся по его токену
+	_, thirdToken := anonymousToken()
+	w = send(thirdToken, http.MethodPost, "/api/v2/links", `{"original_url":"https://mail.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = send(thirdToken, http.MethodPost, "/api/user/claim", `{"token":"`+secondToken+`"}`)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"account_required"`)
+	w = send(session.Token, http.MethodPost, "/api/user/claim", `{"token":"`+session.Token+`"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_claim_token"`)
+
+	// токен, истекший раньше допустимого срока, не принимается
+	staleUserID := jwtService.EnsureRandom()
+	staleToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-31 * 24 * time.Hour))},
+		UserID:           &staleUserID,
+	}).SignedString([]byte(cfg.SecretKey))
+	require.NoError(t, err)
+	w = send(session.Token, http.MethodPost, "/api/user/claim", `{"token":"`+staleToken+`"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_claim_token"`)
+
+	w = send(session.Token, http.MethodPost, "/api/user/claim", `{"token":"`+thirdToken+`"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"claimed":1}`, w.Body.String())
+
+	w = send(session.Token, http.MethodGet, "/api/user/urls", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	for _, url := range []string{"https://ya.ru", "https://dzen.ru", "https://mail.ru"} {
+		assert.Contains(t, w.Body.String(), url)
+	}
+
+	// после пяти неверных паролей с одного адреса вход с него блокируется
+	for i := 0; i < 3; i++ {
+		w = send("", http.MethodPost, "/api/user/login", `{"email":"user@example.com","password":"wrongpassword"}`)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	w = send("", http.MethodPost, "/api/user/login", `{"email":"user@example.com","password":"password123"}`)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"too_many_attempts"`)
+	assert.Equal(t, "900", w.Header().Get("Retry-After"))
+
+	// с другого адреса вход блокируется после пяти неверных паролей для email
+	login := func(password string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/login", strings.NewReader(`{"email":"user@example.com","password":"`+password+`"}`))
+		req.RemoteAddr = "203.0.113.7:1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusUnauthorized, login("wrongpassword"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, login("password123"))
+}
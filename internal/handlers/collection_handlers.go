@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// writeJSON function writes value as JSON response
+func writeJSON(w http.ResponseWriter, r *http.Request, statusCode int, value any) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(b)
+}
+
+// ListCollections handler for getting user's collections with count of their links
+// @Success 200 {json} list of user's collections
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 500 {problem} internal error if collections can't be read
+func (h *Handlers) ListCollections() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		collections, err := h.appService.ListCollections(ctx, userID)
+		if err != nil {
+			logger.Log.Debug("Ошибка получения коллекций пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, collections)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// CreateCollection handler for creating user's collection
+// @Accept json collection name and description
+// @Success 201 {json} created collection
+// @Failure 400 {problem} bad request if JSON is invalid or name is empty
+// @Failure 401 {problem} error if user unauthorized
+func (h *Handlers) CreateCollection() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.CollectionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		collection, err := h.appService.CreateCollection(ctx, userID, req)
+		if err != nil {
+			logger.Log.Debug("Ошибка создания коллекции", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Location", r.URL.Path+"/"+collection.ID)
+		writeJSON(w, r, http.StatusCreated, collection)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// GetCollection handler for getting user's collection by id
+// @Accept string collection id
+// @Success 200 {json} collection
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if collection not found
+func (h *Handlers) GetCollection() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		collection, err := h.appService.GetCollection(ctx, userID, chi.URLParam(r, "id"))
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, collection)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// UpdateCollection handler for renaming user's collection, omitted fields aren't changed
+// @Accept json collection name and description
+// @Success 200 {json} updated collection
+// @Failure 400 {problem} bad request if JSON is invalid or name is empty
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if collection not found
+func (h *Handlers) UpdateCollection() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.CollectionUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		collection, err := h.appService.UpdateCollection(ctx, userID, chi.URLParam(r, "id"), req)
+		if err != nil {
+			logger.Log.Debug("Ошибка изменения коллекции", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, collection)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// DeleteCollection handler for deleting user's collection, its links are kept without collection
+// @Accept string collection id
+// @Success 204 collection is deleted
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if collection not found
+func (h *Handlers) DeleteCollection() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		if err := h.appService.DeleteCollection(ctx, userID, chi.URLParam(r, "id")); err != nil {
+			logger.Log.Debug("Ошибка удаления коллекции", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// GetCollectionURLs handler for getting not deleted links of user's collection, newest links are first
+// @Accept string collection id
+// @Success 200 {json} list of collection's links
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if collection not found
+func (h *Handlers) GetCollectionURLs() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		links, err := h.appService.CollectionLinks(ctx, userID, chi.URLParam(r, "id"))
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, links)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// AddCollectionURLs handler for moving user's links to collection
+// @Accept json list of link ids
+// @Success 204 links are added to collection
+// @Failure 400 {problem} bad request if JSON is invalid
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 403 {problem} error if any link is created by other member of workspace
+// @Failure 404 {problem} error if collection or any link not found
+// @Failure 410 {problem} error if any link deleted
+func (h *Handlers) AddCollectionURLs() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		if err := h.appService.AddCollectionLinks(ctx, userID, chi.URLParam(r, "id"), ids); err != nil {
+			logger.Log.Debug("Ошибка добавления ссылок в коллекцию", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// DeleteCollectionURLs handler for deleting all links of user's collection, collection is kept
+// @Accept string collection id
+// @Success 204 links are deleted
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if collection not found
+func (h *Handlers) DeleteCollectionURLs() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		if err := h.appService.DeleteCollectionLinks(ctx, userID, chi.URLParam(r, "id")); err != nil {
+			logger.Log.Debug("Ошибка удаления ссылок коллекции", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// ExportCollection handler for download links of user's collection in csv, ndjson or json format
+// @Accept string query param format, json by default
+// @Success 200 {file} collection's links export
+// @Failure 400 {problem} bad request if format is unsupported
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if collection not found
+func (h *Handlers) ExportCollection() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		writeExport(w, r, "collection", func(fn func(models.ExportURL) error) error {
+			return h.appService.ExportCollection(ctx, userID, id, fn)
+		})
+	}
+
+	return http.HandlerFunc(fn)
+}
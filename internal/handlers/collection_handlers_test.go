@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlers_Collections(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+	stranger := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Post("/api/v2/links", handler.CreateLink())
+	r.Patch("/api/v2/links/{id}", handler.UpdateLink())
+	r.Get("/api/user/collections", handler.ListCollections())
+	r.Post("/api/user/collections", handler.CreateCollection())
+	r.Get("/api/user/collections/{id}", handler.GetCollection())
+	r.Patch("/api/user/collections/{id}", handler.UpdateCollection())
+	r.Delete("/api/user/collections/{id}", handler.DeleteCollection())
+	r.Get("/api/user/collections/{id}/urls", handler.GetCollectionURLs())
+	r.Post("/api/user/collections/{id}/urls", handler.AddCollectionURLs())
+	r.Delete("/api/user/collections/{id}/urls", handler.DeleteCollectionURLs())
+	r.Get("/api/user/collections/{id}/export", handler.ExportCollection())
+
+	send := func(userID uuid.UUID, method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, userID))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(owner, http.MethodPost, "/api/user/collections", `{"name":"  "}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_collection"`)
+
+	w = send(owner, http.MethodPost, "/api/user/collections", `{"name":"Рассылка","description":"Ссылки из писем"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var collection models.Collection
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &collection))
+	require.NotEmpty(t, collection.ID)
+	assert.Equal(t, "/api/user/collections/"+collection.ID, w.Header().Get("Location"))
+	assert.NotContains(t, w.Body.String(), "user_id")
+	target := "/api/user/collections/" + collection.ID
+
+	// ссылка добавляется в коллекцию при создании
+	w = send(owner, http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru","collection_id":"`+collection.ID+`"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"collection_id":"`+collection.ID+`"`)
+
+	w = send(stranger, http.MethodPost, "/api/v2/links", `{"original_url":"https://mail.ru","collection_id":"`+collection.ID+`"}`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"collection_not_found"`)
+
+	// ссылки добавляются в коллекцию после создания
+	w = send(owner, http.MethodPost, "/api/v2/links", `{"original_url":"https://dzen.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = send(owner, http.MethodPost, "/api/v2/links", `{"original_url":"https://lenta.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = send(owner, http.MethodPost, target+"/urls", `["x+5vpM8W","unknown"]`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = send(owner, http.MethodPost, target+"/urls", `["x+5vpM8W","H14HxBQB"]`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = send(owner, http.MethodPatch, "/api/v2/links/H14HxBQB", `{"collection_id":""}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "collection_id")
+
+	w = send(owner, http.MethodGet, target+"/urls", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var links []models.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &links))
+	ids := make([]string, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, link.ID)
+	}
+	assert.ElementsMatch(t, []string{"6YGS4ZUF", "x+5vpM8W"}, ids)
+
+	w = send(owner, http.MethodPatch, target, `{"name":"Письма"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Письма","description":"Ссылки из писем","links":2`)
+
+	w = send(owner, http.MethodGet, "/api/user/collections", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"`+collection.ID+`"`)
+
+	w = send(stranger, http.MethodGet, "/api/user/collections", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		w = send(stranger, method, target, "")
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+	}
+
+	w = send(owner, http.MethodGet, target+"/export?format=csv", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="collection.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, 3, strings.Count(w.Body.String(), "\n"))
+	assert.Contains(t, w.Body.String(), "https://dzen.ru")
+	assert.NotContains(t, w.Body.String(), "https://lenta.ru")
+
+	w = send(owner, http.MethodDelete, target+"/urls", "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	_, err := storageURLs.GetURL("6YGS4ZUF")
+	assert.Error(t, err)
+	got, err := storageURLs.GetURL("H14HxBQB")
+	require.NoError(t, err)
+	assert.Equal(t, "https://lenta.ru", got)
+
+	w = send(owner, http.MethodGet, target, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"links":0`)
+
+	w = send(owner, http.MethodDelete, target, "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = send(owner, http.MethodGet, target+"/urls", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
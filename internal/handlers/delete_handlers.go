@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
 	"io"
 	"net/http"
 	"time"
@@ -21,18 +23,18 @@ func (h *Handlers) DeleteURLs() http.HandlerFunc {
 		body, err := io.ReadAll(r.Body)
 		defer r.Body.Close()
 		if err != nil {
-			http.Error(w, "Ошибка при парсинге body запроса", http.StatusBadRequest)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBadRequest, "Ошибка при парсинге body запроса"))
 			return
 		}
 
 		userID := auth.UIDFromContext(ctx)
 		if userID == nil {
-			w.WriteHeader(http.StatusUnauthorized)
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
 			return
 		}
 
 		if err := json.Unmarshal(body, &urls); err != nil {
-			http.Error(w, "Ошибка при парсинге спика url для удаления", http.StatusBadRequest)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "Ошибка при парсинге спика url для удаления"))
 			return
 		}
 
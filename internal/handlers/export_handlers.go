@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportWriter writes user's URLs in export format
+type exportWriter interface {
+	begin() error
+	write(link models.ExportURL) error
+	end() error
+}
+
+// exportFormat export content type and writer factory
+type exportFormat struct {
+	contentType string
+	newWriter   func(w io.Writer) exportWriter
+}
+
+// exportFormats supported formats of user's URLs export
+var exportFormats = map[string]exportFormat{
+	"csv": {
+		contentType: "text/csv",
+		newWriter:   func(w io.Writer) exportWriter { return &csvExportWriter{w: csv.NewWriter(w)} },
+	},
+	"ndjson": {
+		contentType: "application/x-ndjson",
+		newWriter:   func(w io.Writer) exportWriter { return &ndjsonExportWriter{enc: json.NewEncoder(w)} },
+	},
+	"json": {
+		contentType: "application/json",
+		newWriter:   func(w io.Writer) exportWriter { return &jsonExportWriter{w: w} },
+	},
+}
+
+// csvFormulaPrefixes first characters of cell read by spreadsheet as formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvExportWriter writer for CSV export, tags are joined with semicolon.
+// Cells starting with formula character are prefixed with quote, so spreadsheet shows them as text
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) begin() error {
+	return c.w.Write([]string{"short_url", "original_url", "title", "notes", "tags", "is_deleted", "created_at"})
+}
+
+func (c *csvExportWriter) write(link models.ExportURL) error {
+	return c.w.Write([]string{
+		csvCell(link.ShortURL),
+		csvCell(link.OriginalURL),
+		csvCell(link.Title),
+		csvCell(link.Notes),
+		csvCell(strings.Join(link.Tags, ";")),
+		strconv.FormatBool(link.IsDeleted),
+		link.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+func (c *csvExportWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvCell function escapes user's text against formula injection in spreadsheets
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ndjsonExportWriter writer for NDJSON export
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonExportWriter) begin() error {
+	return nil
+}
+
+func (n *ndjsonExportWriter) write(link models.ExportURL) error {
+	return n.enc.Encode(link)
+}
+
+func (n *ndjsonExportWriter) end() error {
+	return nil
+}
+
+// jsonExportWriter writer for JSON array export, items are written one by one
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonExportWriter) begin() error {
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *jsonExportWriter) write(link models.ExportURL) error {
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+
+	b, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(b)
+	return err
+}
+
+func (j *jsonExportWriter) end() error {
+	_, err := io.WriteString(j.w, "]")
+	return err
+}
+
+// ExportURLs handler for download all user's URLs with metadata in csv, ndjson or json format
+// @Accept string query param format, json by default
+// @Success 200 {file} user's URLs export
+// @Failure 400 {problem} bad request if format is unsupported
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 500 {problem} internal error if URLs can't be read
+func (h *Handlers) ExportURLs() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		writeExport(w, r, "urls", func(fn func(models.ExportURL) error) error {
+			return h.appService.ExportURLs(ctx, userID, fn)
+		})
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// writeExport function writes links in format from query param, json by default.
+// Headers are sent before first link, so error of export before it is returned as problem.
+// Connection is aborted on error after it, so client sees failed transfer instead of truncated file
+func writeExport(w http.ResponseWriter, r *http.Request, filename string, export func(fn func(models.ExportURL) error) error) {
+	formatName := r.URL.Query().Get("format")
+	if formatName == "" {
+		formatName = "json"
+	}
+
+	format, ok := exportFormats[formatName]
+	if !ok {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeUnsupportedFormat, "Поддерживаются форматы csv, ndjson и json"))
+		return
+	}
+
+	writer := format.newWriter(w)
+	started := false
+
+	// заголовки отправляются перед первой записью, чтобы ошибку чтения из хранилища можно было вернуть кодом 500
+	begin := func() error {
+		started = true
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, formatName))
+		w.WriteHeader(http.StatusOK)
+		return writer.begin()
+	}
+
+	err := export(func(link models.ExportURL) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return writer.write(link)
+	})
+	if err != nil {
+		logger.Log.Debug("Ошибка при выгрузке urls пользователя", zap.Error(err))
+		if started {
+			panic(http.ErrAbortHandler)
+		}
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	if !started {
+		if err := begin(); err != nil {
+			return
+		}
+	}
+	if err := writer.end(); err != nil {
+		logger.Log.Debug("Ошибка при выгрузке urls пользователя", zap.Error(err))
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/models/mocks"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandlers_ExportURLs(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	brokenUserID := jwtService.EnsureRandom()
+	createdAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	links := []models.StorageURL{
+		{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", Title: "Yandex", Tags: []string{"search", "main"}, CreatedAt: createdAt},
+		{UserID: &userID, ShortURL: "x+5vpM8W", OriginalURL: "https://dzen.ru", Title: "=HYPERLINK(\"https://evil.example\")", Notes: "@SUM(A1)", Tags: []string{"-1+1"}, DeletedFlag: true, CreatedAt: createdAt},
+		{UserID: &userID, ShortURL: "Q0VLhuIm", OriginalURL: "https://vk.com", Title: "\t=1+1", Notes: "\r=1+1", CreatedAt: createdAt},
+	}
+
+	type want struct {
+		statusCode  int
+		contentType string
+		response    string
+	}
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		format string
+		want   want
+	}{
+		{
+			name:   "CSV",
+			userID: userID,
+			format: "csv",
+			want: want{
+				statusCode:  http.StatusOK,
+				contentType: "text/csv",
+				response: "short_url,original_url,title,notes,tags,is_deleted,created_at\n" +
+					"http://localhost:8080/6YGS4ZUF,https://ya.ru,Yandex,,search;main,false,2024-10-01T12:00:00Z\n" +
+					"http://localhost:8080/x+5vpM8W,https://dzen.ru,\"'=HYPERLINK(\"\"https://evil.example\"\")\",'@SUM(A1),'-1+1,true,2024-10-01T12:00:00Z\n" +
+					"http://localhost:8080/Q0VLhuIm,https://vk.com,'\t=1+1,\"'\r=1+1\",,false,2024-10-01T12:00:00Z\n",
+			},
+		},
+		{
+			name:   "NDJSON",
+			userID: userID,
+			format: "ndjson",
+			want: want{
+				statusCode:  http.StatusOK,
+				contentType: "application/x-ndjson",
+				response: `{"short_url":"http://localhost:8080/6YGS4ZUF","original_url":"https://ya.ru","title":"Yandex","notes":"","tags":["search","main"],"is_deleted":false,"created_at":"2024-10-01T12:00:00Z"}
+{"short_url":"http://localhost:8080/x+5vpM8W","original_url":"https://dzen.ru","title":"=HYPERLINK(\"https://evil.example\")","notes":"@SUM(A1)","tags":["-1+1"],"is_deleted":true,"created_at":"2024-10-01T12:00:00Z"}
+{"short_url":"http://localhost:8080/Q0VLhuIm","original_url":"https://vk.com","title":"\t=1+1","notes":"\r=1+1","tags":[],"is_deleted":false,"created_at":"2024-10-01T12:00:00Z"}
+`,
+			},
+		},
+		{
+			name:   "JSON_by_default",
+			userID: userID,
+			want: want{
+				statusCode:  http.StatusOK,
+				contentType: "application/json",
+				response:    `[{"short_url":"http://localhost:8080/6YGS4ZUF","original_url":"https://ya.ru","title":"Yandex","notes":"","tags":["search","main"],"is_deleted":false,"created_at":"2024-10-01T12:00:00Z"},{"short_url":"http://localhost:8080/x+5vpM8W","original_url":"https://dzen.ru","title":"=HYPERLINK(\"https://evil.example\")","notes":"@SUM(A1)","tags":["-1+1"],"is_deleted":true,"created_at":"2024-10-01T12:00:00Z"},{"short_url":"http://localhost:8080/Q0VLhuIm","original_url":"https://vk.com","title":"\t=1+1","notes":"\r=1+1","tags":[],"is_deleted":false,"created_at":"2024-10-01T12:00:00Z"}]`,
+			},
+		},
+		{
+			name:   "Unsupported_format",
+			userID: userID,
+			format: "xml",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "Storage_error",
+			userID: brokenUserID,
+			format: "csv",
+			want: want{
+				statusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			name:   "User_Unauthorized",
+			userID: uuid.UUID{},
+			format: "csv",
+			want: want{
+				statusCode: http.StatusUnauthorized,
+			},
+		},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockStorageDB := mocks.NewMockStorage(mockCtrl)
+	defer mockCtrl.Finish()
+
+	storageURLs := storage.Storage{Storage: mockStorageDB}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	mockStorageDB.EXPECT().IterateUrlsByUser(gomock.Any(), &userID, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID *uuid.UUID, fn func(models.StorageURL) error) error {
+			for _, link := range links {
+				if err := fn(link); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Times(3)
+	mockStorageDB.EXPECT().IterateUrlsByUser(gomock.Any(), &brokenUserID, gomock.Any()).
+		Return(errors.New("connection refused")).Times(1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format="+tt.format, nil)
+			r = r.WithContext(context.WithValue(r.Context(), auth.AuthKey, tt.userID))
+			w := httptest.NewRecorder()
+
+			fn := handler.ExportURLs()
+			fn(w, r)
+
+			result := w.Result()
+			resBody, err := io.ReadAll(result.Body)
+			defer result.Body.Close()
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+			if tt.want.statusCode != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tt.want.contentType, result.Header.Get("Content-Type"))
+			assert.Contains(t, result.Header.Get("Content-Disposition"), "attachment")
+			assert.Equal(t, tt.want.response, string(resBody))
+		})
+	}
+}
+
+func TestHandlers_ExportURLsAborted(t *testing.T) {
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	userID := auth.NewJwtService("verycomplexsecretkey").EnsureRandom()
+
+	mockCtrl := gomock.NewController(t)
+	mockStorageDB := mocks.NewMockStorage(mockCtrl)
+	defer mockCtrl.Finish()
+
+	// хранилище падает после первой ссылки, когда ответ уже начат
+	mockStorageDB.EXPECT().IterateUrlsByUser(gomock.Any(), &userID, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID *uuid.UUID, fn func(models.StorageURL) error) error {
+			if err := fn(models.StorageURL{UserID: userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru"}); err != nil {
+				return err
+			}
+			return errors.New("connection refused")
+		}).Times(3)
+
+	handler := New(shortener_service.NewShortenerService(&storage.Storage{Storage: mockStorageDB}, cfg))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ExportURLs()(w, r.WithContext(context.WithValue(r.Context(), auth.AuthKey, userID)))
+	}))
+	defer server.Close()
+
+	for _, format := range []string{"csv", "ndjson", "json"} {
+		t.Run(format, func(t *testing.T) {
+			res, err := server.Client().Get(server.URL + "/api/user/urls/export?format=" + format)
+			if err == nil {
+				defer res.Body.Close()
+				_, err = io.ReadAll(res.Body)
+			}
+			assert.Error(t, err)
+		})
+	}
+}
@@ -8,15 +8,18 @@ import (
 	"github.com/romanp1989/go-shortener/internal/auth"
 	"github.com/romanp1989/go-shortener/internal/logger"
 	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
 	"github.com/romanp1989/go-shortener/internal/shortener-service"
 	"github.com/romanp1989/go-shortener/internal/storage"
 	"go.uber.org/zap"
 	"io"
 	"net/http"
-	"net/url"
 	"time"
 )
 
+// variantCookiePrefix prefix of cookie name with A/B variant of sticky link, short URL is appended
+const variantCookiePrefix = "variant_"
+
 // Handlers handlers
 type Handlers struct {
 	appService *shortenerservice.ShortenerService
@@ -32,9 +35,10 @@ func New(appService *shortenerservice.ShortenerService) Handlers {
 // Encode handler for creating a shortened URL based on the original one
 // @Accept string
 // @Success 201 {string} short URL
-// @Failure 400 bad request
-// @Failure 401 error if user unauthorized
-// @Failure 409 error if URL already exists in DB
+// @Failure 400 {problem} bad request
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 409 {string} short URL if URL already exists in DB, {problem} if request with idempotency key is in progress
+// @Failure 500 {problem} internal error if URL can't be saved
 func (h *Handlers) Encode() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -43,41 +47,31 @@ func (h *Handlers) Encode() http.HandlerFunc {
 
 		userID := auth.UIDFromContext(ctx)
 		if userID == nil {
-			w.WriteHeader(http.StatusUnauthorized)
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
 			return
 		}
 
 		defer r.Body.Close()
 		body, err := io.ReadAll(r.Body)
 		if err != nil || string(body) == "" {
-			w.WriteHeader(http.StatusBadRequest)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBadRequest, "пустое тело запроса"))
 			return
 		}
 
-		stringURI := string(body)
-
-		if _, err = url.ParseRequestURI(stringURI); err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
-
-		shortURL, err := h.appService.Encode(ctx, stringURI)
-
+		shortURL, err := h.appService.Encode(ctx, string(body))
 		if err != nil {
 			logger.Log.Debug("Ошибка добавления данных", zap.Error(err))
 
+			// API v1 возвращает существующий короткий URL в теле ответа, как и до перехода на problem+json
 			var errConflict *storage.URLConflictError
 			if errors.As(err, &errConflict) {
-				//shortID = errConflict.URL
+				w.Header().Set("Content-Type", "text/plain")
 				w.WriteHeader(http.StatusConflict)
 				w.Write([]byte(shortURL))
 				return
-			} else {
-				w.WriteHeader(http.StatusBadRequest)
-				return
 			}
-		} else {
-			w.WriteHeader(http.StatusCreated)
+			problem.WriteError(w, r, err)
+			return
 		}
 
 		w.Header().Set("Content-Type", "text/plain")
@@ -88,45 +82,99 @@ func (h *Handlers) Encode() http.HandlerFunc {
 	return http.HandlerFunc(fn)
 }
 
-// Decode handler for getting the original URL from short URL
+// Decode handler for getting the original URL from short URL.
+// Preview page is shown instead of redirect for id with "+" suffix or if interstitial is enabled,
+// password form is shown for password-protected link without access cookie.
+// Link outside of active window redirects to its fallback URL, redirect rules of link choose destination by client,
+// otherwise A/B variant is chosen by weight and kept in cookie for sticky link.
+// Query parameters of request are forwarded to destination if enabled for link, UTM parameters of link are appended
 // @Accept string
-// @Success 307 {string} redirect to result URL
-// @Failure 400 bad request
-// @Failure 410 error if URL already deleted
-// @Failure 404 error if URL not found
-// @Failure 409 error if URL already exists in DB
+// @Success 307 {string} redirect to result URL or fallback URL
+// @Success 200 {html} preview page with result URL or password form
+// @Failure 400 {problem} bad request
+// @Failure 410 {problem} error if URL already deleted, clicks limit is reached or active window is ended
+// @Failure 404 {problem} error if URL not found or active window isn't started
+// @Failure 500 {problem} internal error if URL can't be read
 func (h *Handlers) Decode() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Некорректный тип запроса", http.StatusBadRequest)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBadRequest, "Некорректный тип запроса"))
 			return
 		}
 
 		id := chi.URLParam(r, "id")
 
 		if id == "" {
-			w.WriteHeader(http.StatusBadRequest)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBadRequest, "не указан ID url"))
 			return
 		}
+		// одинаковый код на разных доменах ведет на разные ссылки
+		id = h.appService.HostLinkKey(r.Host, id)
 
-		fullURL, err := h.appService.Decode(id)
+		res, err := h.appService.Resolve(r.Context(), id)
 		if err != nil {
-			var errURLDeleted *storage.AlreadyDeleted
-			if errors.As(err, &errURLDeleted) {
-				w.WriteHeader(http.StatusGone)
+			logger.Log.Debug("error resolve url", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+		if res.Fallback != "" {
+			http.Redirect(w, r, res.Fallback, http.StatusTemporaryRedirect)
+			return
+		}
+		if res.Protected && !h.linkAccessGranted(r, res.Link.ID) {
+			writePasswordForm(w, r, res.Link, "", http.StatusOK)
+			return
+		}
+		if len(res.Rules) > 0 {
+			// ответ зависит от заголовков клиента и не должен кэшироваться для всех
+			w.Header().Add("Vary", "User-Agent")
+			w.Header().Add("Vary", "Accept-Language")
+			if h.appService.Cfg.GeoHeader != "" {
+				w.Header().Add("Vary", h.appService.Cfg.GeoHeader)
+			}
+		}
+		target := h.appService.Destination(res, h.clientInfo(r, res.Link.ID))
+		res.Link.OriginalURL = target.URL
+		if res.Preview {
+			// явный предпросмотр не считается переходом и не назначает вариант
+			writePreview(w, r, res.Link)
+			return
+		}
+		if target.Variant != "" && res.StickyVariants {
+			setVariantCookie(w, r, res.Link.ID, target.Variant)
+		}
+		if res.Interstitial {
+			// страница вместо редиректа считается переходом
+			if err = h.appService.CountClick(r.Context(), res.Link.ID); err != nil {
+				problem.WriteError(w, r, err)
 				return
 			}
+			if target.Variant != "" {
+				h.appService.CountVariantClick(r.Context(), res.Link.ID, target.Variant)
+			}
+			writePreview(w, r, res.Link)
+			return
+		}
+
+		fullURL, err := h.appService.Decode(id)
+		if err != nil {
 			logger.Log.Debug("error get url response", zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
+			problem.WriteError(w, r, err)
 			return
 		}
 
 		if fullURL != "" {
+			if target.URL != "" {
+				fullURL = target.URL
+			}
+			if target.Variant != "" {
+				h.appService.CountVariantClick(r.Context(), res.Link.ID, target.Variant)
+			}
 			http.Redirect(w, r, fullURL, http.StatusTemporaryRedirect)
 			return
 		}
 
-		http.Error(w, "Не найден url для указанного ID", http.StatusNotFound)
+		problem.Write(w, r, problem.New(http.StatusNotFound, problem.CodeNotFound, "Не найден url для указанного ID"))
 	}
 	return http.HandlerFunc(fn)
 }
@@ -134,9 +182,10 @@ func (h *Handlers) Decode() http.HandlerFunc {
 // Shorten handler for creating a shortened URL based on the original one
 // @Accept json
 // @Success 201 {json} short URL json
-// @Failure 500 internal error if can't decode request body
-// @Failure 401 error if user unauthorized
-// @Failure 409 error if URL already exists in DB
+// @Failure 400 {problem} bad request if can't decode request body or URL is invalid
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 409 {json} short URL json if URL already exists in DB, {problem} if request with idempotency key is in progress
+// @Failure 500 {problem} internal error if URL can't be saved
 func (h *Handlers) Shorten() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		logger.Log.Debug("decoding request")
@@ -147,8 +196,7 @@ func (h *Handlers) Shorten() http.HandlerFunc {
 
 		userID := auth.UIDFromContext(ctx)
 		if userID == nil {
-			w.WriteHeader(http.StatusUnauthorized)
-
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
 			return
 		}
 
@@ -156,59 +204,41 @@ func (h *Handlers) Shorten() http.HandlerFunc {
 		dec := json.NewDecoder(r.Body)
 		if err := dec.Decode(&req); err != nil {
 			logger.Log.Debug("cannot decode request JSON body", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
 			return
 		}
 
-		shortURL, err := h.appService.Shorten(r.Context(), req.URL, userID)
+		shortURL, err := h.appService.ShortenLink(r.Context(), models.StorageURL{
+			UserID:      userID,
+			OriginalURL: req.URL,
+			Title:       req.Title,
+			Notes:       req.Notes,
+			Tags:        req.Tags,
+			Domain:      req.Domain,
+		})
 		if err != nil {
 			logger.Log.Debug("Ошибка добавления данных", zap.Error(err))
 
+			// API v1 возвращает существующий короткий URL в теле ответа, как и до перехода на problem+json
 			var errConflict *storage.URLConflictError
 			if errors.As(err, &errConflict) {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusConflict)
-
-				shortenResponse := models.ShortenResponse{
-					Result: shortURL,
-				}
-				enc := json.NewEncoder(w)
-				if err := enc.Encode(shortenResponse); err != nil {
-					logger.Log.Debug("Ошибка создания ответа", zap.Error(err))
-					w.WriteHeader(http.StatusBadRequest)
-					return
-				}
-
-				return
-			} else {
-
-				w.WriteHeader(http.StatusBadRequest)
-
-				shortenResponse := models.ShortenResponse{
-					Result: "",
-				}
-				enc := json.NewEncoder(w)
-				if err := enc.Encode(shortenResponse); err != nil {
-					logger.Log.Debug("Ошибка создания ответа", zap.Error(err))
-					w.WriteHeader(http.StatusBadRequest)
-					return
-				}
-
+				writeJSON(w, r, http.StatusConflict, models.ShortenResponse{Result: shortURL})
 				return
 			}
-		} else {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusCreated)
+			problem.WriteError(w, r, err)
+			return
 		}
 
 		shortenResponse := models.ShortenResponse{
 			Result: shortURL,
 		}
 
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
 		enc := json.NewEncoder(w)
 		if err := enc.Encode(shortenResponse); err != nil {
 			logger.Log.Debug("Ошибка создания ответа", zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 		logger.Log.Debug("sending HTTP 200 response")
@@ -219,9 +249,10 @@ func (h *Handlers) Shorten() http.HandlerFunc {
 
 // SaveBatch handler for creating a shortened URL based on the original one
 // @Accept json
-// @Success 201 {json} list of short URLs json
-// @Failure 400 bad request error if can't decode request body
-// @Failure 401 error if user unauthorized
+// @Success 201 {json} list of short URLs json with status of every item
+// @Failure 400 {problem} bad request error if can't decode request body or all URLs are invalid, item results are in items member
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 500 {problem} internal error if URLs can't be saved
 func (h *Handlers) SaveBatch() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		var batchReq []models.BatchShortenRequest
@@ -234,13 +265,17 @@ func (h *Handlers) SaveBatch() http.HandlerFunc {
 
 		userID := auth.UIDFromContext(ctx)
 		if userID == nil {
-			w.WriteHeader(http.StatusUnauthorized)
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
 			return
 		}
 
 		err = json.NewDecoder(r.Body).Decode(&batchReq)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+		if len(batchReq) == 0 {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeEmptyBatch, "пустой список URL"))
 			return
 		}
 
@@ -248,7 +283,20 @@ func (h *Handlers) SaveBatch() http.HandlerFunc {
 
 		if err != nil {
 			logger.Log.Debug("error urls save", zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		// частичный успех возвращается с кодом 201, если не сохранен ни один URL - 400
+		saved := false
+		for _, item := range resp {
+			if item.Status != models.BatchStatusInvalid {
+				saved = true
+				break
+			}
+		}
+		if !saved {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBatchInvalid, "все URL некорректны").With("items", resp))
 			return
 		}
 
@@ -258,7 +306,6 @@ func (h *Handlers) SaveBatch() http.HandlerFunc {
 		enc := json.NewEncoder(w)
 		if err := enc.Encode(resp); err != nil {
 			logger.Log.Debug("error encoding response", zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 		logger.Log.Debug("sending HTTP 200 response")
@@ -266,3 +313,34 @@ func (h *Handlers) SaveBatch() http.HandlerFunc {
 
 	return http.HandlerFunc(fn)
 }
+
+// clientInfo function returns request attributes for redirect rules, A/B variants and query forwarding of link,
+// country is read from configured geo header
+func (h *Handlers) clientInfo(r *http.Request, id string) models.ClientInfo {
+	client := models.ClientInfo{
+		UserAgent:      r.UserAgent(),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+		Query:          r.URL.RawQuery,
+	}
+	if h.appService.Cfg.GeoHeader != "" {
+		client.Country = r.Header.Get(h.appService.Cfg.GeoHeader)
+	}
+	if cookie, err := r.Cookie(variantCookiePrefix + shortenerservice.LinkCode(id)); err == nil {
+		client.Variant = cookie.Value
+	}
+	return client
+}
+
+// setVariantCookie function saves A/B variant assigned to client for sticky link,
+// cookie is bound to host, so short code without domain is used in its name
+func setVariantCookie(w http.ResponseWriter, r *http.Request, id string, variant string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     variantCookiePrefix + shortenerservice.LinkCode(id),
+		Value:    variant,
+		Path:     "/",
+		MaxAge:   int(shortenerservice.VariantCookieTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
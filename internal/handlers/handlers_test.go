@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
@@ -90,7 +91,7 @@ func TestEncode(t *testing.T) {
 			requestBody: "https://ya.ru",
 			want: want{
 				statusCode:  http.StatusConflict,
-				responseURL: "",
+				responseURL: "http://localhost:8080/6YGS4ZUF",
 			},
 		},
 		{
@@ -99,7 +100,7 @@ func TestEncode(t *testing.T) {
 			userID:      jwtService.EnsureRandom(),
 			requestBody: "https://ya.ru",
 			want: want{
-				statusCode:  http.StatusBadRequest,
+				statusCode:  http.StatusInternalServerError,
 				responseURL: "",
 			},
 		},
@@ -222,16 +223,18 @@ func TestDecode(t *testing.T) {
 			userID:   jwtService.EnsureRandom(),
 			shortURL: fourthShort,
 			want: want{
-				statusCode:  http.StatusBadRequest,
+				statusCode:  http.StatusInternalServerError,
 				responseURL: "",
 			},
 		},
 	}
 
 	mockStorageDB.EXPECT().Get(firstShort).Return(firstOriginalURL, nil).Times(1)
+	mockStorageDB.EXPECT().IncrementClicks(gomock.Any(), firstShort).Return(nil).Times(1)
 	mockStorageDB.EXPECT().Get(secondShort).Return("", nil).Times(1)
 	mockStorageDB.EXPECT().Get(thirdShort).Return("", storage.NewAlreadyDeletedError(thirdShort)).Times(1)
 	mockStorageDB.EXPECT().Get(fourthShort).Return("", errors.New("error get url response")).Times(1)
+	mockStorageDB.EXPECT().GetLink(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -305,7 +308,7 @@ func TestShorten(t *testing.T) {
 			userID:      jwtService.EnsureRandom(),
 			requestBody: `343434{"url": "https://ya.ru"}`,
 			want: want{
-				statusCode:  http.StatusInternalServerError,
+				statusCode:  http.StatusBadRequest,
 				responseURL: "",
 			},
 		},
@@ -316,7 +319,7 @@ func TestShorten(t *testing.T) {
 			requestBody: `{"url": "https://ya.ru"}`,
 			want: want{
 				statusCode:  http.StatusConflict,
-				responseURL: "",
+				responseURL: `{"result":"http://localhost:8080/6YGS4ZUF"}`,
 			},
 		},
 		{
@@ -325,7 +328,7 @@ func TestShorten(t *testing.T) {
 			userID:      jwtService.EnsureRandom(),
 			requestBody: `{"url": "https://ya.ru"}`,
 			want: want{
-				statusCode:  http.StatusBadRequest,
+				statusCode:  http.StatusInternalServerError,
 				responseURL: "",
 			},
 		},
@@ -339,9 +342,10 @@ func TestShorten(t *testing.T) {
 	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
 	handler := New(appService)
 
-	firstCall := mockStorageDB.EXPECT().Save(gomock.Any(), "https://ya.ru", "6YGS4ZUF", gomock.Any()).Return("6YGS4ZUF", nil)
-	secondCall := mockStorageDB.EXPECT().Save(gomock.Any(), "https://ya.ru", "6YGS4ZUF", gomock.Any()).After(firstCall).Return("", storage.NewURLConflictError("6YGS4ZUF", storage.ErrConflict))
-	mockStorageDB.EXPECT().Save(gomock.Any(), "https://ya.ru", "6YGS4ZUF", gomock.Any()).After(secondCall).Return("", errors.New("Ошибка вставки URL в БД"))
+	link := linkMatcher{originalURL: "https://ya.ru", shortURL: "6YGS4ZUF"}
+	firstCall := mockStorageDB.EXPECT().SaveLink(gomock.Any(), link).Return("6YGS4ZUF", nil)
+	secondCall := mockStorageDB.EXPECT().SaveLink(gomock.Any(), link).After(firstCall).Return("", storage.NewURLConflictError("6YGS4ZUF", storage.ErrConflict))
+	mockStorageDB.EXPECT().SaveLink(gomock.Any(), link).After(secondCall).Return("", errors.New("Ошибка вставки URL в БД"))
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -374,6 +378,21 @@ func TestShorten(t *testing.T) {
 	}
 }
 
+// linkMatcher matches saved URL by original and short URL
+type linkMatcher struct {
+	originalURL string
+	shortURL    string
+}
+
+func (m linkMatcher) Matches(x interface{}) bool {
+	link, ok := x.(models.StorageURL)
+	return ok && link.OriginalURL == m.originalURL && link.ShortURL == m.shortURL
+}
+
+func (m linkMatcher) String() string {
+	return fmt.Sprintf("is link %s -> %s", m.shortURL, m.originalURL)
+}
+
 func TestHandlers_SaveBatch(t *testing.T) {
 	type want struct {
 		statusCode  int
@@ -412,11 +431,13 @@ func TestHandlers_SaveBatch(t *testing.T) {
 				responseURL: `[
 					{
 						"correlation_id": "ssdfdsfsfsd",
-						"short_url": "http://localhost:8080/6YGS4ZUF"
+						"short_url": "http://localhost:8080/6YGS4ZUF",
+						"status": "existing"
 					},
 					{
 						"correlation_id": "rtyuiookjhtr",
-						"short_url": "http://localhost:8080/x+5vpM8W"
+						"short_url": "http://localhost:8080/x+5vpM8W",
+						"status": "created"
 					}
 				]`,
 			},
@@ -447,8 +468,48 @@ func TestHandlers_SaveBatch(t *testing.T) {
 				}				
 			] `,
 			want: want{
-				statusCode:  http.StatusBadRequest,
-				responseURL: "",
+				statusCode: http.StatusBadRequest,
+				responseURL: `{
+					"type": "about:blank",
+					"title": "Bad Request",
+					"status": 400,
+					"code": "batch_invalid",
+					"detail": "все URL некорректны",
+					"instance": "/shorten/batch",
+					"items": [{"correlation_id": "ssdfdsfsfsd", "status": "invalid", "error": "некорректный URL"}]
+				}`,
+			},
+		},
+		{
+			name:   "Partial_success",
+			method: http.MethodPost,
+			userID: jwtService.EnsureRandom(),
+			requestBody: `[
+				{
+					"correlation_id": "first",
+					"original_url": "gdfgdg dfgdfgfd"
+				},
+				{
+					"correlation_id": "second",
+					"original_url": "https://mail.ru"
+				},
+				{
+					"correlation_id": "third",
+					"original_url": "https://mail.ru"
+				},
+				{
+					"correlation_id": "fourth",
+					"original_url": "https://deleted.ru"
+				}
+			]`,
+			want: want{
+				statusCode: http.StatusCreated,
+				responseURL: `[
+					{"correlation_id": "first", "status": "invalid", "error": "некорректный URL"},
+					{"correlation_id": "second", "short_url": "http://localhost:8080/Gi0zPCNe", "status": "created"},
+					{"correlation_id": "third", "short_url": "http://localhost:8080/Gi0zPCNe", "status": "created"},
+					{"correlation_id": "fourth", "status": "invalid", "error": "URL удален"}
+				]`,
 			},
 		},
 		{
@@ -458,33 +519,20 @@ func TestHandlers_SaveBatch(t *testing.T) {
 			requestBody: `[
 				{
 					"correlation_id": "ssdfdsfsfsd",
-					"original_url": "https://ya.ru"
+					"original_url": "https://lenta.ru"
 				},
 				{
 					"correlation_id": "ssdfdsfsfsd",
-					"original_url": "https://ya.ru"
+					"original_url": "https://lenta.ru"
 				}
 			]`,
 			want: want{
-				statusCode:  http.StatusBadRequest,
+				statusCode:  http.StatusInternalServerError,
 				responseURL: ``,
 			},
 		},
 	}
 
-	urlsForSaveErrors := []models.StorageURL{
-		{
-			UserID:      nil,
-			OriginalURL: "https://ya.ru",
-			ShortURL:    "6YGS4ZUF",
-		},
-		{
-			UserID:      nil,
-			OriginalURL: "https://ya.ru",
-			ShortURL:    "6YGS4ZUF",
-		},
-	}
-
 	mockCtrl := gomock.NewController(t)
 	mockStorageDB := mocks.NewMockStorage(mockCtrl)
 	defer mockCtrl.Finish()
@@ -493,11 +541,22 @@ func TestHandlers_SaveBatch(t *testing.T) {
 	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
 	handler := New(appService)
 
-	savedUrls := []string{"6YGS4ZUF", "x+5vpM8W"}
-	mockStorageDB.EXPECT().Get("https://ya.ru").Return("6YGS4ZUF", nil).Times(3)
-	mockStorageDB.EXPECT().Get("https://dzen.ru").Return("x+5vpM8W", nil)
+	urlsForSaveErrors := []models.StorageURL{
+		{
+			UserID:      nil,
+			OriginalURL: "https://lenta.ru",
+			ShortURL:    appService.ShortURL("https://lenta.ru"),
+		},
+	}
+
+	mockStorageDB.EXPECT().Get("6YGS4ZUF").Return("https://ya.ru", nil).Times(1)
+	mockStorageDB.EXPECT().Get(appService.ShortURL("https://dzen.ru")).Return("", nil)
+	mockStorageDB.EXPECT().Get(appService.ShortURL("https://mail.ru")).Return("", nil)
+	mockStorageDB.EXPECT().Get(appService.ShortURL("https://deleted.ru")).Return("", storage.NewAlreadyDeletedError(appService.ShortURL("https://deleted.ru")))
+	mockStorageDB.EXPECT().Get(appService.ShortURL("https://lenta.ru")).Return("", nil)
+	mockStorageDB.EXPECT().SaveBatch(gomock.Any(), []models.StorageURL{{OriginalURL: "https://dzen.ru", ShortURL: appService.ShortURL("https://dzen.ru")}}, gomock.Any()).Return([]string{"x+5vpM8W"}, nil)
+	mockStorageDB.EXPECT().SaveBatch(gomock.Any(), []models.StorageURL{{OriginalURL: "https://mail.ru", ShortURL: appService.ShortURL("https://mail.ru")}}, gomock.Any()).Return([]string{"Gi0zPCNe"}, nil)
 	mockStorageDB.EXPECT().SaveBatch(gomock.Any(), urlsForSaveErrors, gomock.Any()).Return(nil, errors.New("ошибка при вставке записей"))
-	mockStorageDB.EXPECT().SaveBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(savedUrls, nil)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
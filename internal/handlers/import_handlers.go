@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// importChunkSize count of rows saved to storage with one batch request
+const importChunkSize = 500
+
+// maxImportLineSize max size of NDJSON line, longer line is reported as invalid row
+const maxImportLineSize = 64 * 1024
+
+// importReader reads bulk import rows one by one, io.EOF is returned after the last row
+type importReader interface {
+	next() (models.ImportRow, error)
+}
+
+// csvImportReader reader for text/csv import.
+// Header row with original_url and correlation_id columns is optional,
+// without header the first column is original URL and the second is correlation ID
+type csvImportReader struct {
+	reader         *csv.Reader
+	urlColumn      int
+	idColumn       int
+	headerResolved bool
+}
+
+// newCSVImportReader factory for create CSV import reader
+func newCSVImportReader(r io.Reader) *csvImportReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	return &csvImportReader{
+		reader:   reader,
+		idColumn: 1,
+	}
+}
+
+func (c *csvImportReader) next() (models.ImportRow, error) {
+	for {
+		record, err := c.reader.Read()
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return models.ImportRow{Line: parseErr.StartLine, Error: parseErr.Error()}, nil
+		}
+		if err != nil {
+			return models.ImportRow{}, err
+		}
+
+		if !c.headerResolved {
+			c.headerResolved = true
+			if c.resolveHeader(record) {
+				continue
+			}
+		}
+
+		line, _ := c.reader.FieldPos(0)
+		row := models.ImportRow{Line: line}
+		if c.urlColumn < len(record) {
+			row.OriginalURL = record[c.urlColumn]
+		}
+		if c.idColumn >= 0 && c.idColumn < len(record) {
+			row.CorrelationID = record[c.idColumn]
+		}
+
+		return row, nil
+	}
+}
+
+// resolveHeader function finds columns positions in header row, returns false if row isn't a header
+func (c *csvImportReader) resolveHeader(record []string) bool {
+	urlColumn, idColumn := -1, -1
+	for i, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "original_url":
+			urlColumn = i
+		case "correlation_id":
+			idColumn = i
+		}
+	}
+
+	if urlColumn < 0 {
+		return false
+	}
+
+	c.urlColumn, c.idColumn = urlColumn, idColumn
+	return true
+}
+
+// ndjsonImportReader reader for application/x-ndjson import, each line is a batch shorten request object
+type ndjsonImportReader struct {
+	reader *bufio.Reader
+	line   int
+}
+
+// newNDJSONImportReader factory for create NDJSON import reader
+func newNDJSONImportReader(r io.Reader) *ndjsonImportReader {
+	return &ndjsonImportReader{reader: bufio.NewReaderSize(r, maxImportLineSize)}
+}
+
+func (n *ndjsonImportReader) next() (models.ImportRow, error) {
+	for {
+		data, err := n.reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			n.line++
+			// остаток длинной строки пропускается, импорт продолжается со следующей строки
+			if err = n.skipLine(); err != nil && !errors.Is(err, io.EOF) {
+				return models.ImportRow{}, err
+			}
+			return models.ImportRow{Line: n.line, Error: "строка длиннее 64 КБ"}, nil
+		}
+		if len(data) == 0 && err != nil {
+			return models.ImportRow{}, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return models.ImportRow{}, err
+		}
+		n.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		row := models.ImportRow{Line: n.line}
+		if err := json.Unmarshal(data, &row); err != nil {
+			return models.ImportRow{Line: n.line, Error: "некорректный JSON"}, nil
+		}
+
+		return row, nil
+	}
+}
+
+// skipLine function reads rest of current line
+func (n *ndjsonImportReader) skipLine() error {
+	for {
+		if _, err := n.reader.ReadSlice('\n'); !errors.Is(err, bufio.ErrBufferFull) {
+			return err
+		}
+	}
+}
+
+// Import handler for streaming bulk import of URLs from CSV or NDJSON.
+// Rows are saved by chunks, result of every row is streamed back as NDJSON line
+// @Accept text/csv, application/x-ndjson
+// @Success 200 {ndjson} result for every imported row
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 415 {problem} unsupported request content type
+func (h *Handlers) Import() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var reader importReader
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			reader = newCSVImportReader(r.Body)
+		case "application/x-ndjson":
+			reader = newNDJSONImportReader(r.Body)
+		default:
+			problem.Write(w, r, problem.New(http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, "Поддерживаются только text/csv и application/x-ndjson"))
+			return
+		}
+		defer r.Body.Close()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		rc := http.NewResponseController(w)
+		chunk := make([]models.ImportRow, 0, importChunkSize)
+
+		saveChunk := func() error {
+			results, err := h.appService.ImportURLs(ctx, chunk, userID)
+			if err != nil {
+				return err
+			}
+			chunk = chunk[:0]
+
+			for _, result := range results {
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			}
+			_ = rc.Flush()
+
+			return nil
+		}
+
+		for {
+			row, err := reader.next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				logger.Log.Debug("Ошибка чтения строки импорта", zap.Error(err))
+				_ = enc.Encode(models.ImportResult{Line: row.Line, Error: err.Error()})
+				return
+			}
+
+			chunk = append(chunk, row)
+			if len(chunk) < importChunkSize {
+				continue
+			}
+
+			if err := saveChunk(); err != nil {
+				logger.Log.Debug("Ошибка импорта urls", zap.Error(err))
+				_ = enc.Encode(models.ImportResult{Error: err.Error()})
+				return
+			}
+		}
+
+		if len(chunk) > 0 {
+			if err := saveChunk(); err != nil {
+				logger.Log.Debug("Ошибка импорта urls", zap.Error(err))
+				_ = enc.Encode(models.ImportResult{Error: err.Error()})
+			}
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
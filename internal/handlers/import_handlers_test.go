@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/models/mocks"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlers_Import(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+
+	type want struct {
+		statusCode int
+		response   string
+	}
+
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		contentType string
+		requestBody string
+		want        want
+	}{
+		{
+			name:        "CSV_with_header",
+			userID:      jwtService.EnsureRandom(),
+			contentType: "text/csv",
+			requestBody: "correlation_id,original_url\nfirst,https://ya.ru\nsecond,not url\nthird,https://dzen.ru\n",
+			want: want{
+				statusCode: http.StatusOK,
+				response: `{"line":2,"correlation_id":"first","original_url":"https://ya.ru","short_url":"http://localhost:8080/6YGS4ZUF","status":"created"}
+{"line":3,"correlation_id":"second","original_url":"not url","status":"invalid","error":"некорректный URL"}
+{"line":4,"correlation_id":"third","original_url":"https://dzen.ru","short_url":"http://localhost:8080/x+5vpM8W","status":"created"}
+`,
+			},
+		},
+		{
+			name:        "NDJSON",
+			userID:      jwtService.EnsureRandom(),
+			contentType: "application/x-ndjson",
+			requestBody: `{"correlation_id":"first","original_url":"https://ya.ru"}
+{"correlation_id":
+{"correlation_id":"long","original_url":"https://ya.ru/` + strings.Repeat("a", maxImportLineSize) + `"}
+{"correlation_id":"third","original_url":"https://ya.ru"}
+{"correlation_id":"fourth","original_url":"https://vk.com"}
+`,
+			want: want{
+				statusCode: http.StatusOK,
+				response: `{"line":1,"correlation_id":"first","original_url":"https://ya.ru","short_url":"http://localhost:8080/6YGS4ZUF","status":"existing"}
+{"line":2,"original_url":"","status":"invalid","error":"некорректный JSON"}
+{"line":3,"original_url":"","status":"invalid","error":"строка длиннее 64 КБ"}
+{"line":4,"correlation_id":"third","original_url":"https://ya.ru","short_url":"http://localhost:8080/6YGS4ZUF","status":"existing"}
+{"line":5,"correlation_id":"fourth","original_url":"https://vk.com","short_url":"http://localhost:8080/+kRIwT4G","status":"created"}
+`,
+			},
+		},
+		{
+			name:        "Unsupported_content_type",
+			userID:      jwtService.EnsureRandom(),
+			contentType: "application/json",
+			requestBody: `[]`,
+			want: want{
+				statusCode: http.StatusUnsupportedMediaType,
+			},
+		},
+		{
+			name:        "User_Unauthorized",
+			userID:      uuid.UUID{},
+			contentType: "text/csv",
+			requestBody: "https://ya.ru",
+			want: want{
+				statusCode: http.StatusUnauthorized,
+			},
+		},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockStorageDB := mocks.NewMockStorage(mockCtrl)
+	defer mockCtrl.Finish()
+
+	storageURLs := storage.Storage{Storage: mockStorageDB}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	// существующие ссылки не сохраняются повторно
+	firstGet := mockStorageDB.EXPECT().Get("6YGS4ZUF").Return("", nil)
+	mockStorageDB.EXPECT().Get("6YGS4ZUF").After(firstGet).Return("https://ya.ru", nil)
+	mockStorageDB.EXPECT().Get("x+5vpM8W").Return("", nil)
+	mockStorageDB.EXPECT().Get("+kRIwT4G").Return("", nil)
+
+	firstCall := mockStorageDB.EXPECT().SaveBatch(gomock.Any(), []models.StorageURL{
+		{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF"},
+		{OriginalURL: "https://dzen.ru", ShortURL: "x+5vpM8W"},
+	}, gomock.Any()).Return([]string{"6YGS4ZUF", "x+5vpM8W"}, nil)
+	mockStorageDB.EXPECT().SaveBatch(gomock.Any(), []models.StorageURL{
+		{OriginalURL: "https://vk.com", ShortURL: "+kRIwT4G"},
+	}, gomock.Any()).After(firstCall).Return([]string{"+kRIwT4G"}, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/shorten/import", strings.NewReader(tt.requestBody))
+			r.Header.Set("Content-Type", tt.contentType)
+			r = r.WithContext(context.WithValue(r.Context(), auth.AuthKey, tt.userID))
+
+			w := httptest.NewRecorder()
+
+			fn := handler.Import()
+			fn(w, r)
+
+			result := w.Result()
+			resBody, err := io.ReadAll(result.Body)
+			defer result.Body.Close()
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+			if tt.want.response != "" {
+				assert.Equal(t, tt.want.response, string(resBody))
+			}
+		})
+	}
+}
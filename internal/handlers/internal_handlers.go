@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/problem"
 	"go.uber.org/zap"
 	"net/http"
 	"time"
@@ -19,13 +20,13 @@ func (h *Handlers) GetStats() http.HandlerFunc {
 		stats, err := h.appService.GetStats(ctx)
 		if err != nil {
 			logger.Log.Debug("Ошибка при получении статистики", zap.Error(err))
-			w.WriteHeader(http.StatusNoContent)
+			problem.WriteError(w, r, err)
 			return
 		}
 
 		b, err := json.Marshal(stats)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.WriteError(w, r, err)
 			return
 		}
 
@@ -36,3 +37,26 @@ func (h *Handlers) GetStats() http.HandlerFunc {
 
 	return http.HandlerFunc(fn)
 }
+
+// GetScheduledLinks handler for admin list of links with active window of all users
+// @Success 200 {json} list of scheduled links with state
+// @Failure 403 {string} error if client isn't in trusted subnet
+// @Failure 500 {problem} internal error if links can't be read
+func (h *Handlers) GetScheduledLinks() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		links, err := h.appService.GetScheduledLinks(r.Context())
+		if err != nil {
+			logger.Log.Debug("Ошибка при получении запланированных ссылок", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(links); err != nil {
+			logger.Log.Debug("Ошибка создания ответа", zap.Error(err))
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// envelope structure for v2 API responses, resource or list is in data field
+type envelope struct {
+	Data any `json:"data"`
+	Meta any `json:"meta,omitempty"`
+}
+
+// writeEnvelope function writes v2 API response, encoding error is written as problem response
+func writeEnvelope(w http.ResponseWriter, r *http.Request, statusCode int, data any, meta any) {
+	b, err := json.Marshal(envelope{Data: data, Meta: meta})
+	if err != nil {
+		logger.Log.Debug("Ошибка создания ответа", zap.Error(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(b)
+}
+
+// CreateLink handler for creating link resource of v2 API
+// @Accept json
+// @Success 201 {json} created link in data field
+// @Failure 400 {problem} bad request if can't decode request body or URL is invalid
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 409 {problem} error if URL already exists, link id is in id member
+func (h *Handlers) CreateLink() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.LinkCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		link, err := h.appService.CreateLink(ctx, userID, req)
+		if err != nil {
+			logger.Log.Debug("Ошибка создания ссылки", zap.Error(err))
+
+			p := problem.From(err)
+			if p.Code == problem.CodeURLConflict {
+				p.With("id", link.ID).With("short_url", link.ShortURL)
+			}
+			problem.Write(w, r, p)
+			return
+		}
+
+		w.Header().Set("Location", r.URL.Path+"/"+link.ID)
+		writeEnvelope(w, r, http.StatusCreated, link, nil)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// GetLink handler for getting user's link resource of v2 API by id
+// @Accept string link id
+// @Success 200 {json} link in data field
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if link not found
+// @Failure 410 {problem} error if link deleted
+func (h *Handlers) GetLink() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		link, err := h.appService.GetLink(ctx, userID, chi.URLParam(r, "id"))
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeEnvelope(w, r, http.StatusOK, link, nil)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// ListLinks handler for getting page of user's links of v2 API, links of user's workspace are listed with workspace query param
+// @Accept string query params limit, offset and workspace
+// @Success 200 {json} links in data field, pagination in meta field
+// @Failure 400 {problem} bad request if limit or offset is invalid
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+func (h *Handlers) ListLinks() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		limit, errLimit := queryInt(r, "limit")
+		offset, errOffset := queryInt(r, "offset")
+		if errLimit != nil || errOffset != nil {
+			problem.WriteError(w, r, shortenerservice.ErrInvalidPagination)
+			return
+		}
+
+		var links []models.Link
+		var meta models.LinkListMeta
+		var err error
+		if workspaceID := r.URL.Query().Get("workspace"); workspaceID != "" {
+			links, meta, err = h.appService.ListWorkspaceLinks(ctx, userID, workspaceID, limit, offset)
+		} else {
+			links, meta, err = h.appService.ListLinks(ctx, userID, limit, offset)
+		}
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeEnvelope(w, r, http.StatusOK, links, meta)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// UpdateLink handler for updating title, notes and tags of user's link of v2 API
+// @Accept json
+// @Success 200 {json} updated link in data field
+// @Failure 400 {problem} bad request if can't decode request body
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if link not found
+// @Failure 409 {problem} error if password or clicks limit is set to link with code derived from URL
+// @Failure 410 {problem} error if link deleted
+func (h *Handlers) UpdateLink() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.LinkUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		link, err := h.appService.UpdateLink(ctx, userID, chi.URLParam(r, "id"), req)
+		if err != nil {
+			logger.Log.Debug("Ошибка изменения ссылки", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeEnvelope(w, r, http.StatusOK, link, nil)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// DeleteLink handler for deleting user's link of v2 API
+// @Accept string link id
+// @Success 204 link is deleted
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if link not found
+// @Failure 410 {problem} error if link already deleted
+func (h *Handlers) DeleteLink() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		if err := h.appService.DeleteLink(ctx, userID, chi.URLParam(r, "id")); err != nil {
+			logger.Log.Debug("Ошибка удаления ссылки", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// GetLinkInfo handler for inspecting short link without redirect, metadata is returned for owner only
+// @Accept string link id
+// @Success 200 {json} link info
+// @Failure 404 {problem} error if link not found
+// @Failure 500 {problem} internal error if link can't be read
+func (h *Handlers) GetLinkInfo() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		info, err := h.appService.GetLinkInfo(ctx, auth.UIDFromContext(ctx), chi.URLParam(r, "id"))
+		if err != nil {
+			logger.Log.Debug("Ошибка получения информации о ссылке", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		resp, err := json.Marshal(info)
+		if err != nil {
+			logger.Log.Debug("Ошибка создания ответа", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(resp)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// queryInt function parses integer query param, missing param is zero
+func queryInt(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
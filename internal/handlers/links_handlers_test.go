@@ -0,0 +1,761 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlers_Links(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+	stranger := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Post("/api/v2/links", handler.CreateLink())
+	r.Get("/api/v2/links", handler.ListLinks())
+	r.Get("/api/v2/links/{id}", handler.GetLink())
+	r.Patch("/api/v2/links/{id}", handler.UpdateLink())
+	r.Delete("/api/v2/links/{id}", handler.DeleteLink())
+
+	type want struct {
+		statusCode  int
+		contentType string
+		response    string
+	}
+
+	// шаги выполняются последовательно на общем хранилище
+	tests := []struct {
+		name   string
+		method string
+		target string
+		userID uuid.UUID
+		body   string
+		want   want
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			target: "/api/v2/links",
+			userID: owner,
+			body:   `{"original_url":"https://ya.ru","title":"Яндекс","tags":["search"]}`,
+			want: want{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json",
+				response:    `"data":{"id":"6YGS4ZUF","short_url":"http://localhost:8080/6YGS4ZUF","original_url":"https://ya.ru","title":"Яндекс","notes":"","tags":["search"]`,
+			},
+		},
+		{
+			name:   "Create_conflict",
+			method: http.MethodPost,
+			target: "/api/v2/links",
+			userID: stranger,
+			body:   `{"original_url":"https://ya.ru"}`,
+			want: want{
+				statusCode:  http.StatusConflict,
+				contentType: "application/problem+json",
+				response:    `"short_url":"http://localhost:8080/6YGS4ZUF"`,
+			},
+		},
+		{
+			name:   "Create_invalid_URL",
+			method: http.MethodPost,
+			target: "/api/v2/links",
+			userID: owner,
+			body:   `{"original_url":"not url"}`,
+			want: want{
+				statusCode:  http.StatusBadRequest,
+				contentType: "application/problem+json",
+				response:    `"code":"invalid_url"`,
+			},
+		},
+		{
+			name:   "Create_unauthorized",
+			method: http.MethodPost,
+			target: "/api/v2/links",
+			userID: uuid.UUID{},
+			body:   `{"original_url":"https://dzen.ru"}`,
+			want: want{
+				statusCode:  http.StatusUnauthorized,
+				contentType: "application/problem+json",
+			},
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			target: "/api/v2/links/6YGS4ZUF",
+			userID: owner,
+			want: want{
+				statusCode:  http.StatusOK,
+				contentType: "application/json",
+				response:    `"title":"Яндекс"`,
+			},
+		},
+		{
+			name:   "Get_other_user",
+			method: http.MethodGet,
+			target: "/api/v2/links/6YGS4ZUF",
+			userID: stranger,
+			want: want{
+				statusCode:  http.StatusNotFound,
+				contentType: "application/problem+json",
+				response:    `"code":"not_found"`,
+			},
+		},
+		{
+			name:   "Update",
+			method: http.MethodPatch,
+			target: "/api/v2/links/6YGS4ZUF",
+			userID: owner,
+			body:   `{"notes":"поиск","tags":[]}`,
+			want: want{
+				statusCode:  http.StatusOK,
+				contentType: "application/json",
+				response:    `"title":"Яндекс","notes":"поиск","tags":[]`,
+			},
+		},
+		{
+			name:   "List",
+			method: http.MethodGet,
+			target: "/api/v2/links?limit=10",
+			userID: owner,
+			want: want{
+				statusCode:  http.StatusOK,
+				contentType: "application/json",
+				response:    `"meta":{"total":1,"limit":10,"offset":0}`,
+			},
+		},
+		{
+			name:   "List_invalid_limit",
+			method: http.MethodGet,
+			target: "/api/v2/links?limit=ten",
+			userID: owner,
+			want: want{
+				statusCode:  http.StatusBadRequest,
+				contentType: "application/problem+json",
+				response:    `"code":"invalid_pagination"`,
+			},
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			target: "/api/v2/links/6YGS4ZUF",
+			userID: owner,
+			want: want{
+				statusCode: http.StatusNoContent,
+			},
+		},
+		{
+			name:   "Get_deleted",
+			method: http.MethodGet,
+			target: "/api/v2/links/6YGS4ZUF",
+			userID: owner,
+			want: want{
+				statusCode:  http.StatusGone,
+				contentType: "application/problem+json",
+				response:    `"code":"url_deleted"`,
+			},
+		},
+		{
+			name:   "List_after_delete",
+			method: http.MethodGet,
+			target: "/api/v2/links",
+			userID: owner,
+			want: want{
+				statusCode:  http.StatusOK,
+				contentType: "application/json",
+				response:    `{"data":[],"meta":{"total":0,"limit":50,"offset":0}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, tt.userID))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			result := w.Result()
+			resBody, err := io.ReadAll(result.Body)
+			defer result.Body.Close()
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+			assert.Equal(t, tt.want.contentType, result.Header.Get("Content-Type"))
+			assert.Contains(t, string(resBody), tt.want.response)
+		})
+	}
+}
+
+func TestHandlers_GetLinkInfo(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	_, err := appService.CreateLink(context.Background(), &owner, models.LinkCreateRequest{
+		OriginalURL: "https://ya.ru",
+		Title:       "Яндекс",
+	})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Get("/api/links/{id}", handler.GetLinkInfo())
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/6YGS4ZUF", nil))
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		userID     uuid.UUID
+		statusCode int
+		response   string
+	}{
+		{
+			name:       "Anonymous",
+			target:     "/api/links/6YGS4ZUF",
+			userID:     uuid.UUID{},
+			statusCode: http.StatusOK,
+			response:   `"original_url":"https://ya.ru","created_at":"`,
+		},
+		{
+			name:       "Owner",
+			target:     "/api/links/6YGS4ZUF",
+			userID:     owner,
+			statusCode: http.StatusOK,
+			response:   `"state":"active","clicks":2,"password_protected":false,"metadata":{"title":"Яндекс","notes":"","tags":[]}}`,
+		},
+		{
+			name:       "Not_found",
+			target:     "/api/links/unknown",
+			userID:     owner,
+			statusCode: http.StatusNotFound,
+			response:   `"code":"not_found"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, tt.userID))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			result := w.Result()
+			resBody, err := io.ReadAll(result.Body)
+			defer result.Body.Close()
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+			assert.Contains(t, string(resBody), tt.response)
+			if tt.userID.IsNil() {
+				assert.NotContains(t, string(resBody), "metadata")
+			}
+		})
+	}
+}
+
+func TestHandlers_MaxClicks(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Post("/api/v2/links", handler.CreateLink())
+	r.Get("/api/links/{id}", handler.GetLinkInfo())
+
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/links", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, owner))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := create(`{"original_url":"https://dzen.ru","max_clicks":-1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_max_clicks"`)
+
+	w = create(`{"original_url":"https://ya.ru","max_clicks":1}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"max_clicks":1`)
+	var created struct {
+		Data models.Link `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	link := created.Data
+
+	// одноразовая ссылка получает случайный код, тот же URL сокращается в другую ссылку
+	assert.NotEqual(t, "6YGS4ZUF", link.ID)
+	w = create(`{"original_url":"https://ya.ru","max_clicks":1}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), link.ID)
+	w = create(`{"original_url":"https://ya.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"6YGS4ZUF"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+link.ID, nil))
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://ya.ru", w.Header().Get("Location"))
+
+	for _, target := range []string{"/" + link.ID, "/" + link.ID + "+"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"link_expired"`)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/links/"+link.ID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"state":"expired","clicks":1,"max_clicks":1`)
+}
+
+func TestHandlers_UpdateSharedLinkPrivate(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+	other := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Post("/api/v2/links", handler.CreateLink())
+	r.Patch("/api/v2/links/{id}", handler.UpdateLink())
+
+	send := func(userID uuid.UUID, method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, userID))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(owner, http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"6YGS4ZUF"`)
+
+	// код по адресу выдается всем, кто сокращает тот же URL, поэтому ссылка не становится приватной
+	for _, body := range []string{`{"max_clicks":1}`, `{"password":"secret"}`} {
+		w = send(owner, http.MethodPatch, "/api/v2/links/6YGS4ZUF", body)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"shared_link_private"`)
+	}
+
+	w = send(other, http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru"}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"6YGS4ZUF"`)
+	link, err := storageURLs.GetLink(context.Background(), "6YGS4ZUF")
+	require.NoError(t, err)
+	assert.False(t, link.Private())
+
+	// ссылка со случайным кодом меняет пароль и лимит переходов
+	w = send(owner, http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru","max_clicks":5}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		Data models.Link `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	w = send(owner, http.MethodPatch, "/api/v2/links/"+created.Data.ID, `{"max_clicks":1,"password":"secret"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// случайный код не становится публичной ссылкой URL, у которого уже есть код по адресу
+	w = send(owner, http.MethodPatch, "/api/v2/links/"+created.Data.ID, `{"max_clicks":0,"password":""}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"private_link_public"`)
+	link, err = storageURLs.GetLink(context.Background(), created.Data.ID)
+	require.NoError(t, err)
+	assert.True(t, link.Private())
+}
+
+func TestHandlers_ActiveWindow(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	twoHoursAgo, hourAgo, hourLater := now.Add(-2*time.Hour), now.Add(-time.Hour), now.Add(time.Hour)
+
+	create := func(req models.LinkCreateRequest) string {
+		link, err := appService.CreateLink(context.Background(), &owner, req)
+		require.NoError(t, err)
+		return link.ID
+	}
+	scheduled := create(models.LinkCreateRequest{OriginalURL: "https://example.com/launch", ActiveFrom: &hourLater})
+	ended := create(models.LinkCreateRequest{OriginalURL: "https://example.com/ended", ActiveUntil: &hourAgo})
+	withFallback := create(models.LinkCreateRequest{OriginalURL: "https://example.com/sale", ActiveFrom: &twoHoursAgo, ActiveUntil: &hourAgo, FallbackURL: "https://example.com"})
+	active := create(models.LinkCreateRequest{OriginalURL: "https://example.com/now", ActiveFrom: &hourAgo, ActiveUntil: &hourLater})
+
+	_, err := appService.CreateLink(context.Background(), &owner, models.LinkCreateRequest{
+		OriginalURL: "https://example.com/invalid",
+		ActiveFrom:  &hourLater,
+		ActiveUntil: &hourAgo,
+	})
+	assert.ErrorIs(t, err, shortener_service.ErrInvalidActiveWindow)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Get("/api/links/{id}", handler.GetLinkInfo())
+	r.Patch("/api/v2/links/{id}", handler.UpdateLink())
+	r.Get("/api/internal/links/scheduled", handler.GetScheduledLinks())
+
+	decode := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+id, nil))
+		return w
+	}
+
+	w := decode(scheduled)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"link_not_active"`)
+
+	w = decode(ended)
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"link_expired"`)
+
+	w = decode(withFallback)
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://example.com", w.Header().Get("Location"))
+
+	w = decode(active)
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://example.com/now", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/links/"+scheduled, nil))
+	assert.Contains(t, w.Body.String(), `"state":"scheduled"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/internal/links/scheduled", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var links []models.ScheduledLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &links))
+	require.Len(t, links, 4)
+	// ссылки без начала окна идут первыми, затем по началу окна
+	assert.Equal(t, []string{ended, withFallback, active, scheduled}, []string{links[0].ID, links[1].ID, links[2].ID, links[3].ID})
+	assert.Equal(t, []string{"expired", "expired", "active", "scheduled"}, []string{links[0].State, links[1].State, links[2].State, links[3].State})
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v2/links/"+scheduled, strings.NewReader(`{"active_from":"not time"}`))
+	req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, owner))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_active_window"`)
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/v2/links/"+scheduled, strings.NewReader(`{"active_from":""}`))
+	req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, owner))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "active_from")
+
+	w = decode(scheduled)
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+}
+
+func TestHandlers_RedirectRules(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+		GeoHeader:     "X-Country-Code",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Post("/api/v2/links", handler.CreateLink())
+
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/links", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, owner))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := create(`{"original_url":"https://dzen.ru","rules":[{"device":"tv","url":"https://dzen.ru/tv"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_redirect_rule"`)
+
+	w = create(`{"original_url":"https://dzen.ru","rules":[{"url":"https://dzen.ru/all"}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = create(`{"original_url":"https://ya.ru","rules":[
+		{"device":"ios","url":"https://apps.apple.com/app/yandex"},
+		{"device":"android","url":"https://play.google.com/store/apps/details?id=ru.yandex"},
+		{"language":"en","country":"US","url":"https://ya.ru/en-us"},
+		{"language":"pt-BR","url":"https://ya.ru/pt-br"}
+	]}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"rules":[{"device":"ios","url":"https://apps.apple.com/app/yandex"}`)
+
+	tests := []struct {
+		name      string
+		userAgent string
+		language  string
+		country   string
+		location  string
+	}{
+		{
+			name:      "iOS",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148",
+			language:  "en-US",
+			country:   "US",
+			location:  "https://apps.apple.com/app/yandex",
+		},
+		{
+			name:      "Android",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Mobile Safari/537.36",
+			location:  "https://play.google.com/store/apps/details?id=ru.yandex",
+		},
+		{
+			name:      "Language_and_country",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
+			language:  "ru;q=0.5, en-GB;q=0.9",
+			country:   "us",
+			location:  "https://ya.ru/en-us",
+		},
+		{
+			name:      "Language_other_country",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
+			language:  "en-US",
+			country:   "GB",
+			location:  "https://ya.ru",
+		},
+		{
+			name:     "Language_with_region",
+			language: "pt-BR,pt;q=0.9",
+			location: "https://ya.ru/pt-br",
+		},
+		{
+			name:     "Language_other_region",
+			language: "pt-PT",
+			location: "https://ya.ru",
+		},
+		{
+			name:     "Default",
+			location: "https://ya.ru",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/6YGS4ZUF", nil)
+			req.Header.Set("User-Agent", tt.userAgent)
+			req.Header.Set("Accept-Language", tt.language)
+			req.Header.Set("X-Country-Code", tt.country)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+			assert.Equal(t, []string{"User-Agent", "Accept-Language", "X-Country-Code"}, w.Header().Values("Vary"))
+		})
+	}
+}
+
+func TestHandlers_Variants(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Post("/api/v2/links", handler.CreateLink())
+
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/links", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, owner))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, body := range []string{
+		`{"original_url":"https://example.com/zero","variants":[{"url":"https://example.com/a","weight":0}]}`,
+		`{"original_url":"https://example.com/dup","variants":[{"id":"a","url":"https://example.com/a","weight":1},{"id":"a","url":"https://example.com/b","weight":1}]}`,
+		`{"original_url":"https://example.com/url","variants":[{"url":"not url","weight":1}]}`,
+	} {
+		w := create(body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"invalid_variants"`)
+	}
+
+	t.Run("Weighted", func(t *testing.T) {
+		w := create(`{"original_url":"https://ya.ru","variants":[{"url":"https://ya.ru/a","weight":70},{"url":"https://ya.ru/b","weight":30}]}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"variants":[{"id":"v1","url":"https://ya.ru/a","weight":70,"clicks":0},{"id":"v2"`)
+
+		locations := make(map[string]int)
+		for i := 0; i < 200; i++ {
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/6YGS4ZUF", nil))
+			require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+			assert.Empty(t, w.Result().Cookies())
+			locations[w.Header().Get("Location")]++
+		}
+		assert.Len(t, locations, 2)
+		assert.Greater(t, locations["https://ya.ru/a"], locations["https://ya.ru/b"])
+
+		info, err := appService.GetLinkInfo(context.Background(), &owner, "6YGS4ZUF")
+		require.NoError(t, err)
+		assert.Equal(t, int64(200), info.Clicks)
+		require.Len(t, info.Variants, 2)
+		assert.Equal(t, int64(locations["https://ya.ru/a"]), info.Variants[0].Clicks)
+		assert.Equal(t, int64(locations["https://ya.ru/b"]), info.Variants[1].Clicks)
+
+		info, err = appService.GetLinkInfo(context.Background(), nil, "6YGS4ZUF")
+		require.NoError(t, err)
+		assert.Empty(t, info.Variants)
+	})
+
+	t.Run("Sticky", func(t *testing.T) {
+		w := create(`{"original_url":"https://dzen.ru","sticky_variants":true,"variants":[{"id":"a","url":"https://dzen.ru/a","weight":1},{"id":"b","url":"https://dzen.ru/b","weight":1}]}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x+5vpM8W", nil))
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		location := w.Header().Get("Location")
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "variant_x+5vpM8W", cookies[0].Name)
+
+		for i := 0; i < 20; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/x+5vpM8W", nil)
+			req.AddCookie(cookies[0])
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, location, w.Header().Get("Location"))
+		}
+
+		// неизвестный вариант из cookie заменяется новым
+		req := httptest.NewRequest(http.MethodGet, "/x+5vpM8W", nil)
+		req.AddCookie(&http.Cookie{Name: "variant_x+5vpM8W", Value: "unknown"})
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Len(t, w.Result().Cookies(), 1)
+		assert.Contains(t, []string{"a", "b"}, w.Result().Cookies()[0].Value)
+	})
+}
+
+func TestHandlers_QueryForwarding(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Post("/api/v2/links", handler.CreateLink())
+	r.Patch("/api/v2/links/{id}", handler.UpdateLink())
+
+	send := func(method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, owner))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru","forward_query":true,"utm":{"utm_source":"short","utm_campaign":"{id}"}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"forward_query":true,"utm":{"utm_source":"short","utm_campaign":"{id}"}`)
+
+	w = send(http.MethodGet, "/6YGS4ZUF?ref=tg&q=a+b&utm_source=mail", "")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://ya.ru?utm_campaign=6YGS4ZUF&ref=tg&q=a+b&utm_source=mail", w.Header().Get("Location"))
+
+	w = send(http.MethodPatch, "/api/v2/links/6YGS4ZUF", `{"forward_query":false,"utm":{}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"utm"`)
+
+	w = send(http.MethodGet, "/6YGS4ZUF?ref=tg", "")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://ya.ru", w.Header().Get("Location"))
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"html/template"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// linkAccessCookiePrefix prefix of cookie name with access token of password-protected link
+const linkAccessCookiePrefix = "link_"
+
+// passwordPage HTML template of password form for password-protected link
+//
+//go:embed templates/password.html
+var passwordPage string
+
+// passwordTemplate parsed template of password form
+var passwordTemplate = template.Must(template.New("password").Parse(passwordPage))
+
+// passwordForm structure for password form template
+type passwordForm struct {
+	ShortURL string
+	Action   string
+	Error    string
+}
+
+// Unlock handler for checking password of password-protected link, access cookie is set and user is redirected back
+// @Accept form password field
+// @Success 303 {string} redirect to short URL with access cookie
+// @Failure 403 {html} password form if password is wrong
+// @Failure 404 {problem} error if link not found
+// @Failure 410 {problem} error if link deleted
+// @Failure 429 {html} password form if too many wrong passwords are entered
+func (h *Handlers) Unlock() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := h.appService.HostLinkKey(r.Host, chi.URLParam(r, "id"))
+
+		res, err := h.appService.Resolve(r.Context(), id)
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+		if res.Link.ID == "" {
+			problem.WriteError(w, r, shortenerservice.ErrLinkNotFound)
+			return
+		}
+		if !res.Protected {
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		}
+
+		err = h.appService.CheckLinkPassword(r.Context(), res.Link.ID, clientIP(r), r.PostFormValue("password"))
+		switch {
+		case errors.Is(err, shortenerservice.ErrInvalidLinkPassword):
+			writePasswordForm(w, r, res.Link, err.Error(), http.StatusForbidden)
+			return
+		case errors.Is(err, shortenerservice.ErrTooManyPasswordAttempts):
+			w.Header().Set("Retry-After", strconv.Itoa(int(shortenerservice.PasswordAttemptsWindow.Seconds())))
+			writePasswordForm(w, r, res.Link, err.Error(), http.StatusTooManyRequests)
+			return
+		case err != nil:
+			logger.Log.Debug("Ошибка проверки пароля ссылки", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		token, err := h.appService.LinkAccessToken(res.Link.ID)
+		if err != nil {
+			logger.Log.Debug("Ошибка создания токена доступа к ссылке", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     linkAccessCookiePrefix + shortenerservice.LinkCode(res.Link.ID),
+			Value:    token,
+			Path:     "/",
+			MaxAge:   int(shortenerservice.LinkAccessTTL.Seconds()),
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// linkAccessGranted function checks cookie with access token of password-protected link
+func (h *Handlers) linkAccessGranted(r *http.Request, id string) bool {
+	cookie, err := r.Cookie(linkAccessCookiePrefix + shortenerservice.LinkCode(id))
+	if err != nil {
+		return false
+	}
+	return h.appService.VerifyLinkAccess(id, cookie.Value)
+}
+
+// writePasswordForm function renders password form of password-protected link, destination URL isn't shown
+func writePasswordForm(w http.ResponseWriter, r *http.Request, link models.LinkPreview, message string, statusCode int) {
+	var buf bytes.Buffer
+	form := passwordForm{ShortURL: link.ShortURL, Action: r.URL.Path, Error: message}
+	if err := passwordTemplate.Execute(&buf, form); err != nil {
+		logger.Log.Debug("Ошибка отображения формы пароля", zap.Error(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	_, _ = buf.WriteTo(w)
+}
+
+// clientIP function returns IP of client for limiting password attempts
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
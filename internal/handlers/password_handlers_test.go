@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHandlers_Unlock(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+		SecretKey:     "verycomplexsecretkey",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	link, err := appService.CreateLink(context.Background(), &owner, models.LinkCreateRequest{
+		OriginalURL: "https://ya.ru",
+		Password:    "secret",
+	})
+	require.NoError(t, err)
+	assert.True(t, link.PasswordProtected)
+	// код ссылки с паролем случайный и не совпадает с кодом исходного URL
+	assert.NotEqual(t, "6YGS4ZUF", link.ID)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Post("/{id}", handler.Unlock())
+	r.Get("/api/links/{id}", handler.GetLinkInfo())
+
+	unlock := func(remoteAddr string, password string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/"+link.ID, strings.NewReader(url.Values{"password": {password}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = remoteAddr
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Password_form", func(t *testing.T) {
+		for _, target := range []string{"/" + link.ID, "/" + link.ID + "+"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), `type="password"`)
+			assert.NotContains(t, w.Body.String(), "https://ya.ru")
+		}
+	})
+
+	t.Run("Link_info_hides_original_URL", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/links/"+link.ID, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"password_protected":true`)
+		assert.NotContains(t, w.Body.String(), "https://ya.ru")
+	})
+
+	t.Run("Invalid_password", func(t *testing.T) {
+		w := unlock("192.0.2.1:1234", "wrong")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "неверный пароль")
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("Valid_password", func(t *testing.T) {
+		w := unlock("192.0.2.1:1234", "secret")
+
+		require.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/"+link.ID, w.Header().Get("Location"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "link_"+link.ID, cookies[0].Name)
+		assert.True(t, cookies[0].HttpOnly)
+
+		req := httptest.NewRequest(http.MethodGet, "/"+link.ID, nil)
+		req.AddCookie(cookies[0])
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "https://ya.ru", w.Header().Get("Location"))
+	})
+
+	t.Run("Forged_cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/"+link.ID, nil)
+		req.AddCookie(&http.Cookie{Name: "link_" + link.ID, Value: "forged"})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `type="password"`)
+	})
+
+	t.Run("Too_many_attempts", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			require.Equal(t, http.StatusForbidden, unlock("192.0.2.2:1234", "wrong").Code)
+		}
+
+		w := unlock("192.0.2.2:1234", "secret")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "900", w.Header().Get("Retry-After"))
+
+		// попытки считаются отдельно для каждого клиента
+		assert.Equal(t, http.StatusSeeOther, unlock("192.0.2.3:1234", "secret").Code)
+	})
+}
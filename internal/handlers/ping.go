@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/problem"
 	"go.uber.org/zap"
 	"net/http"
 	"time"
@@ -16,7 +17,7 @@ func (h *Handlers) PingDB() http.HandlerFunc {
 
 		if err := h.appService.PingDB(ctx); err != nil {
 			logger.Log.Debug("error database connect ping", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.WriteError(w, r, err)
 			return
 		}
 
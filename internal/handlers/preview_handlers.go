@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"bytes"
+	_ "embed"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"go.uber.org/zap"
+	"html/template"
+	"net/http"
+)
+
+// previewPage HTML template of preview page shown instead of redirect
+//
+//go:embed templates/preview.html
+var previewPage string
+
+// previewTemplate parsed template of preview page
+var previewTemplate = template.Must(template.New("preview").Parse(previewPage))
+
+// writePreview function renders preview page with destination URL and continue button
+func writePreview(w http.ResponseWriter, r *http.Request, preview models.LinkPreview) {
+	var buf bytes.Buffer
+	if err := previewTemplate.Execute(&buf, preview); err != nil {
+		logger.Log.Debug("Ошибка отображения страницы предпросмотра", zap.Error(err))
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecode_Preview(t *testing.T) {
+	type want struct {
+		statusCode int
+		location   string
+		response   string
+	}
+
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	links := []models.LinkCreateRequest{
+		{OriginalURL: "https://ya.ru", Title: "Яндекс <поиск>"},
+		// короткий URL HM8inS0+ сам заканчивается на "+"
+		{OriginalURL: "https://example.com/58"},
+		{OriginalURL: "https://dzen.ru", Interstitial: true},
+	}
+	for _, link := range links {
+		_, err := appService.CreateLink(context.Background(), &owner, link)
+		require.NoError(t, err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+
+	tests := []struct {
+		name   string
+		target string
+		want   want
+	}{
+		{
+			name:   "Redirect",
+			target: "/6YGS4ZUF",
+			want: want{
+				statusCode: http.StatusTemporaryRedirect,
+				location:   "https://ya.ru",
+			},
+		},
+		{
+			name:   "Preview",
+			target: "/6YGS4ZUF+",
+			want: want{
+				statusCode: http.StatusOK,
+				response:   `<strong>Яндекс &lt;поиск&gt;</strong></p>`,
+			},
+		},
+		{
+			name:   "Short_URL_with_plus",
+			target: "/HM8inS0+",
+			want: want{
+				statusCode: http.StatusTemporaryRedirect,
+				location:   "https://example.com/58",
+			},
+		},
+		{
+			name:   "Preview_short_URL_with_plus",
+			target: "/HM8inS0++",
+			want: want{
+				statusCode: http.StatusOK,
+				response:   `href="https://example.com/58"`,
+			},
+		},
+		{
+			name:   "Interstitial",
+			target: "/x+5vpM8W",
+			want: want{
+				statusCode: http.StatusOK,
+				response:   `href="https://dzen.ru"`,
+			},
+		},
+		{
+			name:   "Preview_not_found",
+			target: "/unknown+",
+			want: want{
+				statusCode: http.StatusNotFound,
+				response:   `"code":"not_found"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			result := w.Result()
+			resBody, err := io.ReadAll(result.Body)
+			defer result.Body.Close()
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+			assert.Equal(t, tt.want.location, result.Header.Get("Location"))
+			assert.Contains(t, string(resBody), tt.want.response)
+		})
+	}
+
+	t.Run("Always_interstitial", func(t *testing.T) {
+		cfg.AlwaysInterstitial = true
+		defer func() { cfg.AlwaysInterstitial = false }()
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/6YGS4ZUF", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+// QRCode handler for QR code image with full short URL of existing not deleted link
+// @Accept string query params size in pixels, format png or svg, level L, M, Q or H, margin in modules
+// @Success 200 {png} QR code image, SVG image for svg format
+// @Success 304 if QR code isn't changed since If-None-Match ETag
+// @Failure 400 {problem} bad request if QR code options are invalid
+// @Failure 404 {problem} error if URL not found
+// @Failure 410 {problem} error if URL already deleted
+// @Failure 500 {problem} internal error if QR code can't be generated
+func (h *Handlers) QRCode() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		opts := models.QROptions{
+			Format: query.Get("format"),
+			Level:  query.Get("level"),
+		}
+
+		var err error
+		if size := query.Get("size"); size != "" {
+			if opts.Size, err = strconv.Atoi(size); err != nil {
+				problem.WriteError(w, r, shortenerservice.ErrInvalidQROptions)
+				return
+			}
+		}
+		if margin := query.Get("margin"); margin != "" {
+			value, err := strconv.Atoi(margin)
+			if err != nil {
+				problem.WriteError(w, r, shortenerservice.ErrInvalidQROptions)
+				return
+			}
+			opts.Margin = &value
+		}
+
+		code, err := h.appService.QRCode(r.Context(), h.appService.HostLinkKey(r.Host, chi.URLParam(r, "id")), opts)
+		if err != nil {
+			logger.Log.Debug("Ошибка создания QR-кода", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.Header().Set("ETag", code.ETag)
+		// QR-код удаленной или измененной ссылки не должен отдаваться из кэша, клиент перепроверяет его по ETag
+		w.Header().Set("Cache-Control", "private, no-cache")
+		if r.Header.Get("If-None-Match") == code.ETag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", code.ContentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(code.Content)
+	}
+
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlers_QRCode(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	_, err := appService.Shorten(context.Background(), "https://ya.ru", &owner)
+	require.NoError(t, err)
+	_, err = appService.Shorten(context.Background(), "https://dzen.ru", &owner)
+	require.NoError(t, err)
+	require.NoError(t, storageURLs.DeleteUrlsBatch(context.Background(), &owner, []string{"x+5vpM8W"}))
+
+	r := chi.NewRouter()
+	r.Get("/{id}/qr", handler.QRCode())
+
+	get := func(target string, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("PNG", func(t *testing.T) {
+		w := get("/6YGS4ZUF/qr?size=128&level=H&margin=2", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+
+		img, err := png.Decode(w.Body)
+		require.NoError(t, err)
+		assert.Equal(t, 128, img.Bounds().Dx())
+
+		etag := w.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+		w = get("/6YGS4ZUF/qr?size=128&level=H&margin=2", etag)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		w := get("/6YGS4ZUF/qr?format=svg", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `<svg xmlns="http://www.w3.org/2000/svg" width="256" height="256"`)
+	})
+
+	for _, tt := range []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "Not_Found", target: "/unknown/qr", wantStatus: http.StatusNotFound, wantCode: "not_found"},
+		{name: "Deleted", target: "/x+5vpM8W/qr", wantStatus: http.StatusGone, wantCode: "url_deleted"},
+		{name: "Invalid_Size", target: "/6YGS4ZUF/qr?size=big", wantStatus: http.StatusBadRequest, wantCode: "invalid_qr_options"},
+		{name: "Invalid_Margin", target: "/6YGS4ZUF/qr?margin=-1", wantStatus: http.StatusBadRequest, wantCode: "invalid_qr_options"},
+		{name: "Invalid_Format", target: "/6YGS4ZUF/qr?format=gif", wantStatus: http.StatusBadRequest, wantCode: "invalid_qr_options"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.target, "")
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":"`+tt.wantCode+`"`)
+		})
+	}
+}
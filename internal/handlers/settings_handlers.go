@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// GetUserSettings handler for getting user's default domain of new links and list of configured domains
+// @Success 200 {json} user's settings
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 500 {problem} internal error if settings can't be read
+func (h *Handlers) GetUserSettings() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		userID := auth.UIDFromContext(r.Context())
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		settings, err := h.appService.GetUserSettings(r.Context(), userID)
+		if err != nil {
+			logger.Log.Debug("Ошибка получения настроек пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(settings)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// UpdateUserSettings handler for changing user's default domain of new links, empty domain resets it to default one
+// @Accept json user's settings
+// @Success 200 {json} saved user's settings
+// @Failure 400 {problem} bad request if JSON is invalid or domain isn't configured
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 500 {problem} internal error if settings can't be saved
+func (h *Handlers) UpdateUserSettings() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		userID := auth.UIDFromContext(r.Context())
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.UserSettings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		settings, err := h.appService.SaveUserSettings(r.Context(), userID, req)
+		if err != nil {
+			logger.Log.Debug("Ошибка сохранения настроек пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(settings)
+	}
+
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlers_Domains(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+		Domains:       "https://go.brand.ru",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Get("/{id}", handler.Decode())
+	r.Get("/api/user/settings", handler.GetUserSettings())
+	r.Put("/api/user/settings", handler.UpdateUserSettings())
+
+	send := func(method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, owner))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodGet, "/api/user/settings", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"domain":"localhost:8080","domains":["localhost:8080","go.brand.ru"]}`, w.Body.String())
+
+	w = send(http.MethodPut, "/api/user/settings", `{"domain":"unknown.ru"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"unknown_domain"`)
+
+	w = send(http.MethodPut, "/api/user/settings", `{"domain":"go.brand.ru"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"domain":"go.brand.ru","domains":["localhost:8080","go.brand.ru"]}`, w.Body.String())
+
+	shortURL, err := appService.Shorten(context.Background(), "https://ya.ru", &owner)
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.brand.ru/6YGS4ZUF", shortURL)
+
+	w = send(http.MethodGet, "https://go.brand.ru/6YGS4ZUF", "")
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://ya.ru", w.Header().Get("Location"))
+
+	// код ссылки другого домена не открывается на основном домене
+	w = send(http.MethodGet, "http://localhost:8080/6YGS4ZUF", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
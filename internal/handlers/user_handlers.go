@@ -5,16 +5,23 @@ import (
 	"encoding/json"
 	"github.com/romanp1989/go-shortener/internal/auth"
 	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
 	"go.uber.org/zap"
 	"net/http"
 	"time"
 )
 
-// GetURLs handler for creating a shortened URL based on the original one
+// GetURLs handler for creating a shortened URL based on the original one.
+// With status=broken query param only URLs with broken destination are returned with last check result,
+// with workspace query param URLs of user's workspace are returned
 // @Accept string user uuid
-// @Success 200 {json} list of user's URLs
-// @Failure 204 no content if users haven't URLs
-// @Failure 401 error if user unauthorized
+// @Success 200 {json} list of user's URLs, empty list if user hasn't URLs
+// @Failure 204 no content if filtered list is empty
+// @Failure 400 {problem} bad request if status filter is unknown
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+// @Failure 500 {problem} internal error if URLs can't be read
 func (h *Handlers) GetURLs() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 
@@ -24,28 +31,95 @@ func (h *Handlers) GetURLs() http.HandlerFunc {
 
 		userID := auth.UIDFromContext(ctx)
 		if userID == nil {
-			w.WriteHeader(http.StatusUnauthorized)
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		if status := r.URL.Query().Get("status"); status != "" {
+			urls, err := h.appService.GetURLsByStatus(ctx, userID, status)
+			if err != nil {
+				problem.WriteError(w, r, err)
+				return
+			}
+			if len(urls) == 0 {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			writeJSON(w, r, http.StatusOK, urls)
+			return
+		}
+
+		if workspaceID := r.URL.Query().Get("workspace"); workspaceID != "" {
+			urls, err := h.appService.GetWorkspaceURLs(ctx, userID, workspaceID)
+			if err != nil {
+				problem.WriteError(w, r, err)
+				return
+			}
+			if len(urls) == 0 {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			writeJSON(w, r, http.StatusOK, urls)
 			return
 		}
 
 		allUrls, err := h.appService.GetURLs(ctx, userID)
 		if err != nil {
 			logger.Log.Debug("Ошибка при получении urls пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, allUrls)
+
+		logger.Log.Debug("sending HTTP 200 response")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// SearchURLs handler for full-text search in user's URLs by original URL, title, notes and tags
+// @Accept string query param q
+// @Success 200 {json} list of found user's URLs ordered by rank
+// @Failure 204 no content if nothing found
+// @Failure 400 {problem} bad request if query is empty
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 500 {problem} internal error if search failed
+func (h *Handlers) SearchURLs() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		results, err := h.appService.SearchURLs(ctx, userID, r.URL.Query().Get("q"))
+		if err != nil {
+			logger.Log.Debug("Ошибка при поиске urls пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		if len(results) == 0 {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
 
-		b, err := json.Marshal(allUrls)
+		b, err := json.Marshal(results)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			problem.WriteError(w, r, err)
 			return
 		}
 
 		w.Header().Set("content-type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write(b)
-
-		logger.Log.Debug("sending HTTP 200 response")
 	}
 
 	return http.HandlerFunc(fn)
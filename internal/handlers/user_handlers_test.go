@@ -19,7 +19,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 )
 
 func TestHandlers_GetURLs(t *testing.T) {
@@ -36,16 +38,10 @@ func TestHandlers_GetURLs(t *testing.T) {
 			ShortURL:    "6YGS4ZUF",
 		},
 	}
-	firstURLResponse := []models.StorageURL{
-		{
-			UserID:      nil,
-			OriginalURL: firstURLs[0].OriginalURL,
-			ShortURL:    fmt.Sprintf("%s/%s", cfg.BaseURL, firstURLs[0].ShortURL),
-		},
-	}
-	firstResponse, _ := json.Marshal(firstURLResponse)
+	firstResponse := `[{"original_url":"https://ya.ru","short_url":"http://localhost:8080/6YGS4ZUF"}]`
 
 	secondUserID := jwtService.EnsureRandom()
+	thirdUserID := jwtService.EnsureRandom()
 
 	type want struct {
 		statusCode  int
@@ -63,7 +59,7 @@ func TestHandlers_GetURLs(t *testing.T) {
 			userID: firstUserID,
 			want: want{
 				statusCode:  http.StatusOK,
-				responseURL: string(firstResponse),
+				responseURL: firstResponse,
 			},
 		},
 		{
@@ -78,7 +74,15 @@ func TestHandlers_GetURLs(t *testing.T) {
 			name:   "Empty_URLs",
 			userID: secondUserID,
 			want: want{
-				statusCode:  http.StatusNoContent,
+				statusCode:  http.StatusOK,
+				responseURL: "[]",
+			},
+		},
+		{
+			name:   "Storage_error",
+			userID: thirdUserID,
+			want: want{
+				statusCode:  http.StatusInternalServerError,
 				responseURL: "",
 			},
 		},
@@ -94,7 +98,8 @@ func TestHandlers_GetURLs(t *testing.T) {
 	handler := New(appService)
 
 	mockStorageDB.EXPECT().GetAllUrlsByUser(gomock.Any(), &firstUserID).Return(firstURLs, nil).Times(1)
-	mockStorageDB.EXPECT().GetAllUrlsByUser(gomock.Any(), &secondUserID).Return(make([]models.StorageURL, 0), errors.New("Ошибка при получении urls пользователя")).Times(1)
+	mockStorageDB.EXPECT().GetAllUrlsByUser(gomock.Any(), &secondUserID).Return(make([]models.StorageURL, 0), nil).Times(1)
+	mockStorageDB.EXPECT().GetAllUrlsByUser(gomock.Any(), &thirdUserID).Return(nil, errors.New("Ошибка при получении urls пользователя")).Times(1)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -124,3 +129,178 @@ func TestHandlers_GetURLs(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlers_SearchURLs(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: "http://localhost:8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	firstUserID := jwtService.EnsureRandom()
+	secondUserID := jwtService.EnsureRandom()
+
+	foundURLs := []models.SearchResult{
+		{
+			ShortURL:    "6YGS4ZUF",
+			OriginalURL: "https://ya.ru",
+			Title:       "Яндекс",
+			Tags:        []string{"search"},
+			Rank:        1,
+		},
+	}
+	foundResponse, _ := json.Marshal([]models.SearchResult{
+		{
+			ShortURL:    fmt.Sprintf("%s/%s", cfg.BaseURL, foundURLs[0].ShortURL),
+			OriginalURL: foundURLs[0].OriginalURL,
+			Title:       foundURLs[0].Title,
+			Tags:        foundURLs[0].Tags,
+			Rank:        foundURLs[0].Rank,
+		},
+	})
+
+	type want struct {
+		statusCode int
+		response   string
+	}
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		query  string
+		want   want
+	}{
+		{
+			name:   "Success_search",
+			userID: firstUserID,
+			query:  "search",
+			want: want{
+				statusCode: http.StatusOK,
+				response:   string(foundResponse),
+			},
+		},
+		{
+			name:   "Nothing_found",
+			userID: secondUserID,
+			query:  "search",
+			want: want{
+				statusCode: http.StatusNoContent,
+			},
+		},
+		{
+			name:   "Empty_query",
+			userID: firstUserID,
+			query:  " ",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "User_Unauthorized",
+			userID: uuid.UUID{},
+			query:  "search",
+			want: want{
+				statusCode: http.StatusUnauthorized,
+			},
+		},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockStorageDB := mocks.NewMockStorage(mockCtrl)
+	defer mockCtrl.Finish()
+
+	storageURLs := storage.Storage{Storage: mockStorageDB}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+
+	handler := New(appService)
+
+	mockStorageDB.EXPECT().Search(gomock.Any(), &firstUserID, "search", gomock.Any()).Return(foundURLs, nil).Times(1)
+	mockStorageDB.EXPECT().Search(gomock.Any(), &secondUserID, "search", gomock.Any()).Return([]models.SearchResult{}, nil).Times(1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/user/urls/search?q="+url.QueryEscape(tt.query), nil)
+			r = r.WithContext(context.WithValue(r.Context(), auth.AuthKey, tt.userID))
+			w := httptest.NewRecorder()
+
+			fn := handler.SearchURLs()
+			fn(w, r)
+
+			result := w.Result()
+			resBody, err := io.ReadAll(result.Body)
+			defer result.Body.Close()
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+
+			if tt.want.response != "" {
+				assert.JSONEq(t, tt.want.response, string(resBody))
+			}
+		})
+	}
+}
+
+func TestHandlers_GetURLs_Broken(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: "http://localhost:8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+	stranger := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	checkedAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	for destination, status := range map[string]int{"https://ya.ru": http.StatusNotFound, "https://dzen.ru": http.StatusOK} {
+		_, err := appService.Shorten(context.Background(), destination, &owner)
+		require.NoError(t, err)
+		require.NoError(t, storageURLs.SaveLinkCheck(context.Background(), appService.ShortURL(destination), status, checkedAt))
+	}
+
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		status     string
+		wantStatus int
+		response   string
+	}{
+		{
+			name:       "Broken",
+			userID:     owner,
+			status:     "broken",
+			wantStatus: http.StatusOK,
+			response:   `"original_url":"https://ya.ru","short_url":"http://localhost:8080/6YGS4ZUF",`,
+		},
+		{
+			name:       "No_Broken",
+			userID:     stranger,
+			status:     "broken",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Unknown_Status",
+			userID:     owner,
+			status:     "alive",
+			wantStatus: http.StatusBadRequest,
+			response:   `"code":"invalid_status_filter"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/user/urls?status="+tt.status, nil)
+			r = r.WithContext(context.WithValue(r.Context(), auth.AuthKey, tt.userID))
+			w := httptest.NewRecorder()
+			handler.GetURLs()(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.response)
+			if tt.wantStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"last_status":404,"last_checked_at":"2024-10-01T12:00:00Z"}]`)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// ListWorkspaces handler for getting user's workspaces with user's role
+// @Success 200 {json} list of user's workspaces
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 500 {problem} internal error if workspaces can't be read
+func (h *Handlers) ListWorkspaces() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		workspaces, err := h.appService.ListWorkspaces(ctx, userID)
+		if err != nil {
+			logger.Log.Debug("Ошибка получения рабочих пространств пользователя", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, workspaces)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// CreateWorkspace handler for creating workspace, user becomes its owner
+// @Accept json workspace name
+// @Success 201 {json} created workspace
+// @Failure 400 {problem} bad request if JSON is invalid or name is empty
+// @Failure 401 {problem} error if user unauthorized
+func (h *Handlers) CreateWorkspace() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.WorkspaceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		workspace, err := h.appService.CreateWorkspace(ctx, userID, req)
+		if err != nil {
+			logger.Log.Debug("Ошибка создания рабочего пространства", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Location", r.URL.Path+"/"+workspace.ID)
+		writeJSON(w, r, http.StatusCreated, workspace)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// GetWorkspace handler for getting workspace by id with user's role
+// @Accept string workspace id
+// @Success 200 {json} workspace
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+func (h *Handlers) GetWorkspace() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		workspace, err := h.appService.GetWorkspace(ctx, userID, chi.URLParam(r, "id"))
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, workspace)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// UpdateWorkspace handler for renaming workspace by its owner
+// @Accept json workspace name
+// @Success 200 {json} updated workspace
+// @Failure 400 {problem} bad request if JSON is invalid or name is empty
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 403 {problem} error if user isn't owner of workspace
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+func (h *Handlers) UpdateWorkspace() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		var req models.WorkspaceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		workspace, err := h.appService.UpdateWorkspace(ctx, userID, chi.URLParam(r, "id"), req)
+		if err != nil {
+			logger.Log.Debug("Ошибка изменения рабочего пространства", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, workspace)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// DeleteWorkspace handler for deleting workspace by its owner, links of workspace are returned to their creators
+// @Accept string workspace id
+// @Success 204 workspace is deleted
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 403 {problem} error if user isn't owner of workspace
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+func (h *Handlers) DeleteWorkspace() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		if err := h.appService.DeleteWorkspace(ctx, userID, chi.URLParam(r, "id")); err != nil {
+			logger.Log.Debug("Ошибка удаления рабочего пространства", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// GetWorkspaceMembers handler for getting members of workspace with their roles
+// @Accept string workspace id
+// @Success 200 {json} list of workspace's members
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+func (h *Handlers) GetWorkspaceMembers() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		members, err := h.appService.WorkspaceMembers(ctx, userID, chi.URLParam(r, "id"))
+		if err != nil {
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, members)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// SetWorkspaceMember handler for adding member to workspace or changing member's role by workspace owner
+// @Accept json member's role
+// @Success 200 {json} workspace member
+// @Failure 400 {problem} bad request if JSON, user id or role is invalid
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 403 {problem} error if user isn't owner of workspace
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+// @Failure 409 {problem} error if the only owner loses owner role
+func (h *Handlers) SetWorkspaceMember() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		memberID, err := uuid.FromString(chi.URLParam(r, "user_id"))
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBadRequest, "некорректный идентификатор пользователя"))
+			return
+		}
+
+		var req models.WorkspaceMemberRequest
+		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidJSON, "некорректный JSON"))
+			return
+		}
+
+		member, err := h.appService.SetWorkspaceMember(ctx, userID, chi.URLParam(r, "id"), &memberID, req.Role)
+		if err != nil {
+			logger.Log.Debug("Ошибка изменения участника рабочего пространства", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, member)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// DeleteWorkspaceMember handler for removing member from workspace by its owner, any member can leave workspace
+// @Accept string workspace id and user id
+// @Success 204 member is removed
+// @Failure 400 {problem} bad request if user id is invalid
+// @Failure 401 {problem} error if user unauthorized
+// @Failure 403 {problem} error if user isn't owner of workspace
+// @Failure 404 {problem} error if workspace not found or user isn't its member
+// @Failure 409 {problem} error if the only owner leaves workspace
+func (h *Handlers) DeleteWorkspaceMember() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		userID := auth.UIDFromContext(ctx)
+		if userID == nil {
+			problem.WriteError(w, r, shortenerservice.ErrUnauthorized)
+			return
+		}
+
+		memberID, err := uuid.FromString(chi.URLParam(r, "user_id"))
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBadRequest, "некорректный идентификатор пользователя"))
+			return
+		}
+
+		if err = h.appService.RemoveWorkspaceMember(ctx, userID, chi.URLParam(r, "id"), &memberID); err != nil {
+			logger.Log.Debug("Ошибка удаления участника рабочего пространства", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(fn)
+}
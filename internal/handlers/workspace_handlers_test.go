@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlers_Workspaces(t *testing.T) {
+	cfg := &config.ConfigENV{
+		ServerAddress: ":8080",
+		BaseURL:       "http://localhost:8080",
+	}
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+	editor := jwtService.EnsureRandom()
+	viewer := jwtService.EnsureRandom()
+	stranger := jwtService.EnsureRandom()
+
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	appService := shortener_service.NewShortenerService(&storageURLs, cfg)
+	handler := New(appService)
+
+	r := chi.NewRouter()
+	r.Get("/api/user/urls", handler.GetURLs())
+	r.Delete("/api/user/urls", handler.DeleteURLs())
+	r.Post("/api/v2/links", handler.CreateLink())
+	r.Get("/api/v2/links", handler.ListLinks())
+	r.Get("/api/v2/links/{id}", handler.GetLink())
+	r.Patch("/api/v2/links/{id}", handler.UpdateLink())
+	r.Post("/api/user/collections", handler.CreateCollection())
+	r.Post("/api/user/collections/{id}/urls", handler.AddCollectionURLs())
+	r.Get("/api/user/workspaces", handler.ListWorkspaces())
+	r.Post("/api/user/workspaces", handler.CreateWorkspace())
+	r.Get("/api/user/workspaces/{id}", handler.GetWorkspace())
+	r.Patch("/api/user/workspaces/{id}", handler.UpdateWorkspace())
+	r.Delete("/api/user/workspaces/{id}", handler.DeleteWorkspace())
+	r.Get("/api/user/workspaces/{id}/members", handler.GetWorkspaceMembers())
+	r.Put("/api/user/workspaces/{id}/members/{user_id}", handler.SetWorkspaceMember())
+	r.Delete("/api/user/workspaces/{id}/members/{user_id}", handler.DeleteWorkspaceMember())
+
+	send := func(userID uuid.UUID, method string, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.AuthKey, userID))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(owner, http.MethodPost, "/api/user/workspaces", `{"name":"  "}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_workspace"`)
+
+	w = send(owner, http.MethodPost, "/api/user/workspaces", `{"name":"Маркетинг"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var workspace models.Workspace
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &workspace))
+	require.NotEmpty(t, workspace.ID)
+	assert.Equal(t, models.WorkspaceRoleOwner, workspace.Role)
+	target := "/api/user/workspaces/" + workspace.ID
+
+	w = send(owner, http.MethodPut, target+"/members/"+editor.String(), `{"role":"admin"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_workspace_role"`)
+
+	w = send(owner, http.MethodPut, target+"/members/"+editor.String(), `{"role":"editor"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id":"`+editor.String()+`","role":"editor"}`, w.Body.String())
+	w = send(owner, http.MethodPut, target+"/members/"+viewer.String(), `{"role":"viewer"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = send(viewer, http.MethodPut, target+"/members/"+stranger.String(), `{"role":"viewer"}`)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"workspace_forbidden"`)
+
+	w = send(viewer, http.MethodGet, target+"/members", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var members []models.WorkspaceMember
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &members))
+	assert.Len(t, members, 3)
+
+	// единственный владелец не может лишиться роли
+	w = send(owner, http.MethodPut, target+"/members/"+owner.String(), `{"role":"editor"}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"last_workspace_owner"`)
+
+	// ссылки пространства создают редакторы
+	w = send(viewer, http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru","workspace_id":"`+workspace.ID+`"}`)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	w = send(editor, http.MethodPost, "/api/v2/links", `{"original_url":"https://ya.ru","workspace_id":"`+workspace.ID+`"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"workspace_id":"`+workspace.ID+`"`)
+	w = send(owner, http.MethodPost, "/api/v2/links", `{"original_url":"https://dzen.ru"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = send(stranger, http.MethodGet, "/api/v2/links/6YGS4ZUF", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = send(viewer, http.MethodGet, "/api/v2/links/6YGS4ZUF", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = send(viewer, http.MethodPatch, "/api/v2/links/6YGS4ZUF", `{"title":"Яндекс"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = send(owner, http.MethodPatch, "/api/v2/links/6YGS4ZUF", `{"title":"Яндекс"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Яндекс"`)
+
+	// переносить ссылку между пространствами может только её создатель
+	w = send(owner, http.MethodPatch, "/api/v2/links/6YGS4ZUF", `{"workspace_id":""}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = send(owner, http.MethodPatch, "/api/v2/links/x+5vpM8W", `{"workspace_id":"`+workspace.ID+`"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// в личные коллекции добавляются только свои ссылки, даже если ссылку пространства можно изменять
+	w = send(owner, http.MethodPost, "/api/user/collections", `{"name":"Избранное"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var collection models.Collection
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &collection))
+	w = send(owner, http.MethodPatch, "/api/v2/links/6YGS4ZUF", `{"collection_id":"`+collection.ID+`"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = send(owner, http.MethodPost, "/api/user/collections/"+collection.ID+"/urls", `["x+5vpM8W","6YGS4ZUF"]`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = send(owner, http.MethodPost, "/api/user/collections/"+collection.ID+"/urls", `["x+5vpM8W"]`)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = send(viewer, http.MethodGet, "/api/v2/links?workspace="+workspace.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":2`)
+
+	w = send(viewer, http.MethodGet, "/api/user/urls?workspace="+workspace.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "https://ya.ru")
+	assert.Contains(t, w.Body.String(), "https://dzen.ru")
+	w = send(stranger, http.MethodGet, "/api/user/urls?workspace="+workspace.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// участник с ролью viewer не удаляет ссылки пространства, владелец удаляет ссылки, созданные другими
+	w = send(viewer, http.MethodDelete, "/api/user/urls", `["6YGS4ZUF"]`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	w = send(owner, http.MethodDelete, "/api/user/urls", `["6YGS4ZUF"]`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Eventually(t, func() bool {
+		link, err := storageURLs.GetLink(context.Background(), "6YGS4ZUF")
+		return err == nil && link != nil && link.DeletedFlag
+	}, time.Second, 10*time.Millisecond)
+
+	w = send(viewer, http.MethodDelete, target+"/members/"+viewer.String(), "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = send(viewer, http.MethodGet, target, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = send(editor, http.MethodDelete, target, "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = send(owner, http.MethodPatch, target, `{"name":"Команда"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Команда"`)
+
+	w = send(editor, http.MethodGet, "/api/user/workspaces", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"role":"editor"`)
+
+	// ссылки удаленного пространства возвращаются создателям
+	w = send(owner, http.MethodDelete, target, "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = send(editor, http.MethodGet, "/api/user/workspaces", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	w = send(owner, http.MethodGet, "/api/v2/links/x+5vpM8W", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "workspace_id")
+}
@@ -56,3 +56,8 @@ func (r *LoggingResponseWriter) WriteHeader(statusCode int) {
 	r.ResponseWriter.WriteHeader(statusCode)
 	r.ResponseData.Status = statusCode // захватываем код статуса
 }
+
+// Unwrap function returns original http.ResponseWriter, used by http.ResponseController for flush
+func (r *LoggingResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
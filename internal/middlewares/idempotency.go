@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"bytes"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/problem"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+)
+
+// IdempotencyKeyHeader request header with client idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader response header is set if saved response is replayed
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// idempotencyRecorder response writer which keeps copy of response for idempotency key
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	contentType string
+	body        bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(statusCode int) {
+	if r.status == 0 {
+		r.status = statusCode
+		r.contentType = r.Header().Get("Content-Type")
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap function returns original response writer for http.ResponseController
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Idempotency Middleware for replay of saved response on retries with the same Idempotency-Key header.
+// Must be used after authorization middleware, server errors aren't saved so they can be retried
+func (m Middleware) Idempotency(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		key := r.Header.Get(IdempotencyKeyHeader)
+		userID := auth.UIDFromContext(ctx)
+		if key == "" || userID == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeBadRequest, "Ошибка при чтении body запроса"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestHash := shortenerservice.IdempotencyHash([]byte(r.Method), []byte(r.URL.Path), body)
+		record, err := m.AppService.LookupIdempotent(ctx, userID, key, requestHash)
+		if err != nil {
+			logger.Log.Debug("Ошибка проверки ключа идемпотентности", zap.Error(err))
+			problem.WriteError(w, r, err)
+			return
+		}
+
+		if record != nil {
+			if record.ContentType != "" {
+				w.Header().Set("Content-Type", record.ContentType)
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(record.StatusCode)
+			_, _ = w.Write(record.Body)
+			return
+		}
+		defer m.AppService.ReleaseIdempotent(userID, key)
+
+		rec := &idempotencyRecorder{ResponseWriter: w}
+		h.ServeHTTP(rec, r)
+
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+			return
+		}
+
+		err = m.AppService.SaveIdempotent(ctx, models.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			StatusCode:  rec.status,
+			ContentType: rec.contentType,
+			Body:        rec.body.Bytes(),
+		})
+		if err != nil {
+			logger.Log.Debug("Ошибка сохранения ответа по ключу идемпотентности", zap.Error(err))
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware_Idempotency(t *testing.T) {
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	appService := shortenerservice.NewShortenerService(&storage.Storage{Storage: storage.NewCacheStorage()}, cfg)
+	m := Middleware{Cfg: cfg, AppService: appService}
+
+	calls := 0
+	handler := m.Idempotency(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"result":"http://localhost:8080/6YGS4ZUF"}`))
+	}))
+
+	userID := auth.NewJwtService("verycomplexsecretkey").EnsureRandom()
+	otherUserID := auth.NewJwtService("verycomplexsecretkey").EnsureRandom()
+
+	tests := []struct {
+		name         string
+		key          string
+		userID       uuid.UUID
+		body         string
+		wantStatus   int
+		wantCalls    int
+		wantReplayed bool
+	}{
+		{
+			name:       "First_Request",
+			key:        "key-1",
+			userID:     userID,
+			body:       `{"url":"https://ya.ru"}`,
+			wantStatus: http.StatusCreated,
+			wantCalls:  1,
+		},
+		{
+			name:         "Retry_Replayed",
+			key:          "key-1",
+			userID:       userID,
+			body:         `{"url":"https://ya.ru"}`,
+			wantStatus:   http.StatusCreated,
+			wantCalls:    1,
+			wantReplayed: true,
+		},
+		{
+			name:       "Key_Reused_With_Other_Body",
+			key:        "key-1",
+			userID:     userID,
+			body:       `{"url":"https://dzen.ru"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCalls:  1,
+		},
+		{
+			name:       "Same_Key_Other_User",
+			key:        "key-1",
+			userID:     otherUserID,
+			body:       `{"url":"https://dzen.ru"}`,
+			wantStatus: http.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "Without_Key",
+			userID:     userID,
+			body:       `{"url":"https://ya.ru"}`,
+			wantStatus: http.StatusCreated,
+			wantCalls:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(tt.body))
+			r = r.WithContext(auth.Context(r.Context(), tt.userID))
+			if tt.key != "" {
+				r.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantReplayed {
+				assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				assert.JSONEq(t, `{"result":"http://localhost:8080/6YGS4ZUF"}`, w.Body.String())
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/romanp1989/go-shortener/internal/compress"
 	"github.com/romanp1989/go-shortener/internal/config"
 	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
 	"go.uber.org/zap"
 	"net"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 type Middleware struct {
 	Cfg        *config.ConfigENV
 	JwtService *auth.JWTService
+	AppService *shortenerservice.ShortenerService
 }
 
 // GzipMiddleware Middleware for archiving the hanlders response
@@ -108,7 +110,7 @@ func (m Middleware) AuthMiddlewareSet(h http.Handler) http.Handler {
 
 			m.newCookie(w, uid, m.Cfg.SecretKey)
 		} else if cookie.Value != "" {
-			uid, _ = m.JwtService.GetUserID(cookie.Value)
+			uid = m.cookieUser(r, cookie.Value)
 		}
 
 		if uid == nil {
@@ -141,7 +143,7 @@ func (m Middleware) AuthMiddlewareRead(h http.Handler) http.Handler {
 
 			m.newCookie(w, uid, m.Cfg.SecretKey)
 		} else if cookie.Value != "" {
-			uid, _ = m.JwtService.GetUserID(cookie.Value)
+			uid = m.cookieUser(r, cookie.Value)
 		}
 
 		if uid == nil {
@@ -156,6 +158,43 @@ func (m Middleware) AuthMiddlewareRead(h http.Handler) http.Handler {
 	})
 }
 
+// AuthMiddlewareOptional Middleware for authorization users if cookie is set, request without valid cookie is anonymous
+func (m Middleware) AuthMiddlewareOptional(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("auth")
+		if err == nil && cookie.Value != "" {
+			if uid := m.cookieUser(r, cookie.Value); uid != nil {
+				r = r.WithContext(auth.Context(r.Context(), *uid))
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// cookieUser function returns user id of valid auth token, token of login session is valid until session is finished,
+// token without session isn't accepted for registered user
+func (m Middleware) cookieUser(r *http.Request, token string) *uuid.UUID {
+	claims, err := m.JwtService.ParseToken(token)
+	if err != nil || claims.UserID == nil {
+		return nil
+	}
+
+	if m.AppService == nil {
+		// без сервиса проверить сессию нельзя, принимаются только токены анонимных пользователей
+		if claims.SessionID != "" {
+			return nil
+		}
+		return claims.UserID
+	}
+	if err = m.AppService.CheckSession(r.Context(), claims); err != nil {
+		logger.Log.Debug("Сессия пользователя недействительна", zap.Error(err))
+		return nil
+	}
+
+	return claims.UserID
+}
+
 // ValidateSubnet validate user ip for internal access
 func (m Middleware) ValidateSubnet(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
@@ -36,6 +37,21 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// ClaimUrls mocks base method.
+func (m *MockStorage) ClaimUrls(arg0 context.Context, arg1, arg2 *uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimUrls", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimUrls indicates an expected call of ClaimUrls.
+func (mr *MockStorageMockRecorder) ClaimUrls(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimUrls", reflect.TypeOf((*MockStorage)(nil).ClaimUrls), arg0, arg1, arg2)
+}
+
 // DeleteBatch mocks base method.
 func (m *MockStorage) DeleteBatch(arg0 context.Context, arg1 *uuid.UUID, arg2 []string) error {
 	m.ctrl.T.Helper()
@@ -50,6 +66,90 @@ func (mr *MockStorageMockRecorder) DeleteBatch(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockStorage)(nil).DeleteBatch), arg0, arg1, arg2)
 }
 
+// DeleteCollection mocks base method.
+func (m *MockStorage) DeleteCollection(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockStorageMockRecorder) DeleteCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockStorage)(nil).DeleteCollection), arg0, arg1)
+}
+
+// DeleteExpiredSessions mocks base method.
+func (m *MockStorage) DeleteExpiredSessions(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSessions", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
+func (mr *MockStorageMockRecorder) DeleteExpiredSessions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredSessions), arg0)
+}
+
+// DeleteSession mocks base method.
+func (m *MockStorage) DeleteSession(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockStorageMockRecorder) DeleteSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStorage)(nil).DeleteSession), arg0, arg1)
+}
+
+// DeleteWorkspace mocks base method.
+func (m *MockStorage) DeleteWorkspace(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspace indicates an expected call of DeleteWorkspace.
+func (mr *MockStorageMockRecorder) DeleteWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspace", reflect.TypeOf((*MockStorage)(nil).DeleteWorkspace), arg0, arg1)
+}
+
+// DeleteWorkspaceBatch mocks base method.
+func (m *MockStorage) DeleteWorkspaceBatch(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceBatch indicates an expected call of DeleteWorkspaceBatch.
+func (mr *MockStorageMockRecorder) DeleteWorkspaceBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceBatch", reflect.TypeOf((*MockStorage)(nil).DeleteWorkspaceBatch), arg0, arg1, arg2)
+}
+
+// DeleteWorkspaceMember mocks base method.
+func (m *MockStorage) DeleteWorkspaceMember(arg0 context.Context, arg1 string, arg2 *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceMember indicates an expected call of DeleteWorkspaceMember.
+func (mr *MockStorageMockRecorder) DeleteWorkspaceMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceMember", reflect.TypeOf((*MockStorage)(nil).DeleteWorkspaceMember), arg0, arg1, arg2)
+}
+
 // Get mocks base method.
 func (m *MockStorage) Get(arg0 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +165,36 @@ func (mr *MockStorageMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStorage)(nil).Get), arg0)
 }
 
+// GetAccount mocks base method.
+func (m *MockStorage) GetAccount(arg0 context.Context, arg1 *uuid.UUID) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStorageMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStorage)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountByEmail mocks base method.
+func (m *MockStorage) GetAccountByEmail(arg0 context.Context, arg1 string) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByEmail", arg0, arg1)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByEmail indicates an expected call of GetAccountByEmail.
+func (mr *MockStorageMockRecorder) GetAccountByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByEmail", reflect.TypeOf((*MockStorage)(nil).GetAccountByEmail), arg0, arg1)
+}
+
 // GetAllUrlsByUser mocks base method.
 func (m *MockStorage) GetAllUrlsByUser(arg0 context.Context, arg1 *uuid.UUID) ([]models.StorageURL, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +210,111 @@ func (mr *MockStorageMockRecorder) GetAllUrlsByUser(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUrlsByUser", reflect.TypeOf((*MockStorage)(nil).GetAllUrlsByUser), arg0, arg1)
 }
 
+// GetCollection mocks base method.
+func (m *MockStorage) GetCollection(arg0 context.Context, arg1 string) (*models.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", arg0, arg1)
+	ret0, _ := ret[0].(*models.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockStorageMockRecorder) GetCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockStorage)(nil).GetCollection), arg0, arg1)
+}
+
+// GetCollections mocks base method.
+func (m *MockStorage) GetCollections(arg0 context.Context, arg1 *uuid.UUID) ([]models.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollections", arg0, arg1)
+	ret0, _ := ret[0].([]models.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollections indicates an expected call of GetCollections.
+func (mr *MockStorageMockRecorder) GetCollections(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollections", reflect.TypeOf((*MockStorage)(nil).GetCollections), arg0, arg1)
+}
+
+// GetIdempotencyRecord mocks base method.
+func (m *MockStorage) GetIdempotencyRecord(arg0 context.Context, arg1 *uuid.UUID, arg2 string) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyRecord indicates an expected call of GetIdempotencyRecord.
+func (mr *MockStorageMockRecorder) GetIdempotencyRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyRecord", reflect.TypeOf((*MockStorage)(nil).GetIdempotencyRecord), arg0, arg1, arg2)
+}
+
+// GetLink mocks base method.
+func (m *MockStorage) GetLink(arg0 context.Context, arg1 string) (*models.StorageURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLink", arg0, arg1)
+	ret0, _ := ret[0].(*models.StorageURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLink indicates an expected call of GetLink.
+func (mr *MockStorageMockRecorder) GetLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLink", reflect.TypeOf((*MockStorage)(nil).GetLink), arg0, arg1)
+}
+
+// GetLinksForCheck mocks base method.
+func (m *MockStorage) GetLinksForCheck(arg0 context.Context, arg1 time.Time, arg2 int) ([]models.StorageURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksForCheck", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.StorageURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksForCheck indicates an expected call of GetLinksForCheck.
+func (mr *MockStorageMockRecorder) GetLinksForCheck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksForCheck", reflect.TypeOf((*MockStorage)(nil).GetLinksForCheck), arg0, arg1, arg2)
+}
+
+// GetScheduledLinks mocks base method.
+func (m *MockStorage) GetScheduledLinks(arg0 context.Context) ([]models.StorageURL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledLinks", arg0)
+	ret0, _ := ret[0].([]models.StorageURL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledLinks indicates an expected call of GetScheduledLinks.
+func (mr *MockStorageMockRecorder) GetScheduledLinks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledLinks", reflect.TypeOf((*MockStorage)(nil).GetScheduledLinks), arg0)
+}
+
+// GetSession mocks base method.
+func (m *MockStorage) GetSession(arg0 context.Context, arg1 string) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStorageMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStorage)(nil).GetSession), arg0, arg1)
+}
+
 // GetStats mocks base method.
 func (m *MockStorage) GetStats(arg0 context.Context) (models.StorageStats, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +330,154 @@ func (mr *MockStorageMockRecorder) GetStats(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockStorage)(nil).GetStats), arg0)
 }
 
+// GetUrlsPageByUser mocks base method.
+func (m *MockStorage) GetUrlsPageByUser(arg0 context.Context, arg1 *uuid.UUID, arg2, arg3 int) ([]models.StorageURL, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUrlsPageByUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.StorageURL)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUrlsPageByUser indicates an expected call of GetUrlsPageByUser.
+func (mr *MockStorageMockRecorder) GetUrlsPageByUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUrlsPageByUser", reflect.TypeOf((*MockStorage)(nil).GetUrlsPageByUser), arg0, arg1, arg2, arg3)
+}
+
+// GetUrlsPageByWorkspace mocks base method.
+func (m *MockStorage) GetUrlsPageByWorkspace(arg0 context.Context, arg1 string, arg2, arg3 int) ([]models.StorageURL, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUrlsPageByWorkspace", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.StorageURL)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUrlsPageByWorkspace indicates an expected call of GetUrlsPageByWorkspace.
+func (mr *MockStorageMockRecorder) GetUrlsPageByWorkspace(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUrlsPageByWorkspace", reflect.TypeOf((*MockStorage)(nil).GetUrlsPageByWorkspace), arg0, arg1, arg2, arg3)
+}
+
+// GetUserSettings mocks base method.
+func (m *MockStorage) GetUserSettings(arg0 context.Context, arg1 *uuid.UUID) (*models.UserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSettings", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSettings indicates an expected call of GetUserSettings.
+func (mr *MockStorageMockRecorder) GetUserSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSettings", reflect.TypeOf((*MockStorage)(nil).GetUserSettings), arg0, arg1)
+}
+
+// GetUserWorkspaces mocks base method.
+func (m *MockStorage) GetUserWorkspaces(arg0 context.Context, arg1 *uuid.UUID) ([]models.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWorkspaces", arg0, arg1)
+	ret0, _ := ret[0].([]models.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWorkspaces indicates an expected call of GetUserWorkspaces.
+func (mr *MockStorageMockRecorder) GetUserWorkspaces(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkspaces", reflect.TypeOf((*MockStorage)(nil).GetUserWorkspaces), arg0, arg1)
+}
+
+// GetWorkspace mocks base method.
+func (m *MockStorage) GetWorkspace(arg0 context.Context, arg1 string) (*models.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(*models.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspace indicates an expected call of GetWorkspace.
+func (mr *MockStorageMockRecorder) GetWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspace", reflect.TypeOf((*MockStorage)(nil).GetWorkspace), arg0, arg1)
+}
+
+// GetWorkspaceMembers mocks base method.
+func (m *MockStorage) GetWorkspaceMembers(arg0 context.Context, arg1 string) ([]models.WorkspaceMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceMembers", arg0, arg1)
+	ret0, _ := ret[0].([]models.WorkspaceMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceMembers indicates an expected call of GetWorkspaceMembers.
+func (mr *MockStorageMockRecorder) GetWorkspaceMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceMembers", reflect.TypeOf((*MockStorage)(nil).GetWorkspaceMembers), arg0, arg1)
+}
+
+// IncrementClicks mocks base method.
+func (m *MockStorage) IncrementClicks(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementClicks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementClicks indicates an expected call of IncrementClicks.
+func (mr *MockStorageMockRecorder) IncrementClicks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementClicks", reflect.TypeOf((*MockStorage)(nil).IncrementClicks), arg0, arg1)
+}
+
+// IncrementVariantClicks mocks base method.
+func (m *MockStorage) IncrementVariantClicks(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementVariantClicks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementVariantClicks indicates an expected call of IncrementVariantClicks.
+func (mr *MockStorageMockRecorder) IncrementVariantClicks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementVariantClicks", reflect.TypeOf((*MockStorage)(nil).IncrementVariantClicks), arg0, arg1, arg2)
+}
+
+// IterateUrlsByUser mocks base method.
+func (m *MockStorage) IterateUrlsByUser(arg0 context.Context, arg1 *uuid.UUID, arg2 func(models.StorageURL) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateUrlsByUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateUrlsByUser indicates an expected call of IterateUrlsByUser.
+func (mr *MockStorageMockRecorder) IterateUrlsByUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateUrlsByUser", reflect.TypeOf((*MockStorage)(nil).IterateUrlsByUser), arg0, arg1, arg2)
+}
+
+// IterateUrlsByWorkspace mocks base method.
+func (m *MockStorage) IterateUrlsByWorkspace(arg0 context.Context, arg1 string, arg2 func(models.StorageURL) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateUrlsByWorkspace", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateUrlsByWorkspace indicates an expected call of IterateUrlsByWorkspace.
+func (mr *MockStorageMockRecorder) IterateUrlsByWorkspace(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateUrlsByWorkspace", reflect.TypeOf((*MockStorage)(nil).IterateUrlsByWorkspace), arg0, arg1, arg2)
+}
+
 // Ping mocks base method.
 func (m *MockStorage) Ping(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -124,6 +507,20 @@ func (mr *MockStorageMockRecorder) Save(arg0, arg1, arg2, arg3 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStorage)(nil).Save), arg0, arg1, arg2, arg3)
 }
 
+// SaveAccount mocks base method.
+func (m *MockStorage) SaveAccount(arg0 context.Context, arg1 models.Account) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAccount indicates an expected call of SaveAccount.
+func (mr *MockStorageMockRecorder) SaveAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAccount", reflect.TypeOf((*MockStorage)(nil).SaveAccount), arg0, arg1)
+}
+
 // SaveBatch mocks base method.
 func (m *MockStorage) SaveBatch(arg0 context.Context, arg1 []models.StorageURL, arg2 *uuid.UUID) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -138,3 +535,159 @@ func (mr *MockStorageMockRecorder) SaveBatch(arg0, arg1, arg2 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockStorage)(nil).SaveBatch), arg0, arg1, arg2)
 }
+
+// SaveCollection mocks base method.
+func (m *MockStorage) SaveCollection(arg0 context.Context, arg1 models.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCollection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCollection indicates an expected call of SaveCollection.
+func (mr *MockStorageMockRecorder) SaveCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCollection", reflect.TypeOf((*MockStorage)(nil).SaveCollection), arg0, arg1)
+}
+
+// SaveIdempotencyRecord mocks base method.
+func (m *MockStorage) SaveIdempotencyRecord(arg0 context.Context, arg1 models.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyRecord", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyRecord indicates an expected call of SaveIdempotencyRecord.
+func (mr *MockStorageMockRecorder) SaveIdempotencyRecord(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyRecord", reflect.TypeOf((*MockStorage)(nil).SaveIdempotencyRecord), arg0, arg1)
+}
+
+// SaveLink mocks base method.
+func (m *MockStorage) SaveLink(arg0 context.Context, arg1 models.StorageURL) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLink", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveLink indicates an expected call of SaveLink.
+func (mr *MockStorageMockRecorder) SaveLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLink", reflect.TypeOf((*MockStorage)(nil).SaveLink), arg0, arg1)
+}
+
+// SaveLinkCheck mocks base method.
+func (m *MockStorage) SaveLinkCheck(arg0 context.Context, arg1 string, arg2 int, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLinkCheck", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLinkCheck indicates an expected call of SaveLinkCheck.
+func (mr *MockStorageMockRecorder) SaveLinkCheck(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLinkCheck", reflect.TypeOf((*MockStorage)(nil).SaveLinkCheck), arg0, arg1, arg2, arg3)
+}
+
+// SavePageMetadata mocks base method.
+func (m *MockStorage) SavePageMetadata(arg0 context.Context, arg1 string, arg2 models.PageMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePageMetadata", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePageMetadata indicates an expected call of SavePageMetadata.
+func (mr *MockStorageMockRecorder) SavePageMetadata(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePageMetadata", reflect.TypeOf((*MockStorage)(nil).SavePageMetadata), arg0, arg1, arg2)
+}
+
+// SaveSession mocks base method.
+func (m *MockStorage) SaveSession(arg0 context.Context, arg1 models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSession indicates an expected call of SaveSession.
+func (mr *MockStorageMockRecorder) SaveSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MockStorage)(nil).SaveSession), arg0, arg1)
+}
+
+// SaveUserSettings mocks base method.
+func (m *MockStorage) SaveUserSettings(arg0 context.Context, arg1 models.UserSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserSettings", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserSettings indicates an expected call of SaveUserSettings.
+func (mr *MockStorageMockRecorder) SaveUserSettings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSettings", reflect.TypeOf((*MockStorage)(nil).SaveUserSettings), arg0, arg1)
+}
+
+// SaveWorkspace mocks base method.
+func (m *MockStorage) SaveWorkspace(arg0 context.Context, arg1 models.Workspace) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWorkspace indicates an expected call of SaveWorkspace.
+func (mr *MockStorageMockRecorder) SaveWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWorkspace", reflect.TypeOf((*MockStorage)(nil).SaveWorkspace), arg0, arg1)
+}
+
+// SaveWorkspaceMember mocks base method.
+func (m *MockStorage) SaveWorkspaceMember(arg0 context.Context, arg1 models.WorkspaceMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWorkspaceMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWorkspaceMember indicates an expected call of SaveWorkspaceMember.
+func (mr *MockStorageMockRecorder) SaveWorkspaceMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWorkspaceMember", reflect.TypeOf((*MockStorage)(nil).SaveWorkspaceMember), arg0, arg1)
+}
+
+// Search mocks base method.
+func (m *MockStorage) Search(arg0 context.Context, arg1 *uuid.UUID, arg2 string, arg3 int) ([]models.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockStorageMockRecorder) Search(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockStorage)(nil).Search), arg0, arg1, arg2, arg3)
+}
+
+// UpdateLink mocks base method.
+func (m *MockStorage) UpdateLink(arg0 context.Context, arg1 models.StorageURL) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLink", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLink indicates an expected call of UpdateLink.
+func (mr *MockStorageMockRecorder) UpdateLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLink", reflect.TypeOf((*MockStorage)(nil).UpdateLink), arg0, arg1)
+}
@@ -3,11 +3,16 @@ package models
 import (
 	"context"
 	"github.com/gofrs/uuid"
+	"time"
 )
 
 // ShortenRequest structure for Shorten handler request
 type ShortenRequest struct {
-	URL string `json:"url"`
+	URL    string   `json:"url"`
+	Title  string   `json:"title,omitempty"`
+	Notes  string   `json:"notes,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	Domain string   `json:"domain,omitempty"`
 }
 
 // ShortenResponse structure for Shorten handler response
@@ -15,22 +20,96 @@ type ShortenResponse struct {
 	Result string `json:"result"`
 }
 
-// StorageURL structure for save URLs in DB
+// UserURL structure for item of user's URLs list of API v1
+type UserURL struct {
+	OriginalURL string `json:"original_url"`
+	ShortURL    string `json:"short_url"`
+}
+
+// StorageURL structure for save URLs in DB.
+// Domain is requested domain of new short link, domain of saved link is part of its short URL key
 type StorageURL struct {
-	UserID      *uuid.UUID `json:"user_id"`
-	OriginalURL string     `json:"original_url"`
-	ShortURL    string     `json:"short_url"`
+	UserID         *uuid.UUID     `json:"user_id"`
+	OriginalURL    string         `json:"original_url"`
+	ShortURL       string         `json:"short_url"`
+	Title          string         `json:"title,omitempty"`
+	Notes          string         `json:"notes,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`
+	DeletedFlag    bool           `json:"is_deleted,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Clicks         int64          `json:"clicks,omitempty"`
+	Interstitial   bool           `json:"interstitial,omitempty"`
+	PasswordHash   string         `json:"password_hash,omitempty"`
+	MaxClicks      int64          `json:"max_clicks,omitempty"`
+	ActiveFrom     *time.Time     `json:"active_from,omitempty"`
+	ActiveUntil    *time.Time     `json:"active_until,omitempty"`
+	FallbackURL    string         `json:"fallback_url,omitempty"`
+	Rules          []RedirectRule `json:"rules,omitempty"`
+	Variants       []Variant      `json:"variants,omitempty"`
+	StickyVariants bool           `json:"sticky_variants,omitempty"`
+	ForwardQuery   bool           `json:"forward_query,omitempty"`
+	UTM            *UTMParams     `json:"utm,omitempty"`
+	LastStatus     int            `json:"last_status,omitempty"`
+	LastCheckedAt  *time.Time     `json:"last_checked_at,omitempty"`
+	Page           *PageMetadata  `json:"page,omitempty"`
+	CollectionID   string         `json:"collection_id,omitempty"`
+	WorkspaceID    string         `json:"workspace_id,omitempty"`
+	Domain         string         `json:"-"`
+}
+
+// Private function checks that link has clicks limit or password, such link gets random short code
+// and isn't found by original URL
+func (l StorageURL) Private() bool {
+	return l.MaxClicks > 0 || l.PasswordHash != ""
 }
 
 // Storage interface for storage
 type Storage interface {
 	Save(ctx context.Context, OriginalURL string, ShortURL string, userID *uuid.UUID) (string, error)
+	SaveLink(ctx context.Context, link StorageURL) (string, error)
 	Get(inputURL string) (string, error)
 	SaveBatch(ctx context.Context, urls []StorageURL, userID *uuid.UUID) ([]string, error)
 	Ping(ctx context.Context) error
 	GetAllUrlsByUser(ctx context.Context, userID *uuid.UUID) ([]StorageURL, error)
+	IterateUrlsByUser(ctx context.Context, userID *uuid.UUID, fn func(StorageURL) error) error
+	GetUrlsPageByUser(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]StorageURL, int, error)
 	DeleteBatch(ctx context.Context, userID *uuid.UUID, urls []string) error
 	GetStats(ctx context.Context) (StorageStats, error)
+	Search(ctx context.Context, userID *uuid.UUID, query string, limit int) ([]SearchResult, error)
+	GetIdempotencyRecord(ctx context.Context, userID *uuid.UUID, key string) (*IdempotencyRecord, error)
+	SaveIdempotencyRecord(ctx context.Context, record IdempotencyRecord) error
+	GetLink(ctx context.Context, shortURL string) (*StorageURL, error)
+	UpdateLink(ctx context.Context, link StorageURL) error
+	IncrementClicks(ctx context.Context, shortURL string) error
+	GetLinksForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]StorageURL, error)
+	SaveLinkCheck(ctx context.Context, shortURL string, status int, checkedAt time.Time) error
+	SavePageMetadata(ctx context.Context, shortURL string, page PageMetadata) error
+	GetUserSettings(ctx context.Context, userID *uuid.UUID) (*UserSettings, error)
+	GetCollections(ctx context.Context, userID *uuid.UUID) ([]Collection, error)
+	GetCollection(ctx context.Context, id string) (*Collection, error)
+	SaveCollection(ctx context.Context, collection Collection) error
+	DeleteCollection(ctx context.Context, id string) error
+	GetUserWorkspaces(ctx context.Context, userID *uuid.UUID) ([]Workspace, error)
+	GetWorkspace(ctx context.Context, id string) (*Workspace, error)
+	SaveWorkspace(ctx context.Context, workspace Workspace) error
+	DeleteWorkspace(ctx context.Context, id string) error
+	GetWorkspaceMembers(ctx context.Context, workspaceID string) ([]WorkspaceMember, error)
+	SaveWorkspaceMember(ctx context.Context, member WorkspaceMember) error
+	DeleteWorkspaceMember(ctx context.Context, workspaceID string, userID *uuid.UUID) error
+	IterateUrlsByWorkspace(ctx context.Context, workspaceID string, fn func(StorageURL) error) error
+	GetUrlsPageByWorkspace(ctx context.Context, workspaceID string, limit int, offset int) ([]StorageURL, int, error)
+	DeleteWorkspaceBatch(ctx context.Context, workspaceID string, urls []string) error
+	GetAccount(ctx context.Context, userID *uuid.UUID) (*Account, error)
+	GetAccountByEmail(ctx context.Context, email string) (*Account, error)
+	SaveAccount(ctx context.Context, account Account) error
+	GetSession(ctx context.Context, id string) (*Session, error)
+	SaveSession(ctx context.Context, session Session) error
+	DeleteSession(ctx context.Context, id string) error
+	DeleteExpiredSessions(ctx context.Context) error
+	ClaimUrls(ctx context.Context, from *uuid.UUID, to *uuid.UUID) (int, error)
+	SaveUserSettings(ctx context.Context, settings UserSettings) error
+	IncrementVariantClicks(ctx context.Context, shortURL string, variantID string) error
+	GetScheduledLinks(ctx context.Context) ([]StorageURL, error)
 }
 
 // BatchShortenRequest structure for batch save URLs handler request
@@ -39,13 +118,429 @@ type BatchShortenRequest struct {
 	OriginalURL   string `json:"original_url"`
 }
 
+// Статусы элементов пакетного сокращения URL
+const (
+	// BatchStatusCreated new short URL is created
+	BatchStatusCreated = "created"
+	// BatchStatusExisting URL was shortened before
+	BatchStatusExisting = "existing"
+	// BatchStatusInvalid URL isn't saved, reason is in error field
+	BatchStatusInvalid = "invalid"
+)
+
 // BatchShortenResponse structure for batch save URLs handler response
 type BatchShortenResponse struct {
 	CorrelationID string `json:"correlation_id"`
-	ShortURL      string `json:"short_url"`
+	ShortURL      string `json:"short_url,omitempty"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
 }
 
 type StorageStats struct {
 	Users int64 `json:"users"`
 	URLs  int64 `json:"urls"`
 }
+
+// SearchResult structure for user's URLs search response, ordered by rank
+type SearchResult struct {
+	ShortURL    string   `json:"short_url"`
+	OriginalURL string   `json:"original_url"`
+	Title       string   `json:"title,omitempty"`
+	Notes       string   `json:"notes,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Rank        float64  `json:"rank"`
+}
+
+// ImportRow structure for a row of CSV or NDJSON bulk import request
+type ImportRow struct {
+	Line          int    `json:"-"`
+	CorrelationID string `json:"correlation_id"`
+	OriginalURL   string `json:"original_url"`
+	Error         string `json:"-"`
+}
+
+// ImportResult structure for a row result of bulk import response
+type ImportResult struct {
+	Line          int    `json:"line"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	OriginalURL   string `json:"original_url"`
+	ShortURL      string `json:"short_url,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ExportURL structure for a link of user's URLs export
+type ExportURL struct {
+	ShortURL    string    `json:"short_url"`
+	OriginalURL string    `json:"original_url"`
+	Title       string    `json:"title"`
+	Notes       string    `json:"notes"`
+	Tags        []string  `json:"tags"`
+	IsDeleted   bool      `json:"is_deleted"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// IdempotencyRecord structure for saved response of request with idempotency key
+type IdempotencyRecord struct {
+	UserID      *uuid.UUID `json:"user_id"`
+	Key         string     `json:"key"`
+	RequestHash string     `json:"request_hash"`
+	StatusCode  int        `json:"status_code"`
+	ContentType string     `json:"content_type"`
+	Body        []byte     `json:"body"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+}
+
+// Link structure for link resource of v2 API
+type Link struct {
+	ID                string         `json:"id"`
+	ShortURL          string         `json:"short_url"`
+	OriginalURL       string         `json:"original_url"`
+	Title             string         `json:"title"`
+	Notes             string         `json:"notes"`
+	Tags              []string       `json:"tags"`
+	Interstitial      bool           `json:"interstitial"`
+	PasswordProtected bool           `json:"password_protected"`
+	MaxClicks         int64          `json:"max_clicks"`
+	ActiveFrom        *time.Time     `json:"active_from,omitempty"`
+	ActiveUntil       *time.Time     `json:"active_until,omitempty"`
+	FallbackURL       string         `json:"fallback_url,omitempty"`
+	Rules             []RedirectRule `json:"rules"`
+	Variants          []Variant      `json:"variants"`
+	StickyVariants    bool           `json:"sticky_variants"`
+	ForwardQuery      bool           `json:"forward_query"`
+	UTM               *UTMParams     `json:"utm,omitempty"`
+	Page              *PageMetadata  `json:"page,omitempty"`
+	Domain            string         `json:"domain"`
+	CollectionID      string         `json:"collection_id,omitempty"`
+	WorkspaceID       string         `json:"workspace_id,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
+
+// LinkCreateRequest structure for create link request of v2 API, zero max clicks means unlimited link.
+// Link resolves only in active window, fallback URL is used for redirect outside of window.
+// Query parameters of short link are forwarded to destination if forward query is enabled,
+// link is added to user's collection if collection id is set, link is owned by workspace if workspace id is set
+type LinkCreateRequest struct {
+	OriginalURL    string         `json:"original_url"`
+	Domain         string         `json:"domain"`
+	CollectionID   string         `json:"collection_id"`
+	WorkspaceID    string         `json:"workspace_id"`
+	Title          string         `json:"title"`
+	Notes          string         `json:"notes"`
+	Tags           []string       `json:"tags"`
+	Interstitial   bool           `json:"interstitial"`
+	Password       string         `json:"password"`
+	MaxClicks      int64          `json:"max_clicks"`
+	ActiveFrom     *time.Time     `json:"active_from"`
+	ActiveUntil    *time.Time     `json:"active_until"`
+	FallbackURL    string         `json:"fallback_url"`
+	Rules          []RedirectRule `json:"rules"`
+	Variants       []Variant      `json:"variants"`
+	StickyVariants bool           `json:"sticky_variants"`
+	ForwardQuery   bool           `json:"forward_query"`
+	UTM            *UTMParams     `json:"utm"`
+}
+
+// LinkUpdateRequest structure for update link request of v2 API, omitted fields aren't changed.
+// Empty password removes password protection, zero max clicks removes clicks limit.
+// Active window bounds are RFC 3339 times, empty string removes bound, empty fallback URL removes fallback.
+// UTM parameters are replaced, empty object removes them, empty collection id removes link from collection.
+// Creator can move link to workspace or back to own links with empty workspace id
+type LinkUpdateRequest struct {
+	Title          *string         `json:"title"`
+	Notes          *string         `json:"notes"`
+	Tags           *[]string       `json:"tags"`
+	Interstitial   *bool           `json:"interstitial"`
+	Password       *string         `json:"password"`
+	MaxClicks      *int64          `json:"max_clicks"`
+	ActiveFrom     *string         `json:"active_from"`
+	ActiveUntil    *string         `json:"active_until"`
+	FallbackURL    *string         `json:"fallback_url"`
+	Rules          *[]RedirectRule `json:"rules"`
+	Variants       *[]Variant      `json:"variants"`
+	StickyVariants *bool           `json:"sticky_variants"`
+	ForwardQuery   *bool           `json:"forward_query"`
+	UTM            *UTMParams      `json:"utm"`
+	CollectionID   *string         `json:"collection_id"`
+	WorkspaceID    *string         `json:"workspace_id"`
+}
+
+// LinkListMeta structure for pagination of links list of v2 API
+type LinkListMeta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// Состояния короткой ссылки
+const (
+	// LinkStateActive link redirects to original URL
+	LinkStateActive = "active"
+	// LinkStateDeleted link is deleted by owner
+	LinkStateDeleted = "deleted"
+	// LinkStateExpired all redirects of link with clicks limit are used or active window is ended
+	LinkStateExpired = "expired"
+	// LinkStateScheduled active window of link isn't started
+	LinkStateScheduled = "scheduled"
+)
+
+// LinkInfo structure for short link inspection response, metadata is filled for owner only.
+// Original URL of password-protected link is shown to owner only, A/B variants with clicks are shown to owner only
+type LinkInfo struct {
+	ID                string        `json:"id"`
+	ShortURL          string        `json:"short_url"`
+	OriginalURL       string        `json:"original_url,omitempty"`
+	CreatedAt         time.Time     `json:"created_at"`
+	State             string        `json:"state"`
+	Clicks            int64         `json:"clicks"`
+	MaxClicks         int64         `json:"max_clicks,omitempty"`
+	ActiveFrom        *time.Time    `json:"active_from,omitempty"`
+	ActiveUntil       *time.Time    `json:"active_until,omitempty"`
+	PasswordProtected bool          `json:"password_protected"`
+	Variants          []Variant     `json:"variants,omitempty"`
+	Metadata          *LinkMetadata `json:"metadata,omitempty"`
+}
+
+// LinkMetadata structure for owner-visible link metadata
+type LinkMetadata struct {
+	Title string   `json:"title"`
+	Notes string   `json:"notes"`
+	Tags  []string `json:"tags"`
+}
+
+// LinkPreview structure for preview page of short link
+type LinkPreview struct {
+	ID          string
+	ShortURL    string
+	OriginalURL string
+	Title       string
+	Description string
+	Image       string
+}
+
+// LinkResolution structure for checks of short link before redirect, Link is empty if short link isn't found.
+// Preview is requested with preview suffix, Interstitial page is enabled for link or server,
+// Protected link is opened after password check only, Fallback is redirect URL for hit outside of active window
+type LinkResolution struct {
+	Link           LinkPreview
+	Preview        bool
+	Interstitial   bool
+	Protected      bool
+	Fallback       string
+	Rules          []RedirectRule
+	Variants       []Variant
+	StickyVariants bool
+	ForwardQuery   bool
+	UTM            *UTMParams
+}
+
+// UTMParams structure for UTM parameters appended to destination of short link.
+// Placeholders {id} and {variant} are replaced with short URL and chosen A/B variant
+type UTMParams struct {
+	Source   string `json:"utm_source,omitempty"`
+	Medium   string `json:"utm_medium,omitempty"`
+	Campaign string `json:"utm_campaign,omitempty"`
+	Term     string `json:"utm_term,omitempty"`
+	Content  string `json:"utm_content,omitempty"`
+}
+
+// UserSettings structure for user's settings, new links are created on default domain if domain isn't requested.
+// Domains is the list of configured domains in response
+type UserSettings struct {
+	UserID  *uuid.UUID `json:"user_id,omitempty"`
+	Domain  string     `json:"domain"`
+	Domains []string   `json:"domains,omitempty"`
+}
+
+// Collection structure for user's named group of links, link belongs to one collection at most.
+// Links is count of not deleted links of collection in response
+type Collection struct {
+	ID          string     `json:"id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Links       int        `json:"links"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CollectionRequest structure for create collection request
+type CollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CollectionUpdateRequest structure for update collection request, omitted fields aren't changed
+type CollectionUpdateRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// Роли участников рабочего пространства
+const (
+	// WorkspaceRoleOwner manages workspace and its members, changes links
+	WorkspaceRoleOwner = "owner"
+	// WorkspaceRoleEditor creates, changes and deletes links of workspace
+	WorkspaceRoleEditor = "editor"
+	// WorkspaceRoleViewer reads links of workspace
+	WorkspaceRoleViewer = "viewer"
+)
+
+// Workspace structure for team workspace owning shared links, Role is role of current user in response
+type Workspace struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WorkspaceMember structure for user's membership in workspace
+type WorkspaceMember struct {
+	WorkspaceID string     `json:"workspace_id,omitempty"`
+	UserID      *uuid.UUID `json:"user_id"`
+	Role        string     `json:"role"`
+}
+
+// WorkspaceRequest structure for create and update workspace request
+type WorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+// WorkspaceMemberRequest structure for add member or change member's role request
+type WorkspaceMemberRequest struct {
+	Role string `json:"role"`
+}
+
+// Account structure for registered user, account keeps user id of anonymous cookie identity it's registered from.
+// Password hash isn't returned in response
+type Account struct {
+	UserID       *uuid.UUID `json:"user_id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"password_hash,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// AccountRequest structure for registration and login request,
+// links of current anonymous user are merged into account on login if claim is set
+type AccountRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Claim    bool   `json:"claim"`
+}
+
+// Session structure for login session of account, session token is valid until session is deleted or expired
+type Session struct {
+	ID        string     `json:"id"`
+	UserID    *uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// AccountSession structure for registration and login response with session token,
+// claimed is count of links merged from anonymous user
+type AccountSession struct {
+	Account   Account   `json:"account"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Claimed   int       `json:"claimed,omitempty"`
+}
+
+// ClaimRequest structure for request to merge links of anonymous user into account,
+// token is auth cookie of anonymous user
+type ClaimRequest struct {
+	Token string `json:"token"`
+}
+
+// ClaimResponse structure for count of links merged into account
+type ClaimResponse struct {
+	Claimed int `json:"claimed"`
+}
+
+// PageMetadata structure for title, Open Graph description and image of destination page,
+// it's fetched in background after link creation
+type PageMetadata struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+}
+
+// Variant structure for weighted destination of A/B split link, clicks are counted per variant
+type Variant struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+	Clicks int64  `json:"clicks"`
+}
+
+// RedirectTarget structure for chosen destination of short link, Variant is set if A/B variant is chosen
+type RedirectTarget struct {
+	URL     string
+	Variant string
+}
+
+// Классы устройств для правил перенаправления
+const (
+	// DeviceIOS iPhone, iPad or iPod
+	DeviceIOS = "ios"
+	// DeviceAndroid Android phone or tablet
+	DeviceAndroid = "android"
+	// DeviceMobile any mobile device including iOS and Android
+	DeviceMobile = "mobile"
+	// DeviceDesktop not mobile device
+	DeviceDesktop = "desktop"
+)
+
+// RedirectRule structure for conditional redirect of short link, rule matches if all set conditions match.
+// Language is matched with preferred language of client by primary tag, country is ISO code from geo header
+type RedirectRule struct {
+	Device   string `json:"device,omitempty"`
+	Language string `json:"language,omitempty"`
+	Country  string `json:"country,omitempty"`
+	URL      string `json:"url"`
+}
+
+// ClientInfo structure for request attributes matched by redirect rules
+type ClientInfo struct {
+	UserAgent      string
+	AcceptLanguage string
+	Country        string
+	// Variant A/B variant assigned to client before, used for sticky links
+	Variant string
+	// Query raw query string of short link request, forwarded to destination
+	Query string
+}
+
+// Форматы изображения QR-кода
+const (
+	// QRFormatPNG PNG image
+	QRFormatPNG = "png"
+	// QRFormatSVG SVG image
+	QRFormatSVG = "svg"
+)
+
+// QROptions structure for QR code image options, zero values and nil margin are replaced with defaults.
+// Level is error correction level L, M, Q or H, margin is quiet zone width in modules
+type QROptions struct {
+	Size   int
+	Format string
+	Level  string
+	Margin *int
+}
+
+// QRCode structure for generated QR code image of short URL
+type QRCode struct {
+	Content     []byte
+	ContentType string
+	ETag        string
+}
+
+// ScheduledLink structure for admin list of links with active window
+type ScheduledLink struct {
+	ID          string     `json:"id"`
+	ShortURL    string     `json:"short_url"`
+	OriginalURL string     `json:"original_url"`
+	UserID      *uuid.UUID `json:"user_id"`
+	State       string     `json:"state"`
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+	FallbackURL string     `json:"fallback_url,omitempty"`
+}
@@ -0,0 +1,68 @@
+// Package openapi serves OpenAPI 3 specification of REST API and page for viewing it
+package openapi
+
+import (
+	"embed"
+	"github.com/go-chi/chi/v5"
+	"io/fs"
+	"net/http"
+)
+
+// Spec OpenAPI 3 specification of all routes registered in route.New
+//
+//go:embed openapi.json
+var Spec []byte
+
+// docsPage HTML page with Swagger UI viewer of specification
+//
+//go:embed docs.html
+var docsPage []byte
+
+// swaggerUI Swagger UI 5.18.2 assets, they are served with docs page so it doesn't depend on CDN
+//
+//go:embed swagger-ui/swagger-ui-bundle.js swagger-ui/swagger-ui.css
+var swaggerUI embed.FS
+
+// SpecHandler handler for getting OpenAPI specification
+// @Success 200 {json} OpenAPI 3 document
+func SpecHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(Spec)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// DocsHandler handler for viewing OpenAPI specification in browser
+// @Success 200 {html} Swagger UI page
+func DocsHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(docsPage)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// AssetHandler handler for getting Swagger UI script and styles of docs page
+// @Success 200 {js|css} asset file
+// @Failure 404 error if asset isn't found
+func AssetHandler() http.HandlerFunc {
+	assets, _ := fs.Sub(swaggerUI, "swagger-ui")
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "file")
+		if info, err := fs.Stat(assets, name); err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		http.ServeFileFS(w, r, assets, name)
+	}
+
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,321 @@
+// Package problem describes API errors in RFC 7807 problem details format
+// with stable machine-readable code, shared by REST and gRPC handlers
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// ContentType media type of problem details response
+const ContentType = "application/problem+json"
+
+// Domain error domain for gRPC error details
+const Domain = "go-shortener"
+
+// Коды ошибок API, значения не должны меняться
+const (
+	CodeBadRequest               = "bad_request"
+	CodeInvalidJSON              = "invalid_json"
+	CodeInvalidURL               = "invalid_url"
+	CodeEmptyBatch               = "empty_batch"
+	CodeBatchInvalid             = "batch_invalid"
+	CodeEmptySearchQuery         = "empty_search_query"
+	CodeUnsupportedFormat        = "unsupported_format"
+	CodeUnsupportedMediaType     = "unsupported_media_type"
+	CodeUnauthorized             = "unauthorized"
+	CodeNotFound                 = "not_found"
+	CodeURLConflict              = "url_conflict"
+	CodeURLDeleted               = "url_deleted"
+	CodeInternal                 = "internal"
+	CodeInvalidPagination        = "invalid_pagination"
+	CodeInvalidIdempotencyKey    = "invalid_idempotency_key"
+	CodeIdempotencyKeyReused     = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress = "idempotency_key_in_progress"
+	CodePasswordRequired         = "password_required"
+	CodeInvalidPassword          = "invalid_password"
+	CodeTooManyAttempts          = "too_many_attempts"
+	CodeLinkExpired              = "link_expired"
+	CodeInvalidMaxClicks         = "invalid_max_clicks"
+	CodeSharedLinkPrivate        = "shared_link_private"
+	CodePrivateLinkPublic        = "private_link_public"
+	CodeLinkNotActive            = "link_not_active"
+	CodeInvalidActiveWindow      = "invalid_active_window"
+	CodeInvalidRedirectRule      = "invalid_redirect_rule"
+	CodeInvalidVariants          = "invalid_variants"
+	CodeURLNotAllowed            = "url_not_allowed"
+	CodeInvalidStatusFilter      = "invalid_status_filter"
+	CodeInvalidQROptions         = "invalid_qr_options"
+	CodeUnknownDomain            = "unknown_domain"
+	CodeCollectionNotFound       = "collection_not_found"
+	CodeInvalidCollection        = "invalid_collection"
+	CodeWorkspaceNotFound        = "workspace_not_found"
+	CodeInvalidWorkspace         = "invalid_workspace"
+	CodeInvalidWorkspaceRole     = "invalid_workspace_role"
+	CodeWorkspaceForbidden       = "workspace_forbidden"
+	CodeLastWorkspaceOwner       = "last_workspace_owner"
+	CodeInvalidEmail             = "invalid_email"
+	CodeWeakPassword             = "weak_password"
+	CodeEmailTaken               = "email_taken"
+	CodeInvalidLogin             = "invalid_login"
+	CodeSessionExpired           = "session_expired"
+	CodeAccountRequired          = "account_required"
+	CodeInvalidClaimToken        = "invalid_claim_token"
+)
+
+// Problem structure for RFC 7807 problem details, extensions are written as top-level members
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       string
+	Extensions map[string]any
+}
+
+// New factory for create problem with status, code and detail message
+func New(status int, code string, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Error function returns problem detail message
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// With function adds extension member to problem
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// MarshalJSON function for encode problem with extension members
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+6)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	members["code"] = p.Code
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+
+	return json.Marshal(members)
+}
+
+// From function maps typed service and storage errors to problem, unknown errors are internal
+func From(err error) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p
+	}
+
+	var errConflict *storage.URLConflictError
+	if errors.As(err, &errConflict) {
+		return New(http.StatusConflict, CodeURLConflict, err.Error())
+	}
+
+	var errPolicy *shortenerservice.URLPolicyError
+	if errors.As(err, &errPolicy) {
+		return New(http.StatusBadRequest, CodeURLNotAllowed, err.Error()).With("url", errPolicy.URL).With("reason", errPolicy.Reason)
+	}
+
+	var errDeleted *storage.AlreadyDeleted
+	if errors.As(err, &errDeleted) {
+		return New(http.StatusGone, CodeURLDeleted, err.Error())
+	}
+
+	switch {
+	case errors.Is(err, storage.ErrClicksExhausted):
+		return New(http.StatusGone, CodeLinkExpired, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidURL):
+		return New(http.StatusBadRequest, CodeInvalidURL, err.Error())
+	case errors.Is(err, shortenerservice.ErrEmptySearchQuery):
+		return New(http.StatusBadRequest, CodeEmptySearchQuery, err.Error())
+	case errors.Is(err, shortenerservice.ErrUnauthorized):
+		return New(http.StatusUnauthorized, CodeUnauthorized, err.Error())
+	case errors.Is(err, shortenerservice.ErrLinkNotFound):
+		return New(http.StatusNotFound, CodeNotFound, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidPagination):
+		return New(http.StatusBadRequest, CodeInvalidPagination, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidIdempotencyKey):
+		return New(http.StatusBadRequest, CodeInvalidIdempotencyKey, err.Error())
+	case errors.Is(err, shortenerservice.ErrIdempotencyKeyReused):
+		return New(http.StatusUnprocessableEntity, CodeIdempotencyKeyReused, err.Error())
+	case errors.Is(err, shortenerservice.ErrIdempotencyKeyInProgress):
+		return New(http.StatusConflict, CodeIdempotencyKeyInProgress, err.Error())
+	case errors.Is(err, shortenerservice.ErrLinkPasswordRequired):
+		return New(http.StatusForbidden, CodePasswordRequired, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidLinkPassword):
+		return New(http.StatusForbidden, CodeInvalidPassword, err.Error())
+	case errors.Is(err, shortenerservice.ErrTooManyPasswordAttempts):
+		return New(http.StatusTooManyRequests, CodeTooManyAttempts, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidMaxClicks):
+		return New(http.StatusBadRequest, CodeInvalidMaxClicks, err.Error())
+	case errors.Is(err, shortenerservice.ErrSharedLinkPrivate):
+		return New(http.StatusConflict, CodeSharedLinkPrivate, err.Error())
+	case errors.Is(err, shortenerservice.ErrPrivateLinkPublic):
+		return New(http.StatusConflict, CodePrivateLinkPublic, err.Error())
+	case errors.Is(err, shortenerservice.ErrLinkNotActive):
+		return New(http.StatusNotFound, CodeLinkNotActive, err.Error())
+	case errors.Is(err, shortenerservice.ErrLinkWindowEnded):
+		return New(http.StatusGone, CodeLinkExpired, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidActiveWindow):
+		return New(http.StatusBadRequest, CodeInvalidActiveWindow, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidRedirectRule):
+		return New(http.StatusBadRequest, CodeInvalidRedirectRule, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidVariants):
+		return New(http.StatusBadRequest, CodeInvalidVariants, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidStatusFilter):
+		return New(http.StatusBadRequest, CodeInvalidStatusFilter, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidQROptions):
+		return New(http.StatusBadRequest, CodeInvalidQROptions, err.Error())
+	case errors.Is(err, shortenerservice.ErrUnknownDomain):
+		return New(http.StatusBadRequest, CodeUnknownDomain, err.Error())
+	case errors.Is(err, shortenerservice.ErrCollectionNotFound):
+		return New(http.StatusNotFound, CodeCollectionNotFound, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidCollection):
+		return New(http.StatusBadRequest, CodeInvalidCollection, err.Error())
+	case errors.Is(err, shortenerservice.ErrWorkspaceNotFound):
+		return New(http.StatusNotFound, CodeWorkspaceNotFound, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidWorkspace):
+		return New(http.StatusBadRequest, CodeInvalidWorkspace, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidWorkspaceRole):
+		return New(http.StatusBadRequest, CodeInvalidWorkspaceRole, err.Error())
+	case errors.Is(err, shortenerservice.ErrWorkspaceForbidden):
+		return New(http.StatusForbidden, CodeWorkspaceForbidden, err.Error())
+	case errors.Is(err, shortenerservice.ErrLastWorkspaceOwner):
+		return New(http.StatusConflict, CodeLastWorkspaceOwner, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidEmail):
+		return New(http.StatusBadRequest, CodeInvalidEmail, err.Error())
+	case errors.Is(err, shortenerservice.ErrWeakPassword):
+		return New(http.StatusBadRequest, CodeWeakPassword, err.Error())
+	case errors.Is(err, shortenerservice.ErrEmailTaken):
+		return New(http.StatusConflict, CodeEmailTaken, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidLogin):
+		return New(http.StatusUnauthorized, CodeInvalidLogin, err.Error())
+	case errors.Is(err, shortenerservice.ErrSessionExpired):
+		return New(http.StatusUnauthorized, CodeSessionExpired, err.Error())
+	case errors.Is(err, shortenerservice.ErrAccountRequired):
+		return New(http.StatusForbidden, CodeAccountRequired, err.Error())
+	case errors.Is(err, shortenerservice.ErrInvalidClaimToken):
+		return New(http.StatusBadRequest, CodeInvalidClaimToken, err.Error())
+	}
+
+	logger.Log.Debug("Внутренняя ошибка", zap.Error(err))
+	return New(http.StatusInternalServerError, CodeInternal, "внутренняя ошибка сервера")
+}
+
+// Write function writes problem as application/problem+json response
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Instance == "" && r != nil {
+		p.Instance = r.URL.Path
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		logger.Log.Debug("Ошибка создания ответа", zap.Error(err))
+		w.WriteHeader(p.Status)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(b)
+}
+
+// WriteError function writes error mapped to problem
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	Write(w, r, From(err))
+}
+
+// preconditionCodes codes of conflicts with current state of resource, not with existing resource.
+// They are mapped to FailedPrecondition, other conflicts are mapped to AlreadyExists
+var preconditionCodes = map[string]bool{
+	CodeIdempotencyKeyInProgress: true,
+	CodeSharedLinkPrivate:        true,
+	CodePrivateLinkPublic:        true,
+	CodeLastWorkspaceOwner:       true,
+}
+
+// GRPCCode function maps problem HTTP status to gRPC code, conflict is mapped to AlreadyExists
+func GRPCCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusGone:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// GRPCStatus function converts problem to gRPC status, code and string extensions are passed in ErrorInfo details
+func GRPCStatus(p *Problem) *status.Status {
+	code := GRPCCode(p.Status)
+	if p.Status == http.StatusConflict && preconditionCodes[p.Code] {
+		code = codes.FailedPrecondition
+	}
+	st := status.New(code, p.Error())
+
+	metadata := map[string]string{"status": fmt.Sprint(p.Status)}
+	for key, value := range p.Extensions {
+		if s, ok := value.(string); ok {
+			metadata[key] = s
+		}
+	}
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   p.Code,
+		Domain:   Domain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// GRPCError function maps error to gRPC status error with problem details
+func GRPCError(err error) error {
+	return GRPCStatus(From(err)).Err()
+}
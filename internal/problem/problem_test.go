@@ -0,0 +1,134 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "URL_Conflict",
+			err:        storage.NewURLConflictError("6YGS4ZUF", storage.ErrConflict),
+			wantStatus: http.StatusConflict,
+			wantCode:   CodeURLConflict,
+		},
+		{
+			name:       "URL_Deleted",
+			err:        fmt.Errorf("decode: %w", storage.NewAlreadyDeletedError("6YGS4ZUF")),
+			wantStatus: http.StatusGone,
+			wantCode:   CodeURLDeleted,
+		},
+		{
+			name:       "Invalid_URL",
+			err:        shortenerservice.ErrInvalidURL,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeInvalidURL,
+		},
+		{
+			name:       "URL_Not_Allowed",
+			err:        shortenerservice.NewURLPolicyError("javascript:alert(1)", shortenerservice.PolicyReasonScheme),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeURLNotAllowed,
+		},
+		{
+			name:       "Unauthorized",
+			err:        shortenerservice.ErrUnauthorized,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   CodeUnauthorized,
+		},
+		{
+			name:       "Problem",
+			err:        New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "text/csv"),
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantCode:   CodeUnsupportedMediaType,
+		},
+		{
+			name:       "Unknown_Error",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := From(tt.err)
+			assert.Equal(t, tt.wantStatus, p.Status)
+			assert.Equal(t, tt.wantCode, p.Code)
+			assert.Equal(t, http.StatusText(tt.wantStatus), p.Title)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/shorten", nil)
+	w := httptest.NewRecorder()
+
+	Write(w, r, New(http.StatusConflict, CodeURLConflict, "URL уже существует").With("result", "http://localhost:8080/6YGS4ZUF"))
+
+	result := w.Result()
+	defer result.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, result.StatusCode)
+	assert.Equal(t, ContentType, result.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Conflict",
+		"status": 409,
+		"detail": "URL уже существует",
+		"instance": "/api/shorten",
+		"code": "url_conflict",
+		"result": "http://localhost:8080/6YGS4ZUF"
+	}`, w.Body.String())
+}
+
+func TestGRPCError(t *testing.T) {
+	err := GRPCStatus(New(http.StatusGone, CodeURLDeleted, "URL удален").With("short_url", "6YGS4ZUF")).Err()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Equal(t, "URL удален", st.Message())
+
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, CodeURLDeleted, info.GetReason())
+	assert.Equal(t, Domain, info.GetDomain())
+	assert.Equal(t, map[string]string{"status": "410", "short_url": "6YGS4ZUF"}, info.GetMetadata())
+}
+
+func TestGRPCStatus_Conflict(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want codes.Code
+	}{
+		{name: "URL_Conflict", code: CodeURLConflict, want: codes.AlreadyExists},
+		{name: "Email_Taken", code: CodeEmailTaken, want: codes.AlreadyExists},
+		{name: "Shared_Link_Private", code: CodeSharedLinkPrivate, want: codes.FailedPrecondition},
+		{name: "Last_Workspace_Owner", code: CodeLastWorkspaceOwner, want: codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := GRPCStatus(New(http.StatusConflict, tt.code, "конфликт"))
+			assert.Equal(t, tt.want, st.Code())
+		})
+	}
+}
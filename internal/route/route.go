@@ -5,6 +5,7 @@ import (
 	"github.com/romanp1989/go-shortener/internal/auth"
 	"github.com/romanp1989/go-shortener/internal/handlers"
 	"github.com/romanp1989/go-shortener/internal/middlewares"
+	"github.com/romanp1989/go-shortener/internal/openapi"
 	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
 )
 
@@ -15,23 +16,68 @@ func New(app *shortener_service.ShortenerService, h handlers.Handlers, jwtServic
 	m := middlewares.Middleware{
 		Cfg:        app.Cfg,
 		JwtService: jwtService,
+		AppService: app,
 	}
 
 	r.Use(m.GzipMiddleware)
 	r.Use(m.WithLogging)
 
-	r.With(m.AuthMiddlewareSet).Post("/", h.Encode())
+	r.With(m.AuthMiddlewareSet, m.Idempotency).Post("/", h.Encode())
 	r.Get("/{id}", h.Decode())
+	r.Post("/{id}", h.Unlock())
+	r.Get("/{id}/qr", h.QRCode())
 	r.Get("/ping", h.PingDB())
 	r.Route("/api", func(r chi.Router) {
+		r.Get("/openapi.json", openapi.SpecHandler())
+		r.Get("/docs", openapi.DocsHandler())
+		r.Get("/docs/{file}", openapi.AssetHandler())
 		r.With(m.AuthMiddlewareRead).Get("/user/urls", h.GetURLs())
+		r.With(m.AuthMiddlewareRead).Get("/user/urls/search", h.SearchURLs())
+		r.With(m.AuthMiddlewareRead).Get("/user/urls/export", h.ExportURLs())
 		r.With(m.AuthMiddlewareRead).Delete("/user/urls", h.DeleteURLs())
+		r.With(m.AuthMiddlewareRead).Get("/user/settings", h.GetUserSettings())
+		r.With(m.AuthMiddlewareSet).Put("/user/settings", h.UpdateUserSettings())
+		r.With(m.AuthMiddlewareOptional).Post("/user/register", h.Register())
+		r.With(m.AuthMiddlewareOptional).Post("/user/login", h.Login())
+		r.Post("/user/logout", h.Logout())
+		r.With(m.AuthMiddlewareRead).Get("/user/account", h.GetAccount())
+		r.With(m.AuthMiddlewareRead).Post("/user/claim", h.ClaimURLs())
+		r.Route("/user/collections", func(r chi.Router) {
+			r.With(m.AuthMiddlewareRead).Get("/", h.ListCollections())
+			r.With(m.AuthMiddlewareSet).Post("/", h.CreateCollection())
+			r.With(m.AuthMiddlewareRead).Get("/{id}", h.GetCollection())
+			r.With(m.AuthMiddlewareRead).Patch("/{id}", h.UpdateCollection())
+			r.With(m.AuthMiddlewareRead).Delete("/{id}", h.DeleteCollection())
+			r.With(m.AuthMiddlewareRead).Get("/{id}/urls", h.GetCollectionURLs())
+			r.With(m.AuthMiddlewareRead).Post("/{id}/urls", h.AddCollectionURLs())
+			r.With(m.AuthMiddlewareRead).Delete("/{id}/urls", h.DeleteCollectionURLs())
+			r.With(m.AuthMiddlewareRead).Get("/{id}/export", h.ExportCollection())
+		})
+		r.Route("/user/workspaces", func(r chi.Router) {
+			r.With(m.AuthMiddlewareRead).Get("/", h.ListWorkspaces())
+			r.With(m.AuthMiddlewareSet).Post("/", h.CreateWorkspace())
+			r.With(m.AuthMiddlewareRead).Get("/{id}", h.GetWorkspace())
+			r.With(m.AuthMiddlewareRead).Patch("/{id}", h.UpdateWorkspace())
+			r.With(m.AuthMiddlewareRead).Delete("/{id}", h.DeleteWorkspace())
+			r.With(m.AuthMiddlewareRead).Get("/{id}/members", h.GetWorkspaceMembers())
+			r.With(m.AuthMiddlewareRead).Put("/{id}/members/{user_id}", h.SetWorkspaceMember())
+			r.With(m.AuthMiddlewareRead).Delete("/{id}/members/{user_id}", h.DeleteWorkspaceMember())
+		})
 		r.Route("/shorten", func(r chi.Router) {
-			r.With(m.AuthMiddlewareSet).Post("/", h.Shorten())
-			r.With(m.AuthMiddlewareSet).Post("/batch", h.SaveBatch())
+			r.With(m.AuthMiddlewareSet, m.Idempotency).Post("/", h.Shorten())
+			r.With(m.AuthMiddlewareSet, m.Idempotency).Post("/batch", h.SaveBatch())
+			r.With(m.AuthMiddlewareSet).Post("/import", h.Import())
 		})
 		r.With(m.ValidateSubnet).Get("/internal/stats", h.GetStats())
-
+		r.With(m.ValidateSubnet).Get("/internal/links/scheduled", h.GetScheduledLinks())
+		r.With(m.AuthMiddlewareOptional).Get("/links/{id}", h.GetLinkInfo())
+		r.Route("/v2", func(r chi.Router) {
+			r.With(m.AuthMiddlewareSet).Post("/links", h.CreateLink())
+			r.With(m.AuthMiddlewareRead).Get("/links", h.ListLinks())
+			r.With(m.AuthMiddlewareRead).Get("/links/{id}", h.GetLink())
+			r.With(m.AuthMiddlewareRead).Patch("/links/{id}", h.UpdateLink())
+			r.With(m.AuthMiddlewareRead).Delete("/links/{id}", h.DeleteLink())
+		})
 	})
 
 	return r
@@ -0,0 +1,173 @@
+package route
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/handlers"
+	"github.com/romanp1989/go-shortener/internal/openapi"
+	shortener_service "github.com/romanp1989/go-shortener/internal/shortener-service"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// specDocument part of OpenAPI document used in tests
+type specDocument struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+func newTestRouter() *chi.Mux {
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	appService := shortener_service.NewShortenerService(&storage.Storage{Storage: storage.NewCacheStorage()}, cfg)
+
+	return New(appService, handlers.New(appService), auth.NewJwtService("verycomplexsecretkey"))
+}
+
+// routeKey function returns "METHOD /path" key, chi trailing slash of sub-router root is removed
+func routeKey(method string, path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return strings.ToUpper(method) + " " + path
+}
+
+func TestNew_RoutesHaveOpenAPISpec(t *testing.T) {
+	var spec specDocument
+	require.NoError(t, json.Unmarshal(openapi.Spec, &spec))
+
+	specRoutes := make(map[string]bool)
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			specRoutes[routeKey(method, path)] = true
+		}
+	}
+
+	routes := make(map[string]bool)
+	err := chi.Walk(newTestRouter(), func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes[routeKey(method, route)] = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	var missing, stale []string
+	for route := range routes {
+		if !specRoutes[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range specRoutes {
+		if !routes[route] {
+			stale = append(stale, route)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+
+	assert.Empty(t, missing, "маршруты без описания в openapi.json")
+	assert.Empty(t, stale, "описания в openapi.json без маршрута")
+}
+
+func TestOpenAPISpec_RefsResolve(t *testing.T) {
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(openapi.Spec, &document))
+
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch value := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := value["$ref"].(string); ok {
+				assert.True(t, resolveRef(document, ref), "не найден %s", ref)
+			}
+			for _, child := range value {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(document)
+}
+
+// resolveRef function checks that local reference like #/components/schemas/Problem exists in document
+func resolveRef(document map[string]interface{}, ref string) bool {
+	if !strings.HasPrefix(ref, "#/") {
+		return false
+	}
+
+	var node interface{} = document
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if node, ok = object[part]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNew_OpenAPIEndpoints(t *testing.T) {
+	router := newTestRouter()
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+	}{
+		{
+			name:        "Spec",
+			path:        "/api/openapi.json",
+			contentType: "application/json",
+		},
+		{
+			name:        "Docs",
+			path:        "/api/docs",
+			contentType: "text/html; charset=utf-8",
+		},
+		{
+			name:        "Docs_script",
+			path:        "/api/docs/swagger-ui-bundle.js",
+			contentType: "text/javascript; charset=utf-8",
+		},
+		{
+			name:        "Docs_styles",
+			path:        "/api/docs/swagger-ui.css",
+			contentType: "text/css; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			assert.NotEmpty(t, w.Body.Bytes())
+		})
+	}
+
+	// страница просмотра не загружает файлы с CDN
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	for _, path := range []string{"/api/docs/LICENSE", "/api/docs/missing.js"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+}
@@ -12,7 +12,7 @@ func (s *ShortenerService) GetStats(ctx context.Context) (models.StorageStats, e
 	stats, err := s.storage.GetStats(ctx)
 	if err != nil {
 		logger.Log.Debug("Ошибка при получении статистики", zap.Error(err))
-		return models.StorageStats{}, nil
+		return models.StorageStats{}, err
 	}
 
 	return stats, nil
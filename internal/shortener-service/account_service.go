@@ -0,0 +1,344 @@
+package shortenerservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"go.uber.org/zap"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// sessionTTL lifetime of login session of account
+const sessionTTL = 30 * 24 * time.Hour
+
+// sessionIDLen length of random login session id in bytes
+const sessionIDLen = 32
+
+// defaultClaimTokenMaxAge max time after expiration of anonymous user token when its links can be claimed if it isn't configured
+const defaultClaimTokenMaxAge = 30 * 24 * time.Hour
+
+// anonymousCheckTTL time while user id checked to have no account is accepted without storage lookup
+const anonymousCheckTTL = time.Minute
+
+// emailLimit max length of email
+const emailLimit = 254
+
+// Ограничения длины пароля аккаунта в символах
+const (
+	passwordMinLength = 8
+	passwordMaxLength = 256
+)
+
+// ErrInvalidEmail email of account can't be parsed
+var ErrInvalidEmail = errors.New("некорректный email")
+
+// ErrWeakPassword password of account is too short or too long
+var ErrWeakPassword = errors.New("пароль должен содержать от 8 до 256 символов")
+
+// ErrEmailTaken email is registered by other account
+var ErrEmailTaken = errors.New("email уже зарегистрирован")
+
+// ErrInvalidLogin email isn't registered or password is wrong
+var ErrInvalidLogin = errors.New("неверный email или пароль")
+
+// ErrSessionExpired login session is expired or finished by logout
+var ErrSessionExpired = errors.New("сессия истекла или завершена")
+
+// ErrAccountRequired action is available to registered users only
+var ErrAccountRequired = errors.New("действие доступно только зарегистрированному пользователю")
+
+// ErrInvalidClaimToken token of anonymous user is invalid, expired too long ago or belongs to account
+var ErrInvalidClaimToken = errors.New("некорректный токен анонимного пользователя")
+
+// dummyPasswordHash hash checked for unknown email, so response time doesn't show whether email is registered
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := auth.HashPassword("dummy password")
+	return hash
+})
+
+// Register function for creating account with login session, account keeps user id of current anonymous user
+// with its links. Account gets new user id if current user is registered already
+func (s *ShortenerService) Register(ctx context.Context, userID *uuid.UUID, req models.AccountRequest) (models.AccountSession, error) {
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return models.AccountSession{}, err
+	}
+	if err = validatePassword(req.Password); err != nil {
+		return models.AccountSession{}, err
+	}
+
+	registered, err := s.storage.GetAccount(ctx, userID)
+	if err != nil {
+		return models.AccountSession{}, err
+	}
+	if userID == nil || registered != nil {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return models.AccountSession{}, err
+		}
+		userID = &id
+	}
+
+	account := models.Account{UserID: userID, Email: email, CreatedAt: time.Now().UTC()}
+	if account.PasswordHash, err = auth.HashPassword(req.Password); err != nil {
+		return models.AccountSession{}, err
+	}
+
+	if err = s.storage.SaveAccount(ctx, account); err != nil {
+		if errors.Is(err, storage.ErrConflict) {
+			return models.AccountSession{}, ErrEmailTaken
+		}
+		return models.AccountSession{}, err
+	}
+	s.forgetAnonymousUser(*userID)
+
+	return s.newSession(ctx, account, 0)
+}
+
+// Login function for creating login session of account, links of current anonymous user are merged into account if claim is set.
+// Wrong passwords are limited per email and per client
+func (s *ShortenerService) Login(ctx context.Context, userID *uuid.UUID, client string, req models.AccountRequest) (models.AccountSession, error) {
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return models.AccountSession{}, ErrInvalidLogin
+	}
+
+	emailKey, clientKey := "login/email/"+email, "login/client/"+client
+	if !s.passwordAttempt(emailKey, clientKey) {
+		return models.AccountSession{}, ErrTooManyPasswordAttempts
+	}
+
+	account, err := s.storage.GetAccountByEmail(ctx, email)
+	if err != nil {
+		return models.AccountSession{}, err
+	}
+
+	hash := dummyPasswordHash()
+	if account != nil {
+		hash = account.PasswordHash
+	}
+	ok, err := auth.VerifyPassword(hash, req.Password)
+	if err != nil {
+		return models.AccountSession{}, err
+	}
+	if account == nil || !ok {
+		return models.AccountSession{}, ErrInvalidLogin
+	}
+
+	// неверные попытки клиента не сбрасываются, чтобы вход в свой аккаунт не открывал перебор паролей чужих
+	s.forgetPasswordAttempts(emailKey)
+	s.releasePasswordAttempt(clientKey)
+
+	claimed := 0
+	if req.Claim {
+		// ссылки другого аккаунта не переносятся при входе
+		claimed, err = s.claimAnonymous(ctx, userID, account.UserID)
+		if err != nil && !errors.Is(err, ErrInvalidClaimToken) {
+			return models.AccountSession{}, err
+		}
+	}
+
+	return s.newSession(ctx, *account, claimed)
+}
+
+// Logout function for finishing login session of auth token, token of anonymous user is ignored
+func (s *ShortenerService) Logout(ctx context.Context, token string) error {
+	claims, err := s.jwt.ParseExpiredToken(token)
+	if err != nil || claims.SessionID == "" {
+		return nil
+	}
+
+	return s.storage.DeleteSession(ctx, claims.SessionID)
+}
+
+// CheckSession function checks that login session of auth token isn't expired or finished.
+// Token without session is accepted for anonymous user only, account keeps user id it's registered from.
+// Storage is checked for anonymous user once per anonymousCheckTTL
+func (s *ShortenerService) CheckSession(ctx context.Context, claims *auth.Claims) error {
+	if claims.SessionID == "" {
+		if claims.UserID == nil || s.knownAnonymousUser(*claims.UserID) {
+			return nil
+		}
+		account, err := s.storage.GetAccount(ctx, claims.UserID)
+		if err != nil {
+			return err
+		}
+		if account != nil {
+			return ErrSessionExpired
+		}
+		s.rememberAnonymousUser(*claims.UserID)
+		return nil
+	}
+
+	session, err := s.storage.GetSession(ctx, claims.SessionID)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || !sameUserID(session.UserID, claims.UserID) || !time.Now().Before(session.ExpiresAt) {
+		return ErrSessionExpired
+	}
+	return nil
+}
+
+// GetAccount function for getting account of registered user
+func (s *ShortenerService) GetAccount(ctx context.Context, userID *uuid.UUID) (models.Account, error) {
+	account, err := s.storage.GetAccount(ctx, userID)
+	if err != nil {
+		return models.Account{}, err
+	}
+	if account == nil {
+		return models.Account{}, ErrAccountRequired
+	}
+
+	account.PasswordHash = ""
+	return *account, nil
+}
+
+// Claim function for merging links and collections of anonymous user into account,
+// token is auth cookie of anonymous user, token expired no longer than ClaimTokenMaxAge ago is accepted
+func (s *ShortenerService) Claim(ctx context.Context, userID *uuid.UUID, token string) (int, error) {
+	account, err := s.storage.GetAccount(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if account == nil {
+		return 0, ErrAccountRequired
+	}
+
+	claims, err := s.jwt.ParseExpiredToken(token)
+	if err != nil || claims.SessionID != "" || claims.UserID == nil || claims.ExpiresAt == nil {
+		return 0, ErrInvalidClaimToken
+	}
+
+	maxAge := s.Cfg.ClaimTokenMaxAge
+	if maxAge <= 0 {
+		maxAge = defaultClaimTokenMaxAge
+	}
+	// давно утекший токен не должен открывать доступ к ссылкам анонимного пользователя
+	if time.Since(claims.ExpiresAt.Time) > maxAge {
+		return 0, ErrInvalidClaimToken
+	}
+
+	return s.claimAnonymous(ctx, claims.UserID, account.UserID)
+}
+
+// claimAnonymous function moves links and collections of anonymous user to account
+func (s *ShortenerService) claimAnonymous(ctx context.Context, from *uuid.UUID, to *uuid.UUID) (int, error) {
+	if from == nil || sameUserID(from, to) {
+		return 0, nil
+	}
+
+	registered, err := s.storage.GetAccount(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+	if registered != nil {
+		return 0, ErrInvalidClaimToken
+	}
+
+	return s.storage.ClaimUrls(ctx, from, to)
+}
+
+// newSession function creates login session of account with auth token
+func (s *ShortenerService) newSession(ctx context.Context, account models.Account, claimed int) (models.AccountSession, error) {
+	id := make([]byte, sessionIDLen)
+	if _, err := rand.Read(id); err != nil {
+		return models.AccountSession{}, err
+	}
+
+	session := models.Session{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		UserID:    account.UserID,
+		ExpiresAt: time.Now().UTC().Add(sessionTTL).Truncate(time.Second),
+	}
+	if err := s.storage.SaveSession(ctx, session); err != nil {
+		return models.AccountSession{}, err
+	}
+
+	token, err := s.jwt.CreateSessionToken(session.UserID, session.ID, session.ExpiresAt)
+	if err != nil {
+		return models.AccountSession{}, err
+	}
+
+	account.PasswordHash = ""
+	return models.AccountSession{Account: account, Token: token, ExpiresAt: session.ExpiresAt, Claimed: claimed}, nil
+}
+
+// normalizeEmail function trims and lowercases email and checks its format
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || len(email) > emailLimit {
+		return "", ErrInvalidEmail
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+// validatePassword function checks length of account password
+func validatePassword(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < passwordMinLength || length > passwordMaxLength {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+// knownAnonymousUser function checks that user id was checked to have no account less than anonymousCheckTTL ago
+func (s *ShortenerService) knownAnonymousUser(userID uuid.UUID) bool {
+	s.anonymousMu.Lock()
+	defer s.anonymousMu.Unlock()
+
+	checked, ok := s.anonymous[userID]
+	return ok && time.Since(checked) < anonymousCheckTTL
+}
+
+// rememberAnonymousUser function saves time of check that user id has no account
+func (s *ShortenerService) rememberAnonymousUser(userID uuid.UUID) {
+	s.anonymousMu.Lock()
+	defer s.anonymousMu.Unlock()
+
+	s.anonymous[userID] = time.Now()
+}
+
+// forgetAnonymousUser function removes check of user id registered in account,
+// so its tokens without session are refused at once
+func (s *ShortenerService) forgetAnonymousUser(userID uuid.UUID) {
+	s.anonymousMu.Lock()
+	defer s.anonymousMu.Unlock()
+
+	delete(s.anonymous, userID)
+}
+
+// pruneAnonymousUsers function removes expired checks of anonymous users
+func (s *ShortenerService) pruneAnonymousUsers() {
+	s.anonymousMu.Lock()
+	defer s.anonymousMu.Unlock()
+
+	for userID, checked := range s.anonymous {
+		if time.Since(checked) >= anonymousCheckTTL {
+			delete(s.anonymous, userID)
+		}
+	}
+}
+
+// pruneSessions function removes expired login sessions from storage
+func (s *ShortenerService) pruneSessions(ctx context.Context) {
+	if err := s.storage.DeleteExpiredSessions(ctx); err != nil {
+		logger.Log.Error("Ошибка удаления истекших сессий", zap.Error(err))
+	}
+}
@@ -0,0 +1,55 @@
+package shortenerservice
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/models/mocks"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestShortenerService_CheckSessionAnonymousCached(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+
+	mockCtrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(mockCtrl)
+	// аккаунт анонимного пользователя ищется в хранилище один раз за anonymousCheckTTL
+	mockStorage.EXPECT().GetAccount(gomock.Any(), &userID).Return(nil, nil).Times(1)
+
+	s := NewShortenerService(&storage.Storage{Storage: mockStorage}, &config.ConfigENV{})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.CheckSession(ctx, &auth.Claims{UserID: &userID}))
+	}
+}
+
+func TestShortenerService_CheckSessionAnonymousRegistered(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080", SecretKey: "verycomplexsecretkey"}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+
+	userID := uuid.Must(uuid.NewV4())
+	require.NoError(t, s.CheckSession(ctx, &auth.Claims{UserID: &userID}))
+
+	// после регистрации токен без сессии отклоняется сразу, без ожидания anonymousCheckTTL
+	_, err := s.Register(ctx, &userID, models.AccountRequest{Email: "user@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.CheckSession(ctx, &auth.Claims{UserID: &userID}), ErrSessionExpired)
+}
+
+func TestShortenerService_PruneSessions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(mockCtrl)
+	mockStorage.EXPECT().DeleteExpiredSessions(gomock.Any()).Return(nil).Times(1)
+
+	s := NewShortenerService(&storage.Storage{Storage: mockStorage}, &config.ConfigENV{})
+	s.pruneSessions(context.Background())
+}
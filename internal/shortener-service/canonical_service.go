@@ -0,0 +1,177 @@
+package shortenerservice
+
+import (
+	"fmt"
+	"golang.org/x/net/idna"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultPorts default ports of schemes, removed from canonical URL
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// trackingParams query parameters of ad and mail trackers, utm_ parameters are matched by prefix
+var trackingParams = map[string]bool{
+	"fbclid":    true,
+	"gclid":     true,
+	"dclid":     true,
+	"msclkid":   true,
+	"yclid":     true,
+	"ysclid":    true,
+	"mc_cid":    true,
+	"mc_eid":    true,
+	"_openstat": true,
+}
+
+// normalizeURL function validates original URL by URL policy and returns its canonical form if canonicalization is enabled
+func (s *ShortenerService) normalizeURL(rawURL string) (string, error) {
+	if _, err := url.ParseRequestURI(rawURL); err != nil {
+		return "", ErrInvalidURL
+	}
+	if err := s.checkURLPolicy(rawURL); err != nil {
+		return "", err
+	}
+	if !s.Cfg.CanonicalizeURLs {
+		return rawURL, nil
+	}
+	return canonicalURL(rawURL, s.Cfg.StripTrackingParams)
+}
+
+// canonicalURL function returns canonical form of URL: scheme and host are lowercased, default port is removed,
+// IDN host is converted to punycode, percent-encoding is normalized, empty query, fragment and root path are removed.
+// Tracking parameters are removed from query if stripTracking is set
+func canonicalURL(rawURL string, stripTracking bool) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+	// URL без хоста (mailto:, urn:) не нормализуется
+	if u.Opaque != "" || u.Host == "" {
+		return rawURL, nil
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host, err := canonicalHost(u.Hostname())
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+	if port := u.Port(); port != "" && port != defaultPorts[scheme] {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	var b strings.Builder
+	b.WriteString(scheme)
+	b.WriteString("://")
+	if u.User != nil {
+		b.WriteString(u.User.String())
+		b.WriteString("@")
+	}
+	b.WriteString(host)
+
+	if path := normalizePercent(u.EscapedPath()); path != "/" {
+		b.WriteString(path)
+	}
+	if query := canonicalQuery(u.RawQuery, stripTracking); query != "" {
+		b.WriteString("?")
+		b.WriteString(query)
+	}
+	if fragment := normalizePercent(u.EscapedFragment()); fragment != "" {
+		b.WriteString("#")
+		b.WriteString(fragment)
+	}
+
+	return b.String(), nil
+}
+
+// canonicalHost function lowercases host and converts IDN host to punycode, ASCII hosts aren't validated
+func canonicalHost(host string) (string, error) {
+	host = strings.ToLower(host)
+	for i := 0; i < len(host); i++ {
+		if host[i] >= utf8.RuneSelf {
+			return idna.Lookup.ToASCII(host)
+		}
+	}
+	return host, nil
+}
+
+// canonicalQuery function normalizes percent-encoding of query parameters keeping their order,
+// empty parameters and tracking parameters if stripTracking is set are removed
+func canonicalQuery(rawQuery string, stripTracking bool) string {
+	parts := make([]string, 0)
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		if stripTracking {
+			rawKey, _, _ := strings.Cut(part, "=")
+			if key, err := url.QueryUnescape(rawKey); err == nil && isTrackingParam(key) {
+				continue
+			}
+		}
+		parts = append(parts, normalizePercent(part))
+	}
+	return strings.Join(parts, "&")
+}
+
+// isTrackingParam function checks if query parameter is used by trackers only
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasPrefix(key, "utm_") || trackingParams[key]
+}
+
+// normalizePercent function decodes percent-encoded unreserved characters and uppercases hex digits of other escapes,
+// non-ASCII bytes and spaces are escaped, invalid escapes are kept as is
+func normalizePercent(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf || s[i] == ' ' {
+			fmt.Fprintf(&b, "%%%02X", s[i])
+			continue
+		}
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+// isUnreserved function checks if character is unreserved by RFC 3986 and doesn't need escaping
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isHex function checks if character is hex digit
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// unhex function returns value of hex digit
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
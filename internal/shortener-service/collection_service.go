@@ -0,0 +1,242 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// collectionNameLimit max length of collection name in runes
+const collectionNameLimit = 100
+
+// collectionDescriptionLimit max length of collection description in runes
+const collectionDescriptionLimit = 1000
+
+// ErrCollectionNotFound collection isn't found or belongs to other user
+var ErrCollectionNotFound = errors.New("коллекция не найдена")
+
+// ErrInvalidCollection collection name is empty or name or description is too long
+var ErrInvalidCollection = errors.New("некорректное название или описание коллекции")
+
+// CreateCollection function for creating user's collection
+func (s *ShortenerService) CreateCollection(ctx context.Context, userID *uuid.UUID, req models.CollectionRequest) (models.Collection, error) {
+	collection := models.Collection{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := validateCollection(collection); err != nil {
+		return models.Collection{}, err
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return models.Collection{}, err
+	}
+	collection.ID = id.String()
+
+	if err = s.storage.SaveCollection(ctx, collection); err != nil {
+		return models.Collection{}, err
+	}
+
+	collection.UserID = nil
+	return collection, nil
+}
+
+// ListCollections function for getting user's collections with count of their links
+func (s *ShortenerService) ListCollections(ctx context.Context, userID *uuid.UUID) ([]models.Collection, error) {
+	collections, err := s.storage.GetCollections(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.collectionCounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range collections {
+		collections[i].UserID = nil
+		collections[i].Links = counts[collections[i].ID]
+	}
+
+	return collections, nil
+}
+
+// GetCollection function for getting user's collection by ID with count of its links
+func (s *ShortenerService) GetCollection(ctx context.Context, userID *uuid.UUID, id string) (models.Collection, error) {
+	collection, err := s.ownCollection(ctx, userID, id)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	counts, err := s.collectionCounts(ctx, userID)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	collection.UserID = nil
+	collection.Links = counts[collection.ID]
+	return *collection, nil
+}
+
+// UpdateCollection function for renaming user's collection, omitted fields aren't changed
+func (s *ShortenerService) UpdateCollection(ctx context.Context, userID *uuid.UUID, id string, req models.CollectionUpdateRequest) (models.Collection, error) {
+	collection, err := s.ownCollection(ctx, userID, id)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	if req.Name != nil {
+		collection.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		collection.Description = strings.TrimSpace(*req.Description)
+	}
+	if err = validateCollection(*collection); err != nil {
+		return models.Collection{}, err
+	}
+
+	if err = s.storage.SaveCollection(ctx, *collection); err != nil {
+		return models.Collection{}, err
+	}
+
+	return s.GetCollection(ctx, userID, id)
+}
+
+// DeleteCollection function for deleting user's collection, its links are kept without collection
+func (s *ShortenerService) DeleteCollection(ctx context.Context, userID *uuid.UUID, id string) error {
+	if _, err := s.ownCollection(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return s.storage.DeleteCollection(ctx, id)
+}
+
+// CollectionLinks function for getting not deleted links of user's collection, newest links are first
+func (s *ShortenerService) CollectionLinks(ctx context.Context, userID *uuid.UUID, id string) ([]models.Link, error) {
+	if _, err := s.ownCollection(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return s.filterLinks(ctx, userID, func(link models.StorageURL) bool {
+		return link.CollectionID == id
+	})
+}
+
+// AddCollectionLinks function for moving user's links to collection, no link is moved if any of them isn't found
+func (s *ShortenerService) AddCollectionLinks(ctx context.Context, userID *uuid.UUID, id string, linkIDs []string) error {
+	if _, err := s.ownCollection(ctx, userID, id); err != nil {
+		return err
+	}
+
+	links := make([]models.StorageURL, 0, len(linkIDs))
+	for _, linkID := range linkIDs {
+		link, err := s.ownLink(ctx, userID, linkID)
+		if err != nil {
+			return err
+		}
+		// коллекции личные, редактор пространства не добавляет в них чужие ссылки
+		if !sameUserID(link.UserID, userID) {
+			return ErrWorkspaceForbidden
+		}
+		links = append(links, *link)
+	}
+
+	for _, link := range links {
+		if link.CollectionID == id {
+			continue
+		}
+
+		link.CollectionID = id
+		if err := s.storage.UpdateLink(ctx, link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteCollectionLinks function for deleting all not deleted links of user's collection, collection is kept
+func (s *ShortenerService) DeleteCollectionLinks(ctx context.Context, userID *uuid.UUID, id string) error {
+	if _, err := s.ownCollection(ctx, userID, id); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0)
+	err := s.storage.IterateUrlsByUser(ctx, userID, func(link models.StorageURL) error {
+		if link.CollectionID == id && !link.DeletedFlag {
+			ids = append(ids, link.ShortURL)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return s.storage.DeleteUrlsBatch(ctx, userID, ids)
+}
+
+// ExportCollection function calls fn for every link of user's collection with full short URL
+func (s *ShortenerService) ExportCollection(ctx context.Context, userID *uuid.UUID, id string, fn func(models.ExportURL) error) error {
+	if _, err := s.ownCollection(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return s.exportURLs(ctx, userID, func(link models.StorageURL) bool {
+		return link.CollectionID == id
+	}, fn)
+}
+
+// ownCollection function returns collection of user
+func (s *ShortenerService) ownCollection(ctx context.Context, userID *uuid.UUID, id string) (*models.Collection, error) {
+	if id == "" {
+		return nil, ErrCollectionNotFound
+	}
+
+	collection, err := s.storage.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// чужая коллекция не отличается от несуществующей
+	if collection == nil || collection.UserID == nil || userID == nil || *collection.UserID != *userID {
+		return nil, ErrCollectionNotFound
+	}
+
+	return collection, nil
+}
+
+// collectionCounts function returns count of not deleted user's links by collection id
+func (s *ShortenerService) collectionCounts(ctx context.Context, userID *uuid.UUID) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := s.storage.IterateUrlsByUser(ctx, userID, func(link models.StorageURL) error {
+		if link.CollectionID != "" && !link.DeletedFlag {
+			counts[link.CollectionID]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// validateCollection function checks collection name and description
+func validateCollection(collection models.Collection) error {
+	if collection.Name == "" || utf8.RuneCountInString(collection.Name) > collectionNameLimit {
+		return ErrInvalidCollection
+	}
+	if utf8.RuneCountInString(collection.Description) > collectionDescriptionLimit {
+		return ErrInvalidCollection
+	}
+	return nil
+}
@@ -4,21 +4,33 @@ import (
 	"context"
 	"github.com/gofrs/uuid"
 	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
 	"go.uber.org/zap"
 )
 
 type itemDelete struct {
-	urls   []string
-	userID *uuid.UUID
+	urls        []string
+	userID      *uuid.UUID
+	workspaceID string
 }
 
-// DeleteURLs function for delete urls
+// DeleteURLs function for delete user's urls and urls of workspaces where user is editor
 func (s *ShortenerService) DeleteURLs(userID *uuid.UUID, urls []string) {
 	go s.add(userID, urls)
 }
 
-// add function for add user to delete channel
+// add function for add user to delete channel, urls of workspaces are sent in separate batches
 func (s *ShortenerService) add(userID *uuid.UUID, urls []string) {
+	own, shared := s.splitWorkspaceURLs(context.Background(), userID, urls)
+
+	s.send(userID, "", own)
+	for workspaceID, workspaceURLs := range shared {
+		s.send(userID, workspaceID, workspaceURLs)
+	}
+}
+
+// send function splits urls into batches and sends them to delete channel
+func (s *ShortenerService) send(userID *uuid.UUID, workspaceID string, urls []string) {
 	for i := 0; i < len(urls); i += s.size {
 		endSlice := i + s.size
 		if endSlice > len(urls) {
@@ -26,10 +38,48 @@ func (s *ShortenerService) add(userID *uuid.UUID, urls []string) {
 		}
 
 		s.inChan <- itemDelete{
-			urls:   urls[i:endSlice],
-			userID: userID,
+			urls:        urls[i:endSlice],
+			userID:      userID,
+			workspaceID: workspaceID,
+		}
+	}
+}
+
+// splitWorkspaceURLs function separates urls of workspaces where user is editor from other urls
+func (s *ShortenerService) splitWorkspaceURLs(ctx context.Context, userID *uuid.UUID, urls []string) ([]string, map[string][]string) {
+	workspaces, err := s.storage.GetUserWorkspaces(ctx, userID)
+	if err != nil {
+		logger.Log.Error("Ошибка получения рабочих пространств пользователя", zap.Error(err))
+		return urls, nil
+	}
+
+	editable := make(map[string]bool)
+	for _, workspace := range workspaces {
+		if workspaceRoleRanks[workspace.Role] >= workspaceRoleRanks[models.WorkspaceRoleEditor] {
+			editable[workspace.ID] = true
 		}
 	}
+	// пользователь без рабочих пространств удаляет только свои ссылки
+	if len(editable) == 0 {
+		return urls, nil
+	}
+
+	own := make([]string, 0, len(urls))
+	shared := make(map[string][]string)
+	for _, shortURL := range urls {
+		link, err := s.storage.GetLink(ctx, shortURL)
+		if err != nil {
+			logger.Log.Error("Ошибка получения ссылки для удаления", zap.String("url", shortURL), zap.Error(err))
+			continue
+		}
+		if link != nil && editable[link.WorkspaceID] {
+			shared[link.WorkspaceID] = append(shared[link.WorkspaceID], shortURL)
+			continue
+		}
+		own = append(own, shortURL)
+	}
+
+	return own, shared
 }
 
 // process function starts the goroutine for delete user's urls
@@ -41,6 +91,13 @@ func (s *ShortenerService) process() {
 				return
 			}
 
+			if batch.workspaceID != "" {
+				if err := s.storage.DeleteWorkspaceUrlsBatch(context.Background(), batch.workspaceID, batch.urls); err != nil {
+					logger.Log.Error("Ошибка при удалении url рабочего пространства", zap.String("workspaceID", batch.workspaceID), zap.Error(err))
+				}
+				continue
+			}
+
 			if err := s.storage.DeleteUrlsBatch(context.Background(), batch.userID, batch.urls); err != nil {
 				logger.Log.Error("Ошибка при удалении url: %v, %v", zap.String("userID", batch.userID.String()), zap.Error(err))
 			}
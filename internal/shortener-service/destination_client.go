@@ -0,0 +1,115 @@
+package shortenerservice
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// destinationMaxRedirects max count of redirects followed by requests to link destinations
+const destinationMaxRedirects = 10
+
+// ErrPrivateDestination destination of link resolves to loopback, private, link-local or other special-purpose address
+var ErrPrivateDestination = errors.New("адрес ссылки указывает на внутреннюю сеть")
+
+// newDestinationClient function creates HTTP client for requests to link destinations.
+// Connections to internal addresses are refused after DNS resolution, so redirects and DNS rebinding are checked too
+func newDestinationClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: denyPrivateDestination,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// через прокси адрес назначения не проверяется при подключении
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= destinationMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", destinationMaxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("unsupported redirect scheme %q", req.URL.Scheme)
+			}
+			if addr, err := netip.ParseAddr(req.URL.Hostname()); err == nil && !publicAddr(addr) {
+				return ErrPrivateDestination
+			}
+			return nil
+		},
+	}
+}
+
+// denyPrivateDestination function is dialer control, it refuses connection to resolved internal address
+func denyPrivateDestination(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(addr) {
+		return ErrPrivateDestination
+	}
+	return nil
+}
+
+// specialPrefixes special-purpose address blocks of IANA registries that aren't globally reachable:
+// private, shared, loopback, link-local, documentation, benchmarking, reserved and multicast networks.
+// NAT64, 6to4 and Teredo prefixes are included as they embed IPv4 address of any network
+var specialPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+
+	netip.MustParsePrefix("::/96"),
+	netip.MustParsePrefix("::ffff:0:0/96"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("3fff::/20"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("fec0::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// publicAddr function checks that address isn't in special-purpose blocks, IPv4-mapped address is checked as IPv4
+func publicAddr(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range specialPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
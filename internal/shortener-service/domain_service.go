@@ -0,0 +1,191 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"net/url"
+	"strings"
+)
+
+// domainSeparator separator of short code and domain in key of link on additional domain
+const domainSeparator = "@"
+
+// ErrUnknownDomain domain of short link isn't configured
+var ErrUnknownDomain = errors.New("домен коротких ссылок не настроен")
+
+// shortDomains structure for configured domains of short links, additional domains are keyed by host
+type shortDomains struct {
+	defaultHost string
+	baseURLs    map[string]string
+	hosts       []string
+}
+
+// parseDomains function reads default base URL and comma-separated base URLs of additional domains
+func parseDomains(baseURL string, domains string) shortDomains {
+	parsed := shortDomains{baseURLs: make(map[string]string)}
+	if u, err := url.Parse(baseURL); err == nil {
+		parsed.defaultHost = strings.ToLower(u.Host)
+	}
+	parsed.hosts = append(parsed.hosts, parsed.defaultHost)
+
+	for _, domain := range strings.Split(domains, ",") {
+		domain = strings.TrimSuffix(strings.TrimSpace(domain), "/")
+		if domain == "" {
+			continue
+		}
+		if !strings.Contains(domain, "://") {
+			domain = "https://" + domain
+		}
+		u, err := url.Parse(domain)
+		if err != nil || u.Host == "" {
+			continue
+		}
+
+		host := strings.ToLower(u.Host)
+		if _, ok := parsed.baseURLs[host]; ok || host == parsed.defaultHost {
+			continue
+		}
+		parsed.baseURLs[host] = u.Scheme + "://" + host
+		parsed.hosts = append(parsed.hosts, host)
+	}
+
+	return parsed
+}
+
+// linkKey function returns storage key of short code on domain, links of default domain are keyed by code only
+func linkKey(domain string, code string) string {
+	if domain == "" {
+		return code
+	}
+	return code + domainSeparator + domain
+}
+
+// LinkCode function returns short code of link key without domain
+func LinkCode(key string) string {
+	code, _, _ := strings.Cut(key, domainSeparator)
+	return code
+}
+
+// linkDomain function returns domain of link key, empty string is returned for default domain
+func linkDomain(key string) string {
+	_, domain, _ := strings.Cut(key, domainSeparator)
+	return domain
+}
+
+// HostLinkKey function returns key of link requested by short code on host.
+// Unknown hosts are served as default domain, domain can't be set in code
+func (s *ShortenerService) HostLinkKey(host string, code string) string {
+	if strings.Contains(code, domainSeparator) {
+		// ключ с доменом не должен открываться на другом домене, такая ссылка не будет найдена
+		return linkKey(strings.ToLower(host), code)
+	}
+
+	host = strings.ToLower(host)
+	if _, ok := s.domains.baseURLs[host]; ok {
+		return linkKey(host, code)
+	}
+	return code
+}
+
+// shortLink function returns full short URL of link key with base URL of its domain
+func (s *ShortenerService) shortLink(key string) string {
+	code, domain := LinkCode(key), linkDomain(key)
+	if baseURL, ok := s.domains.baseURLs[domain]; ok {
+		return fmt.Sprintf("%s/%s", baseURL, code)
+	}
+	return fmt.Sprintf("%s/%s", s.Cfg.BaseURL, code)
+}
+
+// domainHost function returns host of link domain
+func (s *ShortenerService) domainHost(domain string) string {
+	if domain == "" {
+		return s.domains.defaultHost
+	}
+	return domain
+}
+
+// isShortDomain function checks if URL points to default or additional domain of short links
+func (s *ShortenerService) isShortDomain(u *url.URL) bool {
+	if base, err := url.Parse(s.Cfg.BaseURL); err == nil && sameOrigin(base, u) {
+		return true
+	}
+	for _, baseURL := range s.domains.baseURLs {
+		if base, err := url.Parse(baseURL); err == nil && sameOrigin(base, u) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDomain function converts host or base URL of configured domain to domain of link key
+func (s *ShortenerService) normalizeDomain(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "/"))
+	if _, host, ok := strings.Cut(domain, "://"); ok {
+		domain = host
+	}
+
+	if domain == "" || domain == s.domains.defaultHost {
+		return "", nil
+	}
+	if _, ok := s.domains.baseURLs[domain]; !ok {
+		return "", ErrUnknownDomain
+	}
+	return domain, nil
+}
+
+// newLinkDomain function returns domain of new link, requested domain is used if set, otherwise user's default domain
+func (s *ShortenerService) newLinkDomain(ctx context.Context, userID *uuid.UUID, requested string) (string, error) {
+	if requested != "" {
+		return s.normalizeDomain(requested)
+	}
+	// без дополнительных доменов настройки пользователя не читаются
+	if len(s.domains.baseURLs) == 0 || userID == nil {
+		return "", nil
+	}
+
+	settings, err := s.storage.GetUserSettings(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if settings == nil {
+		return "", nil
+	}
+	// домен мог быть удален из настроек сервера
+	if _, ok := s.domains.baseURLs[settings.Domain]; !ok {
+		return "", nil
+	}
+	return settings.Domain, nil
+}
+
+// GetUserSettings function returns user's default domain with list of configured domains
+func (s *ShortenerService) GetUserSettings(ctx context.Context, userID *uuid.UUID) (models.UserSettings, error) {
+	domain, err := s.newLinkDomain(ctx, userID, "")
+	if err != nil {
+		return models.UserSettings{}, err
+	}
+
+	return models.UserSettings{
+		Domain:  s.domainHost(domain),
+		Domains: s.domains.hosts,
+	}, nil
+}
+
+// SaveUserSettings function saves user's default domain of new links
+func (s *ShortenerService) SaveUserSettings(ctx context.Context, userID *uuid.UUID, settings models.UserSettings) (models.UserSettings, error) {
+	domain, err := s.normalizeDomain(settings.Domain)
+	if err != nil {
+		return models.UserSettings{}, err
+	}
+
+	if err = s.storage.SaveUserSettings(ctx, models.UserSettings{UserID: userID, Domain: domain}); err != nil {
+		return models.UserSettings{}, err
+	}
+
+	return models.UserSettings{
+		Domain:  s.domainHost(domain),
+		Domains: s.domains.hosts,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package shortenerservice
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_parseDomains(t *testing.T) {
+	got := parseDomains("http://localhost:8080", " https://Go.Brand.ru/, b.brand2:8081,http://localhost:8080,,https://go.brand.ru")
+
+	assert.Equal(t, "localhost:8080", got.defaultHost)
+	assert.Equal(t, []string{"localhost:8080", "go.brand.ru", "b.brand2:8081"}, got.hosts)
+	assert.Equal(t, map[string]string{
+		"go.brand.ru":   "https://go.brand.ru",
+		"b.brand2:8081": "https://b.brand2:8081",
+	}, got.baseURLs)
+}
+
+func TestShortenerService_Domains(t *testing.T) {
+	cfg := &config.ConfigENV{
+		BaseURL: "http://localhost:8080",
+		Domains: "https://go.brand.ru,http://b.brand2:8081",
+	}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+
+	settings, err := s.GetUserSettings(ctx, &userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserSettings{Domain: "localhost:8080", Domains: []string{"localhost:8080", "go.brand.ru", "b.brand2:8081"}}, settings)
+
+	shortURL, err := s.Shorten(ctx, "https://ya.ru", &userID)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/6YGS4ZUF", shortURL)
+
+	// тот же URL на другом домене - отдельная ссылка с тем же кодом
+	settings, err = s.SaveUserSettings(ctx, &userID, models.UserSettings{Domain: "https://GO.brand.ru/"})
+	require.NoError(t, err)
+	assert.Equal(t, "go.brand.ru", settings.Domain)
+
+	shortURL, err = s.Shorten(ctx, "https://ya.ru", &userID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.brand.ru/6YGS4ZUF", shortURL)
+
+	link, err := s.CreateLink(ctx, &userID, models.LinkCreateRequest{OriginalURL: "https://dzen.ru", Domain: "b.brand2:8081"})
+	require.NoError(t, err)
+	assert.Equal(t, "x+5vpM8W@b.brand2:8081", link.ID)
+	assert.Equal(t, "http://b.brand2:8081/x+5vpM8W", link.ShortURL)
+	assert.Equal(t, "b.brand2:8081", link.Domain)
+
+	_, err = s.CreateLink(ctx, &userID, models.LinkCreateRequest{OriginalURL: "https://mail.ru", Domain: "unknown.ru"})
+	assert.ErrorIs(t, err, ErrUnknownDomain)
+	_, err = s.SaveUserSettings(ctx, &userID, models.UserSettings{Domain: "unknown.ru"})
+	assert.ErrorIs(t, err, ErrUnknownDomain)
+
+	_, err = s.Shorten(ctx, "https://go.brand.ru/abc", &userID)
+	var policyErr *URLPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, PolicyReasonSelf, policyErr.Reason)
+
+	tests := []struct {
+		host string
+		code string
+		want string
+	}{
+		{host: "localhost:8080", code: "6YGS4ZUF", want: "https://ya.ru"},
+		{host: "GO.BRAND.RU", code: "6YGS4ZUF", want: "https://ya.ru"},
+		{host: "b.brand2:8081", code: "x+5vpM8W", want: "https://dzen.ru"},
+		{host: "localhost:8080", code: "x+5vpM8W", want: ""},
+		{host: "go.brand.ru", code: "x+5vpM8W", want: ""},
+		{host: "unknown.ru", code: "6YGS4ZUF", want: "https://ya.ru"},
+		{host: "localhost:8080", code: "x+5vpM8W@b.brand2:8081", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host+"/"+tt.code, func(t *testing.T) {
+			got, err := storageURLs.GetURL(s.HostLinkKey(tt.host, tt.code))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	urls, err := s.GetURLs(ctx, &userID)
+	require.NoError(t, err)
+	got := make([]string, 0, len(urls))
+	for _, u := range urls {
+		got = append(got, u.ShortURL)
+	}
+	assert.ElementsMatch(t, []string{"http://localhost:8080/6YGS4ZUF", "https://go.brand.ru/6YGS4ZUF", "http://b.brand2:8081/x+5vpM8W"}, got)
+}
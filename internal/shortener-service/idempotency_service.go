@@ -0,0 +1,102 @@
+package shortenerservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"time"
+)
+
+// defaultIdempotencyTTL window of saved responses if it isn't configured
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKeyLength max length of idempotency key
+const maxIdempotencyKeyLength = 255
+
+// ErrInvalidIdempotencyKey idempotency key is too long
+var ErrInvalidIdempotencyKey = errors.New("некорректный ключ идемпотентности")
+
+// ErrIdempotencyKeyReused idempotency key is reused with different request
+var ErrIdempotencyKeyReused = errors.New("ключ идемпотентности уже использован с другим запросом")
+
+// ErrIdempotencyKeyInProgress request with the same idempotency key isn't finished yet
+var ErrIdempotencyKeyInProgress = errors.New("запрос с этим ключом идемпотентности еще выполняется")
+
+// IdempotencyHash function returns hash of request parts for idempotency key check
+func IdempotencyHash(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		_, _ = h.Write(part)
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LookupIdempotent function returns saved response for user and idempotency key.
+// If there is no response the key is locked until SaveIdempotent or ReleaseIdempotent is called
+func (s *ShortenerService) LookupIdempotent(ctx context.Context, userID *uuid.UUID, key string, requestHash string) (*models.IdempotencyRecord, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	record, err := s.savedIdempotent(ctx, userID, key, requestHash)
+	if record != nil || err != nil {
+		return record, err
+	}
+
+	lockKey := idempotencyLockKey(userID, key)
+	if _, loaded := s.inflight.LoadOrStore(lockKey, struct{}{}); loaded {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	// ответ мог быть сохранен другим запросом между чтением и блокировкой ключа
+	record, err = s.savedIdempotent(ctx, userID, key, requestHash)
+	if record != nil || err != nil {
+		s.inflight.Delete(lockKey)
+		return record, err
+	}
+
+	return nil, nil
+}
+
+// savedIdempotent function returns saved response for idempotency key if it's saved for the same request
+func (s *ShortenerService) savedIdempotent(ctx context.Context, userID *uuid.UUID, key string, requestHash string) (*models.IdempotencyRecord, error) {
+	record, err := s.storage.GetIdempotencyRecord(ctx, userID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if record != nil && record.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	return record, nil
+}
+
+// SaveIdempotent function saves response for the idempotency key window and unlocks the key
+func (s *ShortenerService) SaveIdempotent(ctx context.Context, record models.IdempotencyRecord) error {
+	defer s.ReleaseIdempotent(record.UserID, record.Key)
+
+	ttl := s.Cfg.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	record.ExpiresAt = time.Now().Add(ttl).UTC()
+
+	return s.storage.SaveIdempotencyRecord(ctx, record)
+}
+
+// ReleaseIdempotent function unlocks idempotency key without saving response
+func (s *ShortenerService) ReleaseIdempotent(userID *uuid.UUID, key string) {
+	s.inflight.Delete(idempotencyLockKey(userID, key))
+}
+
+// idempotencyLockKey function returns key of in-flight requests map
+func idempotencyLockKey(userID *uuid.UUID, key string) string {
+	if userID == nil {
+		return "/" + key
+	}
+	return userID.String() + "/" + key
+}
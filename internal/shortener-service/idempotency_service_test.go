@@ -0,0 +1,39 @@
+package shortenerservice
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/models/mocks"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestShortenerService_LookupIdempotentSavedBeforeLock(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+	saved := &models.IdempotencyRecord{UserID: &userID, Key: "key", RequestHash: "hash", StatusCode: 201}
+
+	mockCtrl := gomock.NewController(t)
+	mockStorage := mocks.NewMockStorage(mockCtrl)
+	// ответ сохраняется другим запросом между первым чтением и блокировкой ключа
+	first := mockStorage.EXPECT().GetIdempotencyRecord(gomock.Any(), &userID, "key").Return(nil, nil)
+	mockStorage.EXPECT().GetIdempotencyRecord(gomock.Any(), &userID, "key").After(first).Return(saved, nil).AnyTimes()
+
+	s := NewShortenerService(&storage.Storage{Storage: mockStorage}, &config.ConfigENV{})
+
+	record, err := s.LookupIdempotent(ctx, &userID, "key", "hash")
+	require.NoError(t, err)
+	assert.Equal(t, saved, record)
+
+	// ключ не остается заблокированным
+	_, locked := s.inflight.Load(idempotencyLockKey(&userID, "key"))
+	assert.False(t, locked)
+
+	_, err = s.LookupIdempotent(ctx, &userID, "key", "other")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+}
@@ -0,0 +1,91 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"strings"
+)
+
+// ImportURLs function for saving a chunk of imported rows with one batch request to storage.
+// Invalid rows don't break the chunk and are returned with error, every row gets status like in SaveBatch
+func (s *ShortenerService) ImportURLs(ctx context.Context, rows []models.ImportRow, userID *uuid.UUID) ([]models.ImportResult, error) {
+	results := make([]models.ImportResult, len(rows))
+	batch := make([]models.StorageURL, 0, len(rows))
+	positions := make(map[string][]int, len(rows))
+
+	domain, err := s.newLinkDomain(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		originalURL := strings.TrimSpace(row.OriginalURL)
+		results[i] = models.ImportResult{
+			Line:          row.Line,
+			CorrelationID: row.CorrelationID,
+			OriginalURL:   originalURL,
+			Error:         row.Error,
+		}
+		if row.Error != "" {
+			results[i].Status = models.BatchStatusInvalid
+			continue
+		}
+
+		originalURL, err := s.normalizeURL(originalURL)
+		if err != nil {
+			results[i].Status, results[i].Error = models.BatchStatusInvalid, err.Error()
+			continue
+		}
+
+		// повторы URL в чанке получают результат первого вхождения
+		if first, ok := positions[originalURL]; ok {
+			results[i].Status, results[i].ShortURL = results[first[0]].Status, results[first[0]].ShortURL
+			positions[originalURL] = append(first, i)
+			continue
+		}
+
+		hashID := linkKey(domain, s.ShortURL(originalURL))
+		savedURL, err := s.storage.GetURL(hashID)
+		if err != nil {
+			var errURLDeleted *storage.AlreadyDeleted
+			if errors.As(err, &errURLDeleted) {
+				results[i].Status, results[i].Error = models.BatchStatusInvalid, "URL удален"
+				continue
+			}
+			return nil, err
+		}
+		positions[originalURL] = []int{i}
+
+		if savedURL != "" {
+			results[i].Status, results[i].ShortURL = models.BatchStatusExisting, s.shortLink(hashID)
+			continue
+		}
+
+		results[i].Status = models.BatchStatusCreated
+		batch = append(batch, models.StorageURL{
+			OriginalURL: originalURL,
+			ShortURL:    hashID,
+		})
+	}
+
+	if len(batch) == 0 {
+		return results, nil
+	}
+
+	shortURLs, err := s.storage.SaveBatchURL(ctx, batch, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, shortURL := range shortURLs {
+		for _, position := range positions[batch[i].OriginalURL] {
+			results[position].ShortURL = s.shortLink(shortURL)
+		}
+		s.enqueuePageFetch(shortURL, batch[i].OriginalURL)
+	}
+
+	return results, nil
+}
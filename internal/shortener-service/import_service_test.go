@@ -0,0 +1,43 @@
+package shortenerservice
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestShortenerService_ImportURLs(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080", FetchPageMetadata: true, PageFetchWorkers: 1}
+	s := NewShortenerService(&storage.Storage{Storage: storage.NewCacheStorage()}, cfg)
+
+	_, err := s.ImportURLs(ctx, []models.ImportRow{{Line: 1, OriginalURL: "https://ya.ru"}}, &userID)
+	require.NoError(t, err)
+	require.Len(t, s.pageQueue, 1)
+	<-s.pageQueue
+
+	results, err := s.ImportURLs(ctx, []models.ImportRow{
+		{Line: 1, OriginalURL: "https://ya.ru"},
+		{Line: 2, OriginalURL: "https://dzen.ru"},
+		{Line: 3, OriginalURL: "https://dzen.ru"},
+		{Line: 4, OriginalURL: "not url"},
+	}, &userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.ImportResult{
+		{Line: 1, OriginalURL: "https://ya.ru", ShortURL: "http://localhost:8080/6YGS4ZUF", Status: models.BatchStatusExisting},
+		{Line: 2, OriginalURL: "https://dzen.ru", ShortURL: "http://localhost:8080/x+5vpM8W", Status: models.BatchStatusCreated},
+		{Line: 3, OriginalURL: "https://dzen.ru", ShortURL: "http://localhost:8080/x+5vpM8W", Status: models.BatchStatusCreated},
+		{Line: 4, OriginalURL: "not url", Status: models.BatchStatusInvalid, Error: "некорректный URL"},
+	}, results)
+
+	// страница загружается только для созданной ссылки
+	require.Len(t, s.pageQueue, 1)
+	assert.Equal(t, pageFetch{shortURL: "x+5vpM8W", destination: "https://dzen.ru"}, <-s.pageQueue)
+}
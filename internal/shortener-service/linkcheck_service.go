@@ -0,0 +1,190 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Параметры проверки адресов ссылок
+const (
+	// linkCheckBatch max count of links checked in one round
+	linkCheckBatch = 500
+	// linkCheckTimeout timeout of one destination request
+	linkCheckTimeout = 10 * time.Second
+	// linkCheckBodyLimit max size of GET response body read by checker
+	linkCheckBodyLimit = 64 << 10
+	// linkCheckUserAgent User-Agent of checker requests
+	linkCheckUserAgent = "go-shortener-link-checker/1.0"
+)
+
+// LinkStatusBroken status filter of user's URLs with broken destination
+const LinkStatusBroken = "broken"
+
+// ErrInvalidStatusFilter status filter of user's URLs isn't supported
+var ErrInvalidStatusFilter = errors.New("неизвестный фильтр статуса ссылок")
+
+// IsBrokenLink function checks if destination of checked link is unreachable, not found or returns server error.
+// Other client errors like 403 and 429 are often returned to bots and aren't considered broken
+func IsBrokenLink(link models.StorageURL) bool {
+	if link.LastCheckedAt == nil {
+		return false
+	}
+	return link.LastStatus == 0 || link.LastStatus == http.StatusNotFound || link.LastStatus == http.StatusGone ||
+		link.LastStatus >= http.StatusInternalServerError
+}
+
+// GetURLsByStatus function returns not deleted user's URLs filtered by destination status with last check result
+func (s *ShortenerService) GetURLsByStatus(ctx context.Context, userID *uuid.UUID, status string) ([]models.StorageURL, error) {
+	if status != LinkStatusBroken {
+		return nil, ErrInvalidStatusFilter
+	}
+
+	urls := make([]models.StorageURL, 0)
+	err := s.storage.IterateUrlsByUser(ctx, userID, func(link models.StorageURL) error {
+		if link.DeletedFlag || !IsBrokenLink(link) {
+			return nil
+		}
+		urls = append(urls, models.StorageURL{
+			ShortURL:      s.shortLink(link.ShortURL),
+			OriginalURL:   link.OriginalURL,
+			Title:         link.Title,
+			Notes:         link.Notes,
+			Tags:          link.Tags,
+			CreatedAt:     link.CreatedAt,
+			LastStatus:    link.LastStatus,
+			LastCheckedAt: link.LastCheckedAt,
+			Page:          link.Page,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// CheckLinks function checks destinations of links not checked during check interval and saves their statuses.
+// Hosts are checked concurrently up to configured limit, requests to one host are sent one by one with delay
+func (s *ShortenerService) CheckLinks(ctx context.Context) error {
+	links, err := s.storage.GetLinksForCheck(ctx, time.Now().Add(-s.Cfg.LinkCheckInterval), linkCheckBatch)
+	if err != nil {
+		return err
+	}
+
+	hosts := make(map[string][]models.StorageURL)
+	for _, link := range links {
+		host := link.OriginalURL
+		if u, err := url.Parse(link.OriginalURL); err == nil {
+			host = strings.ToLower(u.Host)
+		}
+		hosts[host] = append(hosts[host], link)
+	}
+
+	concurrency := s.Cfg.LinkCheckConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for _, hostLinks := range hosts {
+		wg.Add(1)
+		go func(hostLinks []models.StorageURL) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			for i, link := range hostLinks {
+				if i > 0 && !sleepContext(ctx, s.Cfg.LinkCheckHostDelay) {
+					return
+				}
+
+				status := s.checkDestination(ctx, link.OriginalURL)
+				if err := s.storage.SaveLinkCheck(ctx, link.ShortURL, status, time.Now()); err != nil {
+					logger.Log.Error("Ошибка сохранения проверки ссылки", zap.String("url", link.ShortURL), zap.Error(err))
+				}
+			}
+		}(hostLinks)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// checkDestination function requests destination with HEAD and with GET if HEAD isn't supported,
+// zero status is returned if destination is unreachable
+func (s *ShortenerService) checkDestination(ctx context.Context, destination string) int {
+	status := s.requestDestination(ctx, http.MethodHead, destination)
+	if status == 0 || status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden {
+		// часть серверов не поддерживает HEAD или отвечает на него иначе, чем на GET
+		status = s.requestDestination(ctx, http.MethodGet, destination)
+	}
+	return status
+}
+
+// requestDestination function sends request to destination and returns response status, redirects are followed
+func (s *ShortenerService) requestDestination(ctx context.Context, method string, destination string) int {
+	ctx, cancel := context.WithTimeout(ctx, linkCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, destination, nil)
+	if err != nil {
+		return 0
+	}
+	req.Header.Set("User-Agent", linkCheckUserAgent)
+
+	resp, err := s.checkClient.Do(req)
+	if err != nil {
+		logger.Log.Debug("Адрес ссылки недоступен", zap.String("url", destination), zap.Error(err))
+		return 0
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, linkCheckBodyLimit))
+
+	return resp.StatusCode
+}
+
+// runLinkChecker function checks destinations of links by timer until ctx is done
+func (s *ShortenerService) runLinkChecker(ctx context.Context) {
+	ticker := time.NewTicker(s.Cfg.LinkCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.CheckLinks(ctx); err != nil && ctx.Err() == nil {
+				logger.Log.Error("Ошибка проверки адресов ссылок", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sleepContext function waits for duration, false is returned if context is done earlier
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,186 @@
+package shortenerservice
+
+import (
+	"context"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShortenerService_CheckLinks(t *testing.T) {
+	var inflight, maxInflight atomic.Int32
+	var mu sync.Mutex
+	requests := make(map[string][]time.Time)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			m := maxInflight.Load()
+			if n <= m || maxInflight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		mu.Lock()
+		requests[r.Host] = append(requests[r.Host], time.Now())
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		case "/error":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}
+	first := httptest.NewServer(http.HandlerFunc(handler))
+	defer first.Close()
+	second := httptest.NewServer(http.HandlerFunc(handler))
+	defer second.Close()
+	closed := httptest.NewServer(http.HandlerFunc(handler))
+	closed.Close()
+
+	cfg := &config.ConfigENV{
+		BaseURL:              "http://localhost:8080",
+		LinkCheckConcurrency: 1,
+		LinkCheckHostDelay:   30 * time.Millisecond,
+	}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+	// тестовые серверы слушают loopback, к которому клиент сервиса не подключается
+	s.checkClient = &http.Client{Timeout: linkCheckTimeout}
+
+	want := map[string]int{
+		first.URL + "/ok":         http.StatusOK,
+		first.URL + "/missing":    http.StatusNotFound,
+		first.URL + "/get-only":   http.StatusOK,
+		second.URL + "/error":     http.StatusBadGateway,
+		second.URL + "/forbidden": http.StatusForbidden,
+		closed.URL + "/ok":        0,
+	}
+	ids := make(map[string]string, len(want))
+	for destination := range want {
+		_, err := s.Shorten(context.Background(), destination, nil)
+		require.NoError(t, err)
+		ids[destination] = s.ShortURL(destination)
+	}
+
+	require.NoError(t, s.CheckLinks(context.Background()))
+
+	for destination, status := range want {
+		link, err := storageURLs.GetLink(context.Background(), ids[destination])
+		require.NoError(t, err)
+		require.NotNil(t, link.LastCheckedAt, destination)
+		assert.Equal(t, status, link.LastStatus, destination)
+	}
+	assert.Equal(t, int32(1), maxInflight.Load())
+
+	mu.Lock()
+	for host, times := range requests {
+		for i := 1; i < len(times); i++ {
+			assert.GreaterOrEqual(t, times[i].Sub(times[i-1]), 10*time.Millisecond, host)
+		}
+	}
+	count := len(requests[first.Listener.Addr().String()])
+	mu.Unlock()
+	// HEAD для трех ссылок и GET для ссылки без поддержки HEAD
+	assert.Equal(t, 4, count)
+
+	// ссылки, проверенные в течение интервала, повторно не проверяются
+	cfg.LinkCheckInterval = time.Hour
+	require.NoError(t, s.CheckLinks(context.Background()))
+	mu.Lock()
+	assert.Equal(t, count, len(requests[first.Listener.Addr().String()]))
+	mu.Unlock()
+
+	broken := 0
+	for destination := range want {
+		link, err := storageURLs.GetLink(context.Background(), ids[destination])
+		require.NoError(t, err)
+		if IsBrokenLink(*link) {
+			broken++
+		}
+	}
+	// 404, 502 и недоступный адрес
+	assert.Equal(t, 3, broken)
+	assert.False(t, IsBrokenLink(models.StorageURL{}))
+}
+
+func TestShortenerService_CheckLinksPrivateDestination(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080", LinkCheckConcurrency: 1}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+
+	_, err := s.Shorten(context.Background(), server.URL+"/internal", nil)
+	require.NoError(t, err)
+	require.NoError(t, s.CheckLinks(context.Background()))
+
+	// внутренний адрес не отличается от недоступного
+	link, err := storageURLs.GetLink(context.Background(), s.ShortURL(server.URL+"/internal"))
+	require.NoError(t, err)
+	assert.Zero(t, link.LastStatus)
+	assert.Zero(t, requests.Load())
+}
+
+func TestShortenerService_StartLinkChecker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080", LinkCheckConcurrency: 1, LinkCheckInterval: 10 * time.Millisecond}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+	s.checkClient = &http.Client{Timeout: linkCheckTimeout}
+
+	_, err := s.Shorten(context.Background(), server.URL+"/ok", nil)
+	require.NoError(t, err)
+
+	// без запуска фоновых задач адреса не проверяются
+	time.Sleep(30 * time.Millisecond)
+	assert.Zero(t, requests.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	require.Eventually(t, func() bool { return requests.Load() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		s.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("workers aren't stopped")
+	}
+}
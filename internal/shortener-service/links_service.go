@@ -0,0 +1,432 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"sort"
+	"time"
+)
+
+// defaultLinksLimit count of links in list page if limit isn't set
+const defaultLinksLimit = 50
+
+// maxLinksLimit max count of links in list page
+const maxLinksLimit = 1000
+
+// ErrLinkNotFound link isn't found or belongs to other user
+var ErrLinkNotFound = errors.New("ссылка не найдена")
+
+// ErrInvalidPagination limit or offset of links list is out of range
+var ErrInvalidPagination = errors.New("некорректные параметры limit или offset")
+
+// ErrInvalidMaxClicks clicks limit of link is negative
+var ErrInvalidMaxClicks = errors.New("некорректный лимит переходов max_clicks")
+
+// ErrSharedLinkPrivate link with code derived from original URL can't get clicks limit or password,
+// the same code is returned to other users shortening the same URL
+var ErrSharedLinkPrivate = errors.New("ссылке с общим кодом нельзя задать пароль или лимит переходов, создайте новую ссылку")
+
+// ErrPrivateLinkPublic link with random code can't drop both clicks limit and password,
+// public link of URL must have code derived from it
+var ErrPrivateLinkPublic = errors.New("ссылке со случайным кодом нельзя снять и пароль, и лимит переходов, создайте новую ссылку")
+
+// CreateLink function for creating link with metadata, existing link is returned with conflict error
+func (s *ShortenerService) CreateLink(ctx context.Context, userID *uuid.UUID, req models.LinkCreateRequest) (models.Link, error) {
+	if req.MaxClicks < 0 {
+		return models.Link{}, ErrInvalidMaxClicks
+	}
+
+	originalURL, err := s.normalizeURL(req.OriginalURL)
+	if err != nil {
+		return models.Link{}, err
+	}
+	domain, err := s.newLinkDomain(ctx, userID, req.Domain)
+	if err != nil {
+		return models.Link{}, err
+	}
+	if req.CollectionID != "" {
+		if _, err = s.ownCollection(ctx, userID, req.CollectionID); err != nil {
+			return models.Link{}, err
+		}
+	}
+	if req.WorkspaceID != "" {
+		if _, err = s.requireWorkspaceRole(ctx, userID, req.WorkspaceID, models.WorkspaceRoleEditor); err != nil {
+			return models.Link{}, err
+		}
+	}
+
+	newLink := models.StorageURL{
+		UserID:         userID,
+		OriginalURL:    originalURL,
+		Title:          req.Title,
+		Notes:          req.Notes,
+		Tags:           req.Tags,
+		Interstitial:   req.Interstitial,
+		MaxClicks:      req.MaxClicks,
+		ActiveFrom:     req.ActiveFrom,
+		ActiveUntil:    req.ActiveUntil,
+		FallbackURL:    req.FallbackURL,
+		Rules:          req.Rules,
+		StickyVariants: req.StickyVariants,
+		ForwardQuery:   req.ForwardQuery,
+		UTM:            normalizeUTM(req.UTM),
+		CollectionID:   req.CollectionID,
+		WorkspaceID:    req.WorkspaceID,
+		Domain:         domain,
+	}
+	if err = validateSchedule(newLink); err != nil {
+		return models.Link{}, err
+	}
+	if err = validateRules(newLink.Rules); err != nil {
+		return models.Link{}, err
+	}
+	if newLink.Variants, err = normalizeVariants(req.Variants); err != nil {
+		return models.Link{}, err
+	}
+	if err = s.checkDestinations(newLink); err != nil {
+		return models.Link{}, err
+	}
+
+	if newLink.PasswordHash, err = hashLinkPassword(req.Password); err != nil {
+		return models.Link{}, err
+	}
+
+	id, saveErr := s.saveLink(ctx, newLink)
+	var errConflict *storage.URLConflictError
+	if saveErr != nil && !errors.As(saveErr, &errConflict) {
+		return models.Link{}, saveErr
+	}
+	if saveErr != nil {
+		// при конфликте возвращается существующая ссылка
+		id = errConflict.URL
+	} else {
+		s.enqueuePageFetch(id, newLink.OriginalURL)
+	}
+
+	link, err := s.storage.GetLink(ctx, id)
+	if err != nil {
+		return models.Link{}, err
+	}
+	if link == nil {
+		return models.Link{}, ErrLinkNotFound
+	}
+
+	return s.link(*link), saveErr
+}
+
+// GetLink function for getting user's link or link of user's workspace by ID
+func (s *ShortenerService) GetLink(ctx context.Context, userID *uuid.UUID, id string) (models.Link, error) {
+	link, err := s.accessLink(ctx, userID, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return models.Link{}, err
+	}
+
+	return s.link(*link), nil
+}
+
+// ListLinks function for getting page of user's not deleted links, newest links are first
+func (s *ShortenerService) ListLinks(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]models.Link, models.LinkListMeta, error) {
+	if limit == 0 {
+		limit = defaultLinksLimit
+	}
+	if limit < 0 || limit > maxLinksLimit || offset < 0 {
+		return nil, models.LinkListMeta{}, ErrInvalidPagination
+	}
+
+	urls, total, err := s.storage.GetUrlsPageByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, models.LinkListMeta{}, err
+	}
+
+	return s.links(urls), models.LinkListMeta{Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// ListWorkspaceLinks function for getting page of not deleted links of user's workspace, newest links are first
+func (s *ShortenerService) ListWorkspaceLinks(ctx context.Context, userID *uuid.UUID, workspaceID string, limit int, offset int) ([]models.Link, models.LinkListMeta, error) {
+	if limit == 0 {
+		limit = defaultLinksLimit
+	}
+	if limit < 0 || limit > maxLinksLimit || offset < 0 {
+		return nil, models.LinkListMeta{}, ErrInvalidPagination
+	}
+
+	if _, err := s.requireWorkspaceRole(ctx, userID, workspaceID, models.WorkspaceRoleViewer); err != nil {
+		return nil, models.LinkListMeta{}, err
+	}
+
+	urls, total, err := s.storage.GetUrlsPageByWorkspace(ctx, workspaceID, limit, offset)
+	if err != nil {
+		return nil, models.LinkListMeta{}, err
+	}
+
+	return s.links(urls), models.LinkListMeta{Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// links function converts page of stored URLs to links of v2 API
+func (s *ShortenerService) links(urls []models.StorageURL) []models.Link {
+	links := make([]models.Link, 0, len(urls))
+	for _, link := range urls {
+		links = append(links, s.link(link))
+	}
+	return links
+}
+
+// filterLinks function returns user's not deleted links matching filter, newest links are first
+func (s *ShortenerService) filterLinks(ctx context.Context, userID *uuid.UUID, filter func(models.StorageURL) bool) ([]models.Link, error) {
+	links := make([]models.Link, 0)
+	err := s.storage.IterateUrlsByUser(ctx, userID, func(link models.StorageURL) error {
+		if !link.DeletedFlag && filter(link) {
+			links = append(links, s.link(link))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortLinks(links)
+	return links, nil
+}
+
+// sortLinks function sorts links from newest to oldest
+func sortLinks(links []models.Link) {
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.After(links[j].CreatedAt)
+		}
+		return links[i].ID < links[j].ID
+	})
+}
+
+// UpdateLink function for updating title, notes, tags and settings of user's link or link of workspace
+// where user is editor, omitted fields aren't changed
+func (s *ShortenerService) UpdateLink(ctx context.Context, userID *uuid.UUID, id string, req models.LinkUpdateRequest) (models.Link, error) {
+	link, err := s.ownLink(ctx, userID, id)
+	if err != nil {
+		return models.Link{}, err
+	}
+	wasPrivate := link.Private()
+
+	if req.Title != nil {
+		link.Title = *req.Title
+	}
+	if req.Notes != nil {
+		link.Notes = *req.Notes
+	}
+	if req.Tags != nil {
+		link.Tags = *req.Tags
+	}
+	if req.Interstitial != nil {
+		link.Interstitial = *req.Interstitial
+	}
+	if req.Password != nil {
+		if link.PasswordHash, err = hashLinkPassword(*req.Password); err != nil {
+			return models.Link{}, err
+		}
+	}
+	if req.MaxClicks != nil {
+		if *req.MaxClicks < 0 {
+			return models.Link{}, ErrInvalidMaxClicks
+		}
+		link.MaxClicks = *req.MaxClicks
+	}
+	if req.ActiveFrom != nil {
+		if link.ActiveFrom, err = parseWindowBound(*req.ActiveFrom); err != nil {
+			return models.Link{}, err
+		}
+	}
+	if req.ActiveUntil != nil {
+		if link.ActiveUntil, err = parseWindowBound(*req.ActiveUntil); err != nil {
+			return models.Link{}, err
+		}
+	}
+	if req.FallbackURL != nil {
+		link.FallbackURL = *req.FallbackURL
+	}
+	if err = validateSchedule(*link); err != nil {
+		return models.Link{}, err
+	}
+	if req.Rules != nil {
+		if err = validateRules(*req.Rules); err != nil {
+			return models.Link{}, err
+		}
+		link.Rules = *req.Rules
+	}
+	if req.Variants != nil {
+		if link.Variants, err = normalizeVariants(*req.Variants); err != nil {
+			return models.Link{}, err
+		}
+	}
+	if req.StickyVariants != nil {
+		link.StickyVariants = *req.StickyVariants
+	}
+	if req.ForwardQuery != nil {
+		link.ForwardQuery = *req.ForwardQuery
+	}
+	if req.UTM != nil {
+		link.UTM = normalizeUTM(req.UTM)
+	}
+	if req.CollectionID != nil && *req.CollectionID != link.CollectionID {
+		// коллекцию ссылки меняет только её создатель, коллекции редактора пространства ему не принадлежат
+		if !sameUserID(link.UserID, userID) {
+			return models.Link{}, ErrWorkspaceForbidden
+		}
+		if *req.CollectionID != "" {
+			if _, err = s.ownCollection(ctx, userID, *req.CollectionID); err != nil {
+				return models.Link{}, err
+			}
+		}
+		link.CollectionID = *req.CollectionID
+	}
+	if req.WorkspaceID != nil && *req.WorkspaceID != link.WorkspaceID {
+		// только создатель ссылки переносит её между рабочими пространствами
+		if !sameUserID(link.UserID, userID) {
+			return models.Link{}, ErrWorkspaceForbidden
+		}
+		if *req.WorkspaceID != "" {
+			if _, err = s.requireWorkspaceRole(ctx, userID, *req.WorkspaceID, models.WorkspaceRoleEditor); err != nil {
+				return models.Link{}, err
+			}
+		}
+		link.WorkspaceID = *req.WorkspaceID
+	}
+
+	if !wasPrivate && link.Private() && s.sharedCode(*link) {
+		return models.Link{}, ErrSharedLinkPrivate
+	}
+	if wasPrivate && !link.Private() && !s.sharedCode(*link) {
+		// публичная ссылка находится по исходному URL, её код должен совпадать с кодом по адресу
+		return models.Link{}, ErrPrivateLinkPublic
+	}
+
+	if err = s.checkDestinations(*link); err != nil {
+		return models.Link{}, err
+	}
+
+	if err := s.storage.UpdateLink(ctx, *link); err != nil {
+		return models.Link{}, err
+	}
+
+	return s.link(*link), nil
+}
+
+// DeleteLink function for deleting user's link or link of workspace where user is editor by ID
+func (s *ShortenerService) DeleteLink(ctx context.Context, userID *uuid.UUID, id string) error {
+	link, err := s.ownLink(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if link.WorkspaceID != "" {
+		return s.storage.DeleteWorkspaceUrlsBatch(ctx, link.WorkspaceID, []string{id})
+	}
+	return s.storage.DeleteUrlsBatch(ctx, userID, []string{id})
+}
+
+// GetLinkInfo function for inspecting link without redirect, metadata is returned for owner
+// and members of link's workspace only
+func (s *ShortenerService) GetLinkInfo(ctx context.Context, userID *uuid.UUID, id string) (models.LinkInfo, error) {
+	link, err := s.storage.GetLink(ctx, id)
+	if err != nil {
+		return models.LinkInfo{}, err
+	}
+	if link == nil {
+		return models.LinkInfo{}, ErrLinkNotFound
+	}
+
+	info := models.LinkInfo{
+		ID:          link.ShortURL,
+		ShortURL:    s.shortLink(link.ShortURL),
+		OriginalURL: link.OriginalURL,
+		CreatedAt:   link.CreatedAt,
+		State:       models.LinkStateActive,
+		Clicks:      link.Clicks,
+		MaxClicks:   link.MaxClicks,
+		ActiveFrom:  link.ActiveFrom,
+		ActiveUntil: link.ActiveUntil,
+	}
+	switch state := windowState(*link, time.Now()); {
+	case link.DeletedFlag:
+		info.State = models.LinkStateDeleted
+	case clicksExhausted(*link):
+		info.State = models.LinkStateExpired
+	case state != "":
+		info.State = state
+	}
+	info.PasswordProtected = link.PasswordHash != ""
+
+	err = s.checkLinkAccess(ctx, userID, *link, models.WorkspaceRoleViewer)
+	if err != nil && !errors.Is(err, ErrLinkNotFound) {
+		return models.LinkInfo{}, err
+	}
+	owner := err == nil
+	if info.PasswordProtected && !owner {
+		info.OriginalURL = ""
+	}
+
+	if owner {
+		resource := s.link(*link)
+		info.Variants = link.Variants
+		info.Metadata = &models.LinkMetadata{
+			Title: resource.Title,
+			Notes: resource.Notes,
+			Tags:  resource.Tags,
+		}
+	}
+
+	return info, nil
+}
+
+// sharedCode function checks that link code is derived from its original URL, such code is found by other users
+func (s *ShortenerService) sharedCode(link models.StorageURL) bool {
+	return link.ShortURL == linkKey(linkDomain(link.ShortURL), s.ShortURL(link.OriginalURL))
+}
+
+// ownLink function returns not deleted link of user or link of workspace where user is editor
+func (s *ShortenerService) ownLink(ctx context.Context, userID *uuid.UUID, id string) (*models.StorageURL, error) {
+	return s.accessLink(ctx, userID, id, models.WorkspaceRoleEditor)
+}
+
+// link function converts stored URL to link resource
+func (s *ShortenerService) link(link models.StorageURL) models.Link {
+	tags := link.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	rules := link.Rules
+	if rules == nil {
+		rules = []models.RedirectRule{}
+	}
+	variants := link.Variants
+	if variants == nil {
+		variants = []models.Variant{}
+	}
+
+	return models.Link{
+		ID:                link.ShortURL,
+		ShortURL:          s.shortLink(link.ShortURL),
+		Domain:            s.domainHost(linkDomain(link.ShortURL)),
+		CollectionID:      link.CollectionID,
+		WorkspaceID:       link.WorkspaceID,
+		OriginalURL:       link.OriginalURL,
+		Title:             link.Title,
+		Notes:             link.Notes,
+		Tags:              tags,
+		Interstitial:      link.Interstitial,
+		PasswordProtected: link.PasswordHash != "",
+		MaxClicks:         link.MaxClicks,
+		ActiveFrom:        link.ActiveFrom,
+		ActiveUntil:       link.ActiveUntil,
+		FallbackURL:       link.FallbackURL,
+		Rules:             rules,
+		Variants:          variants,
+		StickyVariants:    link.StickyVariants,
+		ForwardQuery:      link.ForwardQuery,
+		Page:              link.Page,
+		UTM:               link.UTM,
+		CreatedAt:         link.CreatedAt,
+	}
+}
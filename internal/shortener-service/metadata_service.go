@@ -0,0 +1,238 @@
+package shortenerservice
+
+import (
+	"context"
+	"fmt"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Параметры загрузки метаданных страниц
+const (
+	// pageFetchTimeout timeout of destination page request
+	pageFetchTimeout = 10 * time.Second
+	// pageFetchBodyLimit max size of destination page read by fetcher, metadata is expected in head
+	pageFetchBodyLimit = 512 << 10
+	// pageFetchQueueSize max count of links waiting for page fetch
+	pageFetchQueueSize = 1024
+	// pageFetchUserAgent User-Agent of fetcher requests
+	pageFetchUserAgent = "go-shortener-page-fetcher/1.0"
+	// pageTitleLimit max length of saved page title in runes
+	pageTitleLimit = 300
+	// pageDescriptionLimit max length of saved page description in runes
+	pageDescriptionLimit = 1000
+	// pageImageLimit max length of saved page image URL
+	pageImageLimit = 2048
+)
+
+// pageFetch link waiting for destination page fetch
+type pageFetch struct {
+	shortURL    string
+	destination string
+}
+
+// enqueuePageFetch function adds created link to page fetch queue, link is skipped if fetcher is disabled or queue is full
+func (s *ShortenerService) enqueuePageFetch(shortURL string, destination string) {
+	if s.pageQueue == nil {
+		return
+	}
+
+	select {
+	case s.pageQueue <- pageFetch{shortURL: shortURL, destination: destination}:
+	default:
+		logger.Log.Warn("Очередь загрузки страниц переполнена", zap.String("url", shortURL))
+	}
+}
+
+// runPageFetcher function fetches destination pages of queued links until ctx is done
+func (s *ShortenerService) runPageFetcher(ctx context.Context) {
+	for {
+		select {
+		case job := <-s.pageQueue:
+			if err := s.FetchPageMetadata(ctx, job.shortURL, job.destination); err != nil {
+				logger.Log.Debug("Ошибка загрузки метаданных страницы", zap.String("url", job.destination), zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// FetchPageMetadata function loads destination page and saves its title, description and image for short URL,
+// nothing is saved if page has no metadata
+func (s *ShortenerService) FetchPageMetadata(ctx context.Context, shortURL string, destination string) error {
+	page, err := s.fetchPage(ctx, destination)
+	if err != nil {
+		return err
+	}
+	if page == (models.PageMetadata{}) {
+		return nil
+	}
+
+	return s.storage.SavePageMetadata(ctx, shortURL, page)
+}
+
+// fetchPage function requests destination page and parses metadata from its head, only HTML pages are parsed
+func (s *ShortenerService) fetchPage(ctx context.Context, destination string) (models.PageMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, pageFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, destination, nil)
+	if err != nil {
+		return models.PageMetadata{}, err
+	}
+	req.Header.Set("User-Agent", pageFetchUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := s.pageClient.Do(req)
+	if err != nil {
+		return models.PageMetadata{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return models.PageMetadata{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return models.PageMetadata{}, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, pageFetchBodyLimit), contentType)
+	if err != nil {
+		return models.PageMetadata{}, err
+	}
+
+	return parsePageMetadata(body, resp.Request.URL), nil
+}
+
+// parsePageMetadata function reads title, Open Graph description and image from page head.
+// Open Graph title is used if page has no title, meta description is used if page has no Open Graph description
+func parsePageMetadata(r io.Reader, base *url.URL) models.PageMetadata {
+	var title, ogTitle, description, ogDescription, image string
+	inTitle := false
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// конец документа или ограничения размера, используется то, что успели прочитать
+			break loop
+		case html.TextToken:
+			if inTitle {
+				title += string(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				break loop
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = title == ""
+			case atom.Body:
+				break loop
+			case atom.Meta:
+				var key, content string
+				for hasAttr {
+					var attr, value []byte
+					attr, value, hasAttr = z.TagAttr()
+					switch string(attr) {
+					case "property", "name":
+						if key == "" {
+							key = strings.ToLower(strings.TrimSpace(string(value)))
+						}
+					case "content":
+						content = string(value)
+					}
+				}
+
+				switch key {
+				case "og:title":
+					ogTitle = firstValue(ogTitle, content)
+				case "og:description":
+					ogDescription = firstValue(ogDescription, content)
+				case "description":
+					description = firstValue(description, content)
+				case "og:image", "og:image:url":
+					image = firstValue(image, content)
+				}
+			}
+		}
+	}
+
+	return models.PageMetadata{
+		Title:       pageText(firstValue(cleanText(title), ogTitle), pageTitleLimit),
+		Description: pageText(firstValue(cleanText(ogDescription), description), pageDescriptionLimit),
+		Image:       pageImage(image, base),
+	}
+}
+
+// firstValue function returns current value if it's set, otherwise value
+func firstValue(current string, value string) string {
+	if strings.TrimSpace(current) != "" {
+		return current
+	}
+	return value
+}
+
+// cleanText function collapses whitespace of text
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// pageText function collapses whitespace of text and truncates it to limit runes
+func pageText(text string, limit int) string {
+	text = cleanText(text)
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "")
+	}
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string([]rune(text)[:limit])) + "…"
+}
+
+// pageImage function resolves image URL relative to page URL, only http and https images are kept
+func pageImage(image string, base *url.URL) string {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return ""
+	}
+
+	u, err := url.Parse(image)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+
+	resolved := u.String()
+	if len(resolved) > pageImageLimit {
+		return ""
+	}
+	return resolved
+}
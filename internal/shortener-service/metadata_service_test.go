@@ -0,0 +1,267 @@
+package shortenerservice
+
+import (
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_parsePageMetadata(t *testing.T) {
+	base, err := url.Parse("https://example.com/news/article")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		page string
+		want models.PageMetadata
+	}{
+		{
+			name: "title_and_open_graph",
+			page: `<!DOCTYPE html><html><head>
+<title>
+  Новости   &amp; статьи
+</title>
+<meta property="og:title" content="OG title">
+<meta property="og:description" content="Описание статьи">
+<meta name="description" content="Meta description">
+<meta property="og:image" content="/images/cover.png">
+</head><body><title>Other</title></body></html>`,
+			want: models.PageMetadata{Title: "Новости & статьи", Description: "Описание статьи", Image: "https://example.com/images/cover.png"},
+		},
+		{
+			name: "fallbacks",
+			page: `<html><head><meta property="og:title" content="OG title"/><meta name="Description" content="Meta description"/></head></html>`,
+			want: models.PageMetadata{Title: "OG title", Description: "Meta description"},
+		},
+		{
+			name: "metadata_in_body_ignored",
+			page: `<html><head></head><body><meta property="og:description" content="Body description"></body></html>`,
+			want: models.PageMetadata{},
+		},
+		{
+			name: "not_http_image",
+			page: `<head><title>Page</title><meta property="og:image" content="javascript:alert(1)"></head>`,
+			want: models.PageMetadata{Title: "Page"},
+		},
+		{
+			name: "long_title",
+			page: `<title>` + strings.Repeat("я", pageTitleLimit+10) + `</title>`,
+			want: models.PageMetadata{Title: strings.Repeat("я", pageTitleLimit) + "…"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parsePageMetadata(strings.NewReader(tt.page), base))
+		})
+	}
+}
+
+func TestShortenerService_FetchPageMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(`<html><head><title>Page</title><meta property="og:image" content="cover.png"></head></html>`))
+		case "/cp1251":
+			w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+			// "Привет" в кодировке windows-1251
+			_, _ = w.Write([]byte("<title>\xcf\xf0\xe8\xe2\xe5\xf2</title>"))
+		case "/large":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html><head>" + strings.Repeat(" ", pageFetchBodyLimit) + "<title>Late</title></head></html>"))
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("<title>Image</title>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+	// тестовый сервер слушает loopback, к которому клиент сервиса не подключается
+	s.pageClient = server.Client()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    *models.PageMetadata
+		wantErr bool
+	}{
+		{name: "html", path: "/page", want: &models.PageMetadata{Title: "Page", Image: server.URL + "/cover.png"}},
+		{name: "charset", path: "/cp1251", want: &models.PageMetadata{Title: "Привет"}},
+		{name: "body_limit", path: "/large"},
+		{name: "not_html", path: "/image", wantErr: true},
+		{name: "not_found", path: "/missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destination := server.URL + tt.path
+			shortURL, err := s.Shorten(context.Background(), destination, nil)
+			require.NoError(t, err)
+			id := strings.TrimPrefix(shortURL, cfg.BaseURL+"/")
+
+			err = s.FetchPageMetadata(context.Background(), id, destination)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			link, err := storageURLs.GetLink(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, link.Page)
+		})
+	}
+}
+
+func TestShortenerService_PageFetcher(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<head><title>Page</title><meta property="og:description" content="Description"></head>`))
+	}))
+	defer server.Close()
+
+	t.Run("enabled", func(t *testing.T) {
+		cfg := &config.ConfigENV{BaseURL: "http://localhost:8080", FetchPageMetadata: true, PageFetchWorkers: 2}
+		storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+		s := NewShortenerService(&storageURLs, cfg)
+		s.pageClient = server.Client()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s.Start(ctx)
+
+		userID := uuid.Must(uuid.NewV4())
+		shortURL, err := s.Shorten(context.Background(), server.URL+"/enabled", &userID)
+		require.NoError(t, err)
+		id := strings.TrimPrefix(shortURL, cfg.BaseURL+"/")
+
+		require.Eventually(t, func() bool {
+			link, err := storageURLs.GetLink(context.Background(), id)
+			return err == nil && link.Page != nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		link, err := s.GetLink(context.Background(), &userID, id)
+		require.NoError(t, err)
+		assert.Equal(t, &models.PageMetadata{Title: "Page", Description: "Description"}, link.Page)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		requests.Store(0)
+		cfg := &config.ConfigENV{BaseURL: "http://localhost:8080", FetchPageMetadata: false, PageFetchWorkers: 2}
+		storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+		s := NewShortenerService(&storageURLs, cfg)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s.Start(ctx)
+
+		_, err := s.Shorten(context.Background(), server.URL+"/disabled", nil)
+		require.NoError(t, err)
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Zero(t, requests.Load())
+	})
+}
+
+func TestShortenerService_FetchPagePrivateDestination(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<head><title>Internal</title></head>`))
+	}))
+	defer server.Close()
+
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+
+	shortURL, err := s.Shorten(context.Background(), server.URL+"/internal", nil)
+	require.NoError(t, err)
+	id := strings.TrimPrefix(shortURL, cfg.BaseURL+"/")
+
+	err = s.FetchPageMetadata(context.Background(), id, server.URL+"/internal")
+	assert.ErrorIs(t, err, ErrPrivateDestination)
+	assert.Zero(t, requests.Load())
+
+	link, err := storageURLs.GetLink(context.Background(), id)
+	require.NoError(t, err)
+	assert.Nil(t, link.Page)
+
+	// переход по редиректу на внутренний адрес отклоняется до подключения
+	req := httptest.NewRequest(http.MethodGet, "http://169.254.169.254/latest/meta-data/", nil)
+	assert.ErrorIs(t, s.pageClient.CheckRedirect(req, nil), ErrPrivateDestination)
+}
+
+func Test_publicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "127.0.0.1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "172.16.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "::1", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "0.1.2.3", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "100.127.255.254", want: false},
+		{addr: "192.0.0.8", want: false},
+		{addr: "192.0.2.1", want: false},
+		{addr: "198.18.0.1", want: false},
+		{addr: "198.19.255.255", want: false},
+		{addr: "198.51.100.1", want: false},
+		{addr: "203.0.113.1", want: false},
+		{addr: "224.0.0.1", want: false},
+		{addr: "240.0.0.1", want: false},
+		{addr: "255.255.255.255", want: false},
+		{addr: "::", want: false},
+		{addr: "::127.0.0.1", want: false},
+		{addr: "::ffff:10.0.0.1", want: false},
+		{addr: "::ffff:100.64.0.1", want: false},
+		{addr: "::ffff:198.18.0.1", want: false},
+		{addr: "64:ff9b::7f00:1", want: false},
+		{addr: "64:ff9b::4d58:37f2", want: false},
+		{addr: "64:ff9b:1::a00:1", want: false},
+		{addr: "100::1", want: false},
+		{addr: "2001::1", want: false},
+		{addr: "2001:db8::1", want: false},
+		{addr: "2002:7f00:1::1", want: false},
+		{addr: "3fff::1", want: false},
+		{addr: "fc00::1", want: false},
+		{addr: "fec0::1", want: false},
+		{addr: "ff02::1", want: false},
+		{addr: "77.88.55.242", want: true},
+		{addr: "8.8.8.8", want: true},
+		{addr: "::ffff:77.88.55.242", want: true},
+		{addr: "2a02:6b8::2:242", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, publicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+
+	assert.False(t, publicAddr(netip.Addr{}))
+}
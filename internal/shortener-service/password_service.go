@@ -0,0 +1,176 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"time"
+)
+
+// maxPasswordAttempts count of wrong passwords for link from one client in PasswordAttemptsWindow
+const maxPasswordAttempts = 5
+
+// PasswordAttemptsWindow window for counting wrong passwords
+const PasswordAttemptsWindow = 15 * time.Minute
+
+// LinkAccessTTL lifetime of token which opens password-protected link without password
+const LinkAccessTTL = time.Hour
+
+// ErrLinkPasswordRequired link is password-protected
+var ErrLinkPasswordRequired = errors.New("для перехода по ссылке нужен пароль")
+
+// ErrInvalidLinkPassword password of link is wrong
+var ErrInvalidLinkPassword = errors.New("неверный пароль")
+
+// ErrTooManyPasswordAttempts client entered wrong password too many times
+var ErrTooManyPasswordAttempts = errors.New("слишком много попыток ввода пароля, попробуйте позже")
+
+// passwordAttempts wrong passwords count of client in window
+type passwordAttempts struct {
+	count int
+	start time.Time
+}
+
+// CheckLinkPassword function verifies password of link, wrong attempts are limited per link and client
+func (s *ShortenerService) CheckLinkPassword(ctx context.Context, id string, client string, password string) error {
+	link, err := s.storage.GetLink(ctx, id)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return ErrLinkNotFound
+	}
+	if link.DeletedFlag {
+		return storage.NewAlreadyDeletedError(id)
+	}
+	if link.PasswordHash == "" {
+		return nil
+	}
+
+	key := id + "/" + client
+	if !s.passwordAttempt(key) {
+		return ErrTooManyPasswordAttempts
+	}
+
+	ok, err := auth.VerifyPassword(link.PasswordHash, password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidLinkPassword
+	}
+
+	s.forgetPasswordAttempts(key)
+
+	return nil
+}
+
+// LinkAccessToken function creates signed token which opens password-protected link till LinkAccessTTL expires
+func (s *ShortenerService) LinkAccessToken(id string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   id,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(LinkAccessTTL)),
+	})
+
+	return token.SignedString([]byte(s.Cfg.SecretKey))
+}
+
+// VerifyLinkAccess function checks token created by LinkAccessToken for link
+func (s *ShortenerService) VerifyLinkAccess(id string, tokenString string) bool {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims,
+		func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("неизвестный алгоритм подписи: %v", t.Header["alg"])
+			}
+			return []byte(s.Cfg.SecretKey), nil
+		})
+
+	return err == nil && token.Valid && claims.Subject == id
+}
+
+// hashLinkPassword function returns hash of password, empty password means link without protection
+func hashLinkPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	return auth.HashPassword(password)
+}
+
+// passwordAttempt function counts password check for every key before password is verified,
+// so concurrent checks can't exceed the limit. Nothing is counted and false is returned if any key reached the limit
+func (s *ShortenerService) passwordAttempt(keys ...string) bool {
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		attempts, ok := s.attempts[key]
+		if ok && now.Sub(attempts.start) <= PasswordAttemptsWindow && attempts.count >= maxPasswordAttempts {
+			return false
+		}
+	}
+
+	for _, key := range keys {
+		attempts, ok := s.attempts[key]
+		if !ok || now.Sub(attempts.start) > PasswordAttemptsWindow {
+			attempts = passwordAttempts{start: now}
+		}
+		attempts.count++
+		s.attempts[key] = attempts
+	}
+	return true
+}
+
+// forgetPasswordAttempts function removes counted attempts of key after right password
+func (s *ShortenerService) forgetPasswordAttempts(key string) {
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+
+	delete(s.attempts, key)
+}
+
+// releasePasswordAttempt function uncounts attempt of key with right password, other attempts in window are kept
+func (s *ShortenerService) releasePasswordAttempt(key string) {
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+
+	if attempts, ok := s.attempts[key]; ok && attempts.count > 0 {
+		attempts.count--
+		s.attempts[key] = attempts
+	}
+}
+
+// prunePasswordAttempts function removes expired windows of password attempts
+func (s *ShortenerService) prunePasswordAttempts() {
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+
+	now := time.Now()
+	for key, attempts := range s.attempts {
+		if now.Sub(attempts.start) > PasswordAttemptsWindow {
+			delete(s.attempts, key)
+		}
+	}
+}
+
+// runAttemptsPruner function removes expired windows of password attempts, expired checks of anonymous users
+// and expired login sessions by timer until ctx is done
+func (s *ShortenerService) runAttemptsPruner(ctx context.Context) {
+	ticker := time.NewTicker(PasswordAttemptsWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.prunePasswordAttempts()
+			s.pruneAnonymousUsers()
+			s.pruneSessions(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShortenerService_CheckLinkPasswordConcurrent(t *testing.T) {
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+
+	userID := uuid.Must(uuid.NewV4())
+	link, err := s.CreateLink(context.Background(), &userID, models.LinkCreateRequest{OriginalURL: "https://ya.ru", Password: "secret"})
+	require.NoError(t, err)
+
+	// попытка учитывается до проверки пароля, поэтому одновременные запросы не превышают лимит
+	var wrong, limited atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.CheckLinkPassword(context.Background(), link.ID, "203.0.113.7", "wrong")
+			switch {
+			case errors.Is(err, ErrInvalidLinkPassword):
+				wrong.Add(1)
+			case errors.Is(err, ErrTooManyPasswordAttempts):
+				limited.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(maxPasswordAttempts), wrong.Load())
+	assert.Equal(t, int32(20-maxPasswordAttempts), limited.Load())
+	assert.ErrorIs(t, s.CheckLinkPassword(context.Background(), link.ID, "203.0.113.7", "secret"), ErrTooManyPasswordAttempts)
+	assert.NoError(t, s.CheckLinkPassword(context.Background(), link.ID, "203.0.113.8", "secret"))
+}
+
+func TestShortenerService_PrunePasswordAttempts(t *testing.T) {
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+
+	s.attempts["expired"] = passwordAttempts{count: maxPasswordAttempts, start: time.Now().Add(-2 * PasswordAttemptsWindow)}
+	require.True(t, s.passwordAttempt("current"))
+
+	s.prunePasswordAttempts()
+	assert.NotContains(t, s.attempts, "expired")
+	assert.Contains(t, s.attempts, "current")
+}
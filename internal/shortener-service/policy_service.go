@@ -0,0 +1,238 @@
+package shortenerservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"go.uber.org/zap"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Причины отклонения URL политикой
+const (
+	// PolicyReasonScheme scheme of URL isn't in allowed schemes
+	PolicyReasonScheme = "scheme_not_allowed"
+	// PolicyReasonDenied domain of URL is in deny list
+	PolicyReasonDenied = "domain_denied"
+	// PolicyReasonNotAllowed allow list is set and domain of URL isn't in it
+	PolicyReasonNotAllowed = "domain_not_allowed"
+	// PolicyReasonSelf URL points to shortener itself and makes redirect loop
+	PolicyReasonSelf = "self_referential"
+)
+
+// defaultAllowedSchemes schemes of destination URLs allowed if schemes aren't configured
+var defaultAllowedSchemes = []string{"http", "https"}
+
+// URLPolicyError structure for destination URL rejected by URL policy with reason code
+type URLPolicyError struct {
+	URL    string
+	Reason string
+}
+
+// Error function returns rejection message with reason
+func (pe *URLPolicyError) Error() string {
+	switch pe.Reason {
+	case PolicyReasonScheme:
+		return fmt.Sprintf("схема URL %v не разрешена", pe.URL)
+	case PolicyReasonDenied:
+		return fmt.Sprintf("домен URL %v заблокирован", pe.URL)
+	case PolicyReasonNotAllowed:
+		return fmt.Sprintf("домен URL %v не входит в список разрешенных", pe.URL)
+	case PolicyReasonSelf:
+		return fmt.Sprintf("URL %v указывает на сокращатель ссылок", pe.URL)
+	default:
+		return fmt.Sprintf("URL %v запрещен", pe.URL)
+	}
+}
+
+// NewURLPolicyError factory for create URL policy error
+func NewURLPolicyError(url string, reason string) error {
+	return &URLPolicyError{
+		URL:    url,
+		Reason: reason,
+	}
+}
+
+// urlPolicy structure for domain lists of URL policy file, domain matches itself and its subdomains
+type urlPolicy struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// policyFile structure for loaded URL policy and modification time of its file
+type policyFile struct {
+	mu      sync.RWMutex
+	policy  urlPolicy
+	modTime time.Time
+}
+
+// parseSchemes function reads comma-separated allowed schemes of destination URLs, spaces and empty items are skipped
+func parseSchemes(schemes string) []string {
+	parsed := make([]string, 0)
+	for _, scheme := range strings.Split(schemes, ",") {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if scheme == "" {
+			continue
+		}
+		parsed = append(parsed, scheme)
+	}
+	return parsed
+}
+
+// checkURLPolicy function checks destination URL by allowed schemes, domain lists of policy file and base URL
+func (s *ShortenerService) checkURLPolicy(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidURL
+	}
+
+	schemes := s.schemes
+	if len(schemes) == 0 {
+		schemes = defaultAllowedSchemes
+	}
+	if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+		return NewURLPolicyError(rawURL, PolicyReasonScheme)
+	}
+	// разрешенные схемы без хоста (mailto:, tel:) не проверяются по спискам доменов
+	if u.Host == "" && u.Opaque != "" {
+		return nil
+	}
+
+	host, err := canonicalHost(strings.TrimSuffix(u.Hostname(), "."))
+	if err != nil || host == "" {
+		return ErrInvalidURL
+	}
+	if s.isShortDomain(u) {
+		return NewURLPolicyError(rawURL, PolicyReasonSelf)
+	}
+
+	s.policy.mu.RLock()
+	defer s.policy.mu.RUnlock()
+
+	if matchDomain(s.policy.policy.Deny, host) {
+		return NewURLPolicyError(rawURL, PolicyReasonDenied)
+	}
+	if len(s.policy.policy.Allow) > 0 && !matchDomain(s.policy.policy.Allow, host) {
+		return NewURLPolicyError(rawURL, PolicyReasonNotAllowed)
+	}
+	return nil
+}
+
+// checkDestinations function checks fallback URL, redirect rules and A/B variants of link by URL policy
+func (s *ShortenerService) checkDestinations(link models.StorageURL) error {
+	if link.FallbackURL != "" {
+		if err := s.checkURLPolicy(link.FallbackURL); err != nil {
+			return err
+		}
+	}
+	for _, rule := range link.Rules {
+		if err := s.checkURLPolicy(rule.URL); err != nil {
+			return err
+		}
+	}
+	for _, variant := range link.Variants {
+		if err := s.checkURLPolicy(variant.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameOrigin function checks if URLs have the same host and port, default port of scheme is used if port isn't set
+func sameOrigin(a *url.URL, b *url.URL) bool {
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		return defaultPorts[strings.ToLower(u.Scheme)]
+	}
+	return strings.EqualFold(strings.TrimSuffix(a.Hostname(), "."), strings.TrimSuffix(b.Hostname(), ".")) && port(a) == port(b)
+}
+
+// matchDomain function checks if host is one of domains or their subdomain
+func matchDomain(domains []string, host string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadURLPolicy function reads domain lists from URL policy file if file is changed since the last load
+func (s *ShortenerService) LoadURLPolicy() error {
+	info, err := os.Stat(s.Cfg.URLPolicyFile)
+	if err != nil {
+		return fmt.Errorf("can't read url policy file: %w", err)
+	}
+
+	s.policy.mu.RLock()
+	changed := !info.ModTime().Equal(s.policy.modTime)
+	s.policy.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.Cfg.URLPolicyFile)
+	if err != nil {
+		return fmt.Errorf("can't read url policy file: %w", err)
+	}
+	var policy urlPolicy
+	if err = json.Unmarshal(data, &policy); err != nil {
+		return fmt.Errorf("can't parse url policy file: %w", err)
+	}
+	if policy.Allow, err = normalizeDomains(policy.Allow); err != nil {
+		return err
+	}
+	if policy.Deny, err = normalizeDomains(policy.Deny); err != nil {
+		return err
+	}
+
+	s.policy.mu.Lock()
+	s.policy.policy, s.policy.modTime = policy, info.ModTime()
+	s.policy.mu.Unlock()
+
+	logger.Log.Info("Загружена политика URL", zap.Int("allow", len(policy.Allow)), zap.Int("deny", len(policy.Deny)))
+	return nil
+}
+
+// normalizeDomains function converts domains of policy file to canonical form
+func normalizeDomains(domains []string) ([]string, error) {
+	result := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(domain), "*."), ".")
+		if domain == "" {
+			continue
+		}
+		host, err := canonicalHost(domain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid domain %q in url policy file: %w", domain, err)
+		}
+		result = append(result, host)
+	}
+	return result, nil
+}
+
+// watchURLPolicy function reloads URL policy file by timer until ctx is done, previous policy is kept if file can't be loaded
+func (s *ShortenerService) watchURLPolicy(ctx context.Context) {
+	ticker := time.NewTicker(s.Cfg.URLPolicyReload)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.LoadURLPolicy(); err != nil {
+				logger.Log.Error("Ошибка загрузки политики URL", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package shortenerservice
+
+import (
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestShortenerService_checkURLPolicy(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(policyPath, []byte(`{"deny":["evil.example","*.ads.example.","Пример.рф"]}`), 0o644))
+
+	s := &ShortenerService{Cfg: &config.ConfigENV{BaseURL: "http://localhost:8080", URLPolicyFile: policyPath}}
+	require.NoError(t, s.LoadURLPolicy())
+
+	tests := []struct {
+		name       string
+		rawURL     string
+		wantReason string
+	}{
+		{name: "allowed", rawURL: "https://ya.ru/path"},
+		{name: "javascript", rawURL: "javascript:alert(1)", wantReason: PolicyReasonScheme},
+		{name: "data", rawURL: "data:text/html,<script>alert(1)</script>", wantReason: PolicyReasonScheme},
+		{name: "file", rawURL: "file:///etc/passwd", wantReason: PolicyReasonScheme},
+		{name: "scheme case", rawURL: "JavaScript:alert(1)", wantReason: PolicyReasonScheme},
+		{name: "denied domain", rawURL: "https://evil.example/login", wantReason: PolicyReasonDenied},
+		{name: "denied subdomain", rawURL: "https://WWW.Evil.Example./login", wantReason: PolicyReasonDenied},
+		{name: "denied wildcard", rawURL: "http://x.ads.example", wantReason: PolicyReasonDenied},
+		{name: "denied IDN", rawURL: "https://пример.рф", wantReason: PolicyReasonDenied},
+		{name: "similar domain", rawURL: "https://notevil.example"},
+		{name: "self", rawURL: "http://localhost:8080/6YGS4ZUF", wantReason: PolicyReasonSelf},
+		{name: "self host case", rawURL: "http://LOCALHOST:8080", wantReason: PolicyReasonSelf},
+		{name: "other port", rawURL: "http://localhost:3000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkURLPolicy(tt.rawURL)
+			if tt.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var errPolicy *URLPolicyError
+			require.ErrorAs(t, err, &errPolicy)
+			assert.Equal(t, tt.wantReason, errPolicy.Reason)
+			assert.Equal(t, tt.rawURL, errPolicy.URL)
+		})
+	}
+
+	t.Run("Allowed_Schemes", func(t *testing.T) {
+		s := &ShortenerService{Cfg: &config.ConfigENV{}, schemes: parseSchemes("https,mailto")}
+		assert.NoError(t, s.checkURLPolicy("mailto:user@example.com"))
+		assert.NoError(t, s.checkURLPolicy("https://ya.ru"))
+
+		var errPolicy *URLPolicyError
+		require.ErrorAs(t, s.checkURLPolicy("http://ya.ru"), &errPolicy)
+		assert.Equal(t, PolicyReasonScheme, errPolicy.Reason)
+	})
+
+	t.Run("Allowed_Schemes_Spaces", func(t *testing.T) {
+		s := &ShortenerService{Cfg: &config.ConfigENV{}, schemes: parseSchemes(" http, HTTPS ,,")}
+		assert.Equal(t, []string{"http", "https"}, s.schemes)
+		assert.NoError(t, s.checkURLPolicy("https://ya.ru"))
+		assert.NoError(t, s.checkURLPolicy("http://ya.ru"))
+		assert.Error(t, s.checkURLPolicy("ftp://ya.ru"))
+	})
+
+	t.Run("Destinations", func(t *testing.T) {
+		err := s.checkDestinations(models.StorageURL{
+			OriginalURL: "https://ya.ru",
+			Rules:       []models.RedirectRule{{Device: models.DeviceIOS, URL: "https://apps.apple.com"}},
+			Variants:    []models.Variant{{ID: "v1", URL: "https://evil.example/a", Weight: 1}},
+		})
+		var errPolicy *URLPolicyError
+		require.ErrorAs(t, err, &errPolicy)
+		assert.Equal(t, "https://evil.example/a", errPolicy.URL)
+
+		assert.Error(t, s.checkDestinations(models.StorageURL{FallbackURL: "javascript:alert(1)"}))
+	})
+}
+
+func TestShortenerService_LoadURLPolicy(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(policyPath, []byte(`{"allow":["ya.ru"]}`), 0o644))
+
+	s := &ShortenerService{Cfg: &config.ConfigENV{URLPolicyFile: policyPath}}
+	require.NoError(t, s.LoadURLPolicy())
+	assert.NoError(t, s.checkURLPolicy("https://mail.ya.ru"))
+	assert.Error(t, s.checkURLPolicy("https://dzen.ru"))
+
+	// новый список применяется после изменения файла
+	require.NoError(t, os.WriteFile(policyPath, []byte(`{"allow":["dzen.ru"]}`), 0o644))
+	modTime := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(policyPath, modTime, modTime))
+	require.NoError(t, s.LoadURLPolicy())
+	assert.NoError(t, s.checkURLPolicy("https://dzen.ru"))
+	assert.Error(t, s.checkURLPolicy("https://ya.ru"))
+
+	// некорректный файл не сбрасывает загруженную политику
+	require.NoError(t, os.WriteFile(policyPath, []byte(`{"allow":`), 0o644))
+	modTime = modTime.Add(time.Second)
+	require.NoError(t, os.Chtimes(policyPath, modTime, modTime))
+	assert.Error(t, s.LoadURLPolicy())
+	assert.NoError(t, s.checkURLPolicy("https://dzen.ru"))
+	assert.Error(t, s.checkURLPolicy("https://ya.ru"))
+}
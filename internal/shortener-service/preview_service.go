@@ -0,0 +1,102 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// PreviewSuffix suffix of short URL for showing preview page instead of redirect
+const PreviewSuffix = "+"
+
+// Resolve function finds link by short URL before redirect and checks if preview page or password is required.
+// Preview is requested for id with PreviewSuffix, interstitial is enabled for link or whole server.
+// Short URL can end with "+" itself, so exact match of id is checked first.
+// Link outside of active window is resolved to fallback URL if it's set
+func (s *ShortenerService) Resolve(ctx context.Context, id string) (models.LinkResolution, error) {
+	link, err := s.storage.GetLink(ctx, id)
+	if err != nil {
+		return models.LinkResolution{}, err
+	}
+
+	preview := false
+	if link == nil {
+		if !strings.HasSuffix(id, PreviewSuffix) {
+			return models.LinkResolution{}, nil
+		}
+
+		id = strings.TrimSuffix(id, PreviewSuffix)
+		link, err = s.storage.GetLink(ctx, id)
+		if err != nil {
+			return models.LinkResolution{}, err
+		}
+		if link == nil {
+			return models.LinkResolution{}, ErrLinkNotFound
+		}
+		preview = true
+	}
+
+	if link.DeletedFlag {
+		return models.LinkResolution{}, storage.NewAlreadyDeletedError(id)
+	}
+	if state := windowState(*link, time.Now()); state != "" {
+		if link.FallbackURL == "" {
+			return models.LinkResolution{}, windowError(state)
+		}
+		return models.LinkResolution{Link: s.preview(*link), Fallback: link.FallbackURL}, nil
+	}
+	if clicksExhausted(*link) {
+		return models.LinkResolution{}, storage.ErrClicksExhausted
+	}
+
+	return models.LinkResolution{
+		Link:           s.preview(*link),
+		Preview:        preview,
+		Interstitial:   link.Interstitial || s.Cfg.AlwaysInterstitial,
+		Protected:      link.PasswordHash != "",
+		Rules:          link.Rules,
+		Variants:       link.Variants,
+		StickyVariants: link.StickyVariants,
+		ForwardQuery:   link.ForwardQuery,
+		UTM:            link.UTM,
+	}, nil
+}
+
+// CountClick function for counting redirect by short URL, only exhausted clicks limit stops redirect,
+// other errors are logged
+func (s *ShortenerService) CountClick(ctx context.Context, id string) error {
+	err := s.storage.IncrementClicks(ctx, id)
+	if errors.Is(err, storage.ErrClicksExhausted) {
+		return err
+	}
+	if err != nil {
+		logger.Log.Debug("Ошибка подсчета переходов", zap.Error(err))
+	}
+	return nil
+}
+
+// clicksExhausted function checks if all redirects of link with clicks limit are used
+func clicksExhausted(link models.StorageURL) bool {
+	return link.MaxClicks > 0 && link.Clicks >= link.MaxClicks
+}
+
+// preview function converts stored URL to preview page data
+func (s *ShortenerService) preview(link models.StorageURL) models.LinkPreview {
+	preview := models.LinkPreview{
+		ID:          link.ShortURL,
+		ShortURL:    s.shortLink(link.ShortURL),
+		OriginalURL: link.OriginalURL,
+		Title:       link.Title,
+	}
+	// заголовок владельца ссылки важнее заголовка страницы
+	if link.Page != nil {
+		preview.Title = firstValue(preview.Title, link.Page.Title)
+		preview.Description, preview.Image = link.Page.Description, link.Page.Image
+	}
+	return preview
+}
@@ -0,0 +1,225 @@
+package shortenerservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/skip2/go-qrcode"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+)
+
+// Параметры QR-кодов
+const (
+	qrDefaultSize   = 256
+	qrMinSize       = 64
+	qrMaxSize       = 2048
+	qrDefaultLevel  = "M"
+	qrDefaultMargin = 4
+	qrMaxMargin     = 16
+	// qrCacheSize max count of QR code images kept in memory
+	qrCacheSize = 1024
+)
+
+// ErrInvalidQROptions QR code size, format, error correction level or margin is invalid
+var ErrInvalidQROptions = errors.New("некорректные параметры QR-кода")
+
+// qrLevels error correction levels of QR code by their names
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// qrCache structure for generated QR code images, the oldest image is removed if cache is full
+type qrCache struct {
+	mu    sync.Mutex
+	items map[string]models.QRCode
+	order []string
+}
+
+// get function returns cached QR code image by key
+func (c *qrCache) get(key string) (models.QRCode, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	code, ok := c.items[key]
+	return code, ok
+}
+
+// put function saves QR code image to cache
+func (c *qrCache) put(key string, code models.QRCode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.items == nil {
+		c.items = make(map[string]models.QRCode)
+	}
+	if _, ok := c.items[key]; ok {
+		return
+	}
+	if len(c.order) >= qrCacheSize {
+		delete(c.items, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.items[key] = code
+	c.order = append(c.order, key)
+}
+
+// QRCode function returns QR code image with full short URL, only existing not deleted links have QR code
+func (s *ShortenerService) QRCode(ctx context.Context, id string, opts models.QROptions) (models.QRCode, error) {
+	opts, err := qrOptions(opts)
+	if err != nil {
+		return models.QRCode{}, err
+	}
+
+	link, err := s.storage.GetLink(ctx, id)
+	if err != nil {
+		return models.QRCode{}, err
+	}
+	if link == nil {
+		return models.QRCode{}, ErrLinkNotFound
+	}
+	if link.DeletedFlag {
+		return models.QRCode{}, storage.NewAlreadyDeletedError(id)
+	}
+
+	shortURL := s.shortLink(link.ShortURL)
+	key := fmt.Sprintf("%s|%d|%s|%s|%d", shortURL, opts.Size, opts.Format, opts.Level, *opts.Margin)
+	if code, ok := s.qrCache.get(key); ok {
+		return code, nil
+	}
+
+	code, err := renderQRCode(shortURL, opts)
+	if err != nil {
+		return models.QRCode{}, err
+	}
+	s.qrCache.put(key, code)
+
+	return code, nil
+}
+
+// qrOptions function validates QR code options and sets defaults
+func qrOptions(opts models.QROptions) (models.QROptions, error) {
+	if opts.Size == 0 {
+		opts.Size = qrDefaultSize
+	}
+	if opts.Size < qrMinSize || opts.Size > qrMaxSize {
+		return opts, ErrInvalidQROptions
+	}
+
+	opts.Format = strings.ToLower(opts.Format)
+	if opts.Format == "" {
+		opts.Format = models.QRFormatPNG
+	}
+	if opts.Format != models.QRFormatPNG && opts.Format != models.QRFormatSVG {
+		return opts, ErrInvalidQROptions
+	}
+
+	opts.Level = strings.ToUpper(opts.Level)
+	if opts.Level == "" {
+		opts.Level = qrDefaultLevel
+	}
+	if _, ok := qrLevels[opts.Level]; !ok {
+		return opts, ErrInvalidQROptions
+	}
+
+	margin := qrDefaultMargin
+	if opts.Margin != nil {
+		margin = *opts.Margin
+	}
+	if margin < 0 || margin > qrMaxMargin {
+		return opts, ErrInvalidQROptions
+	}
+	opts.Margin = &margin
+
+	return opts, nil
+}
+
+// renderQRCode function encodes content to QR code and draws it as PNG or SVG image with margin
+func renderQRCode(content string, opts models.QROptions) (models.QRCode, error) {
+	q, err := qrcode.New(content, qrLevels[opts.Level])
+	if err != nil {
+		return models.QRCode{}, err
+	}
+	q.DisableBorder = true
+	modules := q.Bitmap()
+
+	var code models.QRCode
+	switch opts.Format {
+	case models.QRFormatSVG:
+		code = models.QRCode{Content: qrSVG(modules, opts.Size, *opts.Margin), ContentType: "image/svg+xml"}
+	default:
+		content, err := qrPNG(modules, opts.Size, *opts.Margin)
+		if err != nil {
+			return models.QRCode{}, err
+		}
+		code = models.QRCode{Content: content, ContentType: "image/png"}
+	}
+
+	sum := sha256.Sum256(code.Content)
+	code.ETag = `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	return code, nil
+}
+
+// qrPNG function draws QR code modules as PNG image of given size, modules are scaled by integer factor
+// and centered, so image is at least as large as QR code with margin
+func qrPNG(modules [][]bool, size int, margin int) ([]byte, error) {
+	total := len(modules) + 2*margin
+	scale := max(size/total, 1)
+	size = max(size, scale*total)
+	offset := (size - scale*len(modules)) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(offset+x*scale+dx, offset+y*scale+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// qrSVG function draws QR code modules as SVG image of given size, one path with horizontal runs of dark modules is used
+func qrSVG(modules [][]bool, size int, margin int) []byte {
+	total := len(modules) + 2*margin
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, total, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, total, total)
+	for y, row := range modules {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start+margin, y+margin, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
@@ -0,0 +1,126 @@
+package shortenerservice
+
+import (
+	"bytes"
+	"context"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/config"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func Test_qrOptions(t *testing.T) {
+	margin := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		opts    models.QROptions
+		want    models.QROptions
+		wantErr bool
+	}{
+		{name: "defaults", want: models.QROptions{Size: 256, Format: "png", Level: "M", Margin: margin(4)}},
+		{name: "case", opts: models.QROptions{Size: 512, Format: "SVG", Level: "h", Margin: margin(0)}, want: models.QROptions{Size: 512, Format: "svg", Level: "H", Margin: margin(0)}},
+		{name: "small", opts: models.QROptions{Size: 32}, wantErr: true},
+		{name: "large", opts: models.QROptions{Size: 4096}, wantErr: true},
+		{name: "negative size", opts: models.QROptions{Size: -256}, wantErr: true},
+		{name: "format", opts: models.QROptions{Format: "jpeg"}, wantErr: true},
+		{name: "level", opts: models.QROptions{Level: "X"}, wantErr: true},
+		{name: "negative margin", opts: models.QROptions{Margin: margin(-1)}, wantErr: true},
+		{name: "large margin", opts: models.QROptions{Margin: margin(17)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := qrOptions(tt.opts)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidQROptions)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_renderQRCode(t *testing.T) {
+	const content = "http://localhost:8080/6YGS4ZUF"
+	q, err := qrcode.New(content, qrcode.Highest)
+	require.NoError(t, err)
+	q.DisableBorder = true
+	modules := q.Bitmap()
+
+	t.Run("PNG", func(t *testing.T) {
+		margin := 2
+		code, err := renderQRCode(content, models.QROptions{Size: 300, Format: models.QRFormatPNG, Level: "H", Margin: &margin})
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", code.ContentType)
+		assert.NotEmpty(t, code.ETag)
+
+		img, err := png.Decode(bytes.NewReader(code.Content))
+		require.NoError(t, err)
+		require.Equal(t, 300, img.Bounds().Dx())
+		require.Equal(t, 300, img.Bounds().Dy())
+
+		// модули QR-кода отрисованы целым масштабом по центру изображения
+		scale := 300 / (len(modules) + 2*margin)
+		offset := (300 - scale*len(modules)) / 2
+		assert.GreaterOrEqual(t, offset, margin*scale)
+		for y, row := range modules {
+			for x, dark := range row {
+				r, _, _, _ := img.At(offset+x*scale+scale/2, offset+y*scale+scale/2).RGBA()
+				assert.Equal(t, dark, r == 0, "module %d,%d", x, y)
+			}
+		}
+		assert.Equal(t, color.RGBAModel.Convert(color.White), color.RGBAModel.Convert(img.At(offset-1, offset-1)))
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		margin := 0
+		code, err := renderQRCode(content, models.QROptions{Size: 128, Format: models.QRFormatSVG, Level: "H", Margin: &margin})
+		require.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", code.ContentType)
+
+		svg := string(code.Content)
+		assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="128" height="128"`))
+		assert.Contains(t, svg, `viewBox="0 0 33 33"`)
+		// левый верхний поисковый узор начинается с горизонтальной линии из 7 модулей
+		assert.Contains(t, svg, `d="M0 0h7v1h-7z`)
+	})
+}
+
+func TestShortenerService_QRCode(t *testing.T) {
+	cfg := &config.ConfigENV{BaseURL: "http://localhost:8080"}
+	storageURLs := storage.Storage{Storage: storage.NewCacheStorage()}
+	s := NewShortenerService(&storageURLs, cfg)
+
+	owner := uuid.Must(uuid.NewV4())
+	_, err := s.Shorten(context.Background(), "https://ya.ru", &owner)
+	require.NoError(t, err)
+
+	code, err := s.QRCode(context.Background(), "6YGS4ZUF", models.QROptions{})
+	require.NoError(t, err)
+	cached, err := s.QRCode(context.Background(), "6YGS4ZUF", models.QROptions{Format: "png", Level: "m"})
+	require.NoError(t, err)
+	assert.Equal(t, code, cached)
+
+	svg, err := s.QRCode(context.Background(), "6YGS4ZUF", models.QROptions{Format: "svg"})
+	require.NoError(t, err)
+	assert.NotEqual(t, code.ETag, svg.ETag)
+
+	_, err = s.QRCode(context.Background(), "unknown", models.QROptions{})
+	assert.ErrorIs(t, err, ErrLinkNotFound)
+
+	_, err = s.QRCode(context.Background(), "6YGS4ZUF", models.QROptions{Level: "Z"})
+	assert.ErrorIs(t, err, ErrInvalidQROptions)
+
+	require.NoError(t, storageURLs.DeleteUrlsBatch(context.Background(), &owner, []string{"6YGS4ZUF"}))
+	_, err = s.QRCode(context.Background(), "6YGS4ZUF", models.QROptions{})
+	var errDeleted *storage.AlreadyDeleted
+	assert.ErrorAs(t, err, &errDeleted)
+}
@@ -0,0 +1,130 @@
+package shortenerservice
+
+import (
+	"github.com/romanp1989/go-shortener/internal/models"
+	"net/url"
+	"strings"
+)
+
+// queryParam decoded query parameter, order of parameters is kept
+type queryParam struct {
+	key   string
+	value string
+}
+
+// redirectQuery function adds incoming query parameters and UTM parameters of link to destination URL.
+// Incoming parameters override UTM parameters, UTM parameters override parameters of destination with the same name
+func redirectQuery(destination string, res models.LinkResolution, client models.ClientInfo, variant string) string {
+	var params []queryParam
+	if res.UTM != nil {
+		params = utmParams(*res.UTM, res.Link.ID, variant)
+	}
+	if res.ForwardQuery {
+		incoming := parseQuery(client.Query)
+		params = append(withoutKeys(params, incoming), incoming...)
+	}
+	if len(params) == 0 {
+		return destination
+	}
+
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+	u.RawQuery = mergeQuery(u.RawQuery, params)
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+// mergeQuery function replaces parameters of raw query with params of the same name and appends others.
+// Untouched parameters keep their order and original encoding
+func mergeQuery(rawQuery string, params []queryParam) string {
+	replaced := make(map[string]bool, len(params))
+	for _, param := range params {
+		replaced[param.key] = true
+	}
+
+	parts := make([]string, 0)
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		rawKey, _, _ := strings.Cut(part, "=")
+		// параметр с некорректным экранированием сохраняется как есть
+		if key, err := url.QueryUnescape(rawKey); err == nil && replaced[key] {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	for _, param := range params {
+		parts = append(parts, url.QueryEscape(param.key)+"="+url.QueryEscape(param.value))
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// parseQuery function decodes raw query into parameters in original order, parameters with invalid escaping are skipped
+func parseQuery(rawQuery string) []queryParam {
+	params := make([]queryParam, 0)
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		rawKey, rawValue, _ := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil || key == "" {
+			continue
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			continue
+		}
+		params = append(params, queryParam{key: key, value: value})
+	}
+	return params
+}
+
+// withoutKeys function removes params with names from other list
+func withoutKeys(params []queryParam, other []queryParam) []queryParam {
+	keys := make(map[string]bool, len(other))
+	for _, param := range other {
+		keys[param.key] = true
+	}
+
+	result := make([]queryParam, 0, len(params))
+	for _, param := range params {
+		if !keys[param.key] {
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+// utmParams function returns set UTM parameters of link, {id} and {variant} placeholders are replaced
+func utmParams(utm models.UTMParams, id string, variant string) []queryParam {
+	replacer := strings.NewReplacer("{id}", id, "{variant}", variant)
+
+	params := make([]queryParam, 0, 5)
+	for _, param := range []queryParam{
+		{key: "utm_source", value: utm.Source},
+		{key: "utm_medium", value: utm.Medium},
+		{key: "utm_campaign", value: utm.Campaign},
+		{key: "utm_term", value: utm.Term},
+		{key: "utm_content", value: utm.Content},
+	} {
+		if param.value != "" {
+			param.value = replacer.Replace(param.value)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// normalizeUTM function returns nil for UTM parameters without values
+func normalizeUTM(utm *models.UTMParams) *models.UTMParams {
+	if utm == nil || *utm == (models.UTMParams{}) {
+		return nil
+	}
+	return utm
+}
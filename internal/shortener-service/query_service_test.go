@@ -0,0 +1,148 @@
+package shortenerservice
+
+import (
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_redirectQuery(t *testing.T) {
+	utm := &models.UTMParams{Source: "news", Medium: "email", Campaign: "{id}-{variant}"}
+
+	tests := []struct {
+		name         string
+		destination  string
+		forwardQuery bool
+		utm          *models.UTMParams
+		query        string
+		variant      string
+		want         string
+	}{
+		{
+			name:        "no options",
+			destination: "https://example.com/path?a=1",
+			query:       "b=2",
+			want:        "https://example.com/path?a=1",
+		},
+		{
+			name:         "forward to URL without query",
+			destination:  "https://example.com/path",
+			forwardQuery: true,
+			query:        "a=1&b=2",
+			want:         "https://example.com/path?a=1&b=2",
+		},
+		{
+			name:         "empty incoming query",
+			destination:  "https://example.com/path?a=1",
+			forwardQuery: true,
+			want:         "https://example.com/path?a=1",
+		},
+		{
+			name:         "destination parameters keep order and encoding",
+			destination:  "https://example.com/path?x=%2Fhome&y=a+b&z=%7E",
+			forwardQuery: true,
+			query:        "w=1",
+			want:         "https://example.com/path?x=%2Fhome&y=a+b&z=%7E&w=1",
+		},
+		{
+			name:         "incoming parameter overrides destination",
+			destination:  "https://example.com/path?a=1&b=2&a=3",
+			forwardQuery: true,
+			query:        "a=9",
+			want:         "https://example.com/path?b=2&a=9",
+		},
+		{
+			name:         "repeated incoming parameters",
+			destination:  "https://example.com/path?tag=old",
+			forwardQuery: true,
+			query:        "tag=a&tag=b",
+			want:         "https://example.com/path?tag=a&tag=b",
+		},
+		{
+			name:         "fragment is kept after query",
+			destination:  "https://example.com/path?x=1#section",
+			forwardQuery: true,
+			query:        "y=2",
+			want:         "https://example.com/path?x=1&y=2#section",
+		},
+		{
+			name:         "encoded incoming values",
+			destination:  "https://example.com/path",
+			forwardQuery: true,
+			query:        "q=hello+world&name=%D0%9F%D1%80%D0%B8&amp=%26&eq=a%3Db&plus=%2B",
+			want:         "https://example.com/path?q=hello+world&name=%D0%9F%D1%80%D0%B8&amp=%26&eq=a%3Db&plus=%2B",
+		},
+		{
+			name:         "encoded key matches destination key",
+			destination:  "https://example.com/path?a+b=1&c=2",
+			forwardQuery: true,
+			query:        "a%20b=3",
+			want:         "https://example.com/path?c=2&a+b=3",
+		},
+		{
+			name:         "invalid escaping and empty parts are skipped",
+			destination:  "https://example.com/path?keep=%zz",
+			forwardQuery: true,
+			query:        "bad=%zz&&=1&ok=1&flag",
+			want:         "https://example.com/path?keep=%zz&ok=1&flag=",
+		},
+		{
+			name:         "semicolon isn't separator",
+			destination:  "https://example.com/path",
+			forwardQuery: true,
+			query:        "a=1;b=2",
+			want:         "https://example.com/path?a=1%3Bb%3D2",
+		},
+		{
+			name:        "empty destination query",
+			destination: "https://example.com/path?",
+			utm:         &models.UTMParams{Source: "news"},
+			want:        "https://example.com/path?utm_source=news",
+		},
+		{
+			name:        "utm parameters with placeholders",
+			destination: "https://example.com/path?a=1",
+			utm:         utm,
+			variant:     "v1",
+			want:        "https://example.com/path?a=1&utm_source=news&utm_medium=email&utm_campaign=6YGS4ZUF-v1",
+		},
+		{
+			name:        "utm parameters override destination",
+			destination: "https://example.com/path?utm_source=site&a=1",
+			utm:         &models.UTMParams{Source: "news letter"},
+			want:        "https://example.com/path?a=1&utm_source=news+letter",
+		},
+		{
+			name:         "incoming parameters override utm parameters",
+			destination:  "https://example.com/path",
+			forwardQuery: true,
+			utm:          utm,
+			query:        "utm_source=twitter&ref=1",
+			want:         "https://example.com/path?utm_medium=email&utm_campaign=6YGS4ZUF-&utm_source=twitter&ref=1",
+		},
+		{
+			name:         "invalid destination isn't changed",
+			destination:  "://example.com",
+			forwardQuery: true,
+			query:        "a=1",
+			want:         "://example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := models.LinkResolution{
+				Link:         models.LinkPreview{ID: "6YGS4ZUF"},
+				ForwardQuery: tt.forwardQuery,
+				UTM:          tt.utm,
+			}
+			got := redirectQuery(tt.destination, res, models.ClientInfo{Query: tt.query}, tt.variant)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_normalizeUTM(t *testing.T) {
+	assert.Nil(t, normalizeUTM(nil))
+	assert.Nil(t, normalizeUTM(&models.UTMParams{}))
+	assert.Equal(t, &models.UTMParams{Term: "go"}, normalizeUTM(&models.UTMParams{Term: "go"}))
+}
@@ -0,0 +1,136 @@
+package shortenerservice
+
+import (
+	"errors"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxRedirectRules max count of redirect rules of link
+const maxRedirectRules = 20
+
+// ErrInvalidRedirectRule redirect rule has invalid condition or URL
+var ErrInvalidRedirectRule = errors.New("некорректное правило перенаправления")
+
+// Destination function returns URL of the first redirect rule matched by client.
+// If no rule is matched A/B variant is chosen by weight, original URL is used by default.
+// Incoming query and UTM parameters of link are added to chosen URL
+func (s *ShortenerService) Destination(res models.LinkResolution, client models.ClientInfo) models.RedirectTarget {
+	target := destination(res, client)
+	if target.URL != "" {
+		target.URL = redirectQuery(target.URL, res, client, target.Variant)
+	}
+	return target
+}
+
+// destination function chooses destination of link by redirect rules and A/B variants
+func destination(res models.LinkResolution, client models.ClientInfo) models.RedirectTarget {
+	if len(res.Rules) > 0 {
+		devices := deviceClasses(client.UserAgent)
+		language := preferredLanguage(client.AcceptLanguage)
+		for _, rule := range res.Rules {
+			if matchRule(rule, devices, language, client.Country) {
+				return models.RedirectTarget{URL: rule.URL}
+			}
+		}
+	}
+
+	if len(res.Variants) > 0 {
+		variant := chooseVariant(res.Variants, res.StickyVariants, client.Variant)
+		return models.RedirectTarget{URL: variant.URL, Variant: variant.ID}
+	}
+
+	return models.RedirectTarget{URL: res.Link.OriginalURL}
+}
+
+// matchRule function checks if all set conditions of rule match client
+func matchRule(rule models.RedirectRule, devices []string, language string, country string) bool {
+	if rule.Device != "" && !slices.Contains(devices, rule.Device) {
+		return false
+	}
+	if rule.Language != "" && !matchLanguage(rule.Language, language) {
+		return false
+	}
+	if rule.Country != "" && !strings.EqualFold(rule.Country, strings.TrimSpace(country)) {
+		return false
+	}
+	return true
+}
+
+// deviceClasses function returns all device classes of client by User-Agent
+func deviceClasses(userAgent string) []string {
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"), strings.Contains(userAgent, "iPod"):
+		return []string{models.DeviceIOS, models.DeviceMobile}
+	case strings.Contains(userAgent, "Android"):
+		return []string{models.DeviceAndroid, models.DeviceMobile}
+	case strings.Contains(userAgent, "Mobile"):
+		return []string{models.DeviceMobile}
+	}
+	return []string{models.DeviceDesktop}
+}
+
+// preferredLanguage function returns language tag with the highest quality from Accept-Language header
+func preferredLanguage(acceptLanguage string) string {
+	preferred, best := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		// при равном качестве побеждает язык, указанный раньше
+		if quality > best {
+			preferred, best = tag, quality
+		}
+	}
+
+	return strings.ToLower(preferred)
+}
+
+// matchLanguage function compares rule language with client language, rule without region matches any region
+func matchLanguage(ruleLanguage string, language string) bool {
+	ruleLanguage = strings.ToLower(ruleLanguage)
+	if strings.Contains(ruleLanguage, "-") {
+		return ruleLanguage == language
+	}
+	primary, _, _ := strings.Cut(language, "-")
+	return ruleLanguage == primary
+}
+
+// validateRules function checks conditions and URLs of redirect rules
+func validateRules(rules []models.RedirectRule) error {
+	if len(rules) > maxRedirectRules {
+		return ErrInvalidRedirectRule
+	}
+
+	for _, rule := range rules {
+		if rule.Device == "" && rule.Language == "" && rule.Country == "" {
+			return ErrInvalidRedirectRule
+		}
+		switch rule.Device {
+		case "", models.DeviceIOS, models.DeviceAndroid, models.DeviceMobile, models.DeviceDesktop:
+		default:
+			return ErrInvalidRedirectRule
+		}
+		if rule.Country != "" && len(rule.Country) != 2 {
+			return ErrInvalidRedirectRule
+		}
+		if _, err := url.ParseRequestURI(rule.URL); err != nil {
+			return ErrInvalidRedirectRule
+		}
+	}
+
+	return nil
+}
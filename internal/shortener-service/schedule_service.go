@@ -0,0 +1,93 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"net/url"
+	"time"
+)
+
+// ErrLinkNotActive active window of link isn't started
+var ErrLinkNotActive = errors.New("ссылка еще не активна")
+
+// ErrLinkWindowEnded active window of link is ended
+var ErrLinkWindowEnded = errors.New("срок действия ссылки истек")
+
+// ErrInvalidActiveWindow active window bounds can't be parsed or window end isn't after start
+var ErrInvalidActiveWindow = errors.New("некорректное окно активности active_from, active_until")
+
+// GetScheduledLinks function for admin list of not deleted links with active window of all users
+func (s *ShortenerService) GetScheduledLinks(ctx context.Context) ([]models.ScheduledLink, error) {
+	stored, err := s.storage.GetScheduledLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	links := make([]models.ScheduledLink, 0, len(stored))
+	for _, link := range stored {
+		state := windowState(link, now)
+		if state == "" {
+			state = models.LinkStateActive
+		}
+
+		links = append(links, models.ScheduledLink{
+			ID:          link.ShortURL,
+			ShortURL:    s.shortLink(link.ShortURL),
+			OriginalURL: link.OriginalURL,
+			UserID:      link.UserID,
+			State:       state,
+			ActiveFrom:  link.ActiveFrom,
+			ActiveUntil: link.ActiveUntil,
+			FallbackURL: link.FallbackURL,
+		})
+	}
+
+	return links, nil
+}
+
+// windowState function returns scheduled or expired state of link outside of active window, empty state means link is in window
+func windowState(link models.StorageURL, now time.Time) string {
+	if link.ActiveFrom != nil && now.Before(*link.ActiveFrom) {
+		return models.LinkStateScheduled
+	}
+	if link.ActiveUntil != nil && !now.Before(*link.ActiveUntil) {
+		return models.LinkStateExpired
+	}
+	return ""
+}
+
+// windowError function returns error for hit of link outside of active window
+func windowError(state string) error {
+	if state == models.LinkStateScheduled {
+		return ErrLinkNotActive
+	}
+	return ErrLinkWindowEnded
+}
+
+// validateSchedule function checks active window and fallback URL of link
+func validateSchedule(link models.StorageURL) error {
+	if link.ActiveFrom != nil && link.ActiveUntil != nil && !link.ActiveUntil.After(*link.ActiveFrom) {
+		return ErrInvalidActiveWindow
+	}
+	if link.FallbackURL != "" {
+		if _, err := url.ParseRequestURI(link.FallbackURL); err != nil {
+			return ErrInvalidURL
+		}
+	}
+	return nil
+}
+
+// parseWindowBound function parses RFC 3339 bound of active window from update request, empty string removes bound
+func parseWindowBound(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	bound, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, ErrInvalidActiveWindow
+	}
+	return &bound, nil
+}
@@ -3,8 +3,8 @@ package shortenerservice
 import (
 	"context"
 	"crypto/md5"
+	"crypto/rand"
 	"encoding/base64"
-	"fmt"
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 	"github.com/romanp1989/go-shortener/internal/auth"
@@ -13,17 +13,67 @@ import (
 	"github.com/romanp1989/go-shortener/internal/models"
 	"github.com/romanp1989/go-shortener/internal/storage"
 	"go.uber.org/zap"
-	"net/url"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
+// randomCodeLen length of random short code in bytes, encoded code has 8 symbols as code derived from URL
+const randomCodeLen = 6
+
+// maxRandomCodeAttempts count of attempts to save link with new random code if code is taken
+const maxRandomCodeAttempts = 5
+
+// ErrInvalidURL original URL can't be parsed
+var ErrInvalidURL = errors.New("некорректный URL")
+
+// ErrUnauthorized user isn't found in context
+var ErrUnauthorized = errors.New("пользователь не авторизован")
+
 type ShortenerService struct {
 	storage *storage.Storage
 	Cfg     *config.ConfigENV
 
+	// domains configured domains of short links
+	domains shortDomains
+
 	inChan    chan itemDelete
 	closeChan chan struct{}
 	size      int
+
+	// inflight idempotency keys of requests in progress
+	inflight sync.Map
+
+	// attempts wrong passwords of password-protected links by link and client
+	attemptsMu sync.Mutex
+	attempts   map[string]passwordAttempts
+
+	// anonymous user ids checked to have no account, time of check
+	anonymousMu sync.Mutex
+	anonymous   map[uuid.UUID]time.Time
+
+	// schemes allowed schemes of destination URLs, default schemes are allowed if empty
+	schemes []string
+	// policy domain lists of URL policy file
+	policy policyFile
+
+	// checkClient HTTP client of destinations checker, internal addresses are refused
+	checkClient *http.Client
+
+	// qrCache generated QR code images
+	qrCache qrCache
+
+	// pageQueue links waiting for destination page fetch, nil if fetcher is disabled
+	pageQueue chan pageFetch
+	// pageClient HTTP client of destination pages fetcher, internal addresses are refused
+	pageClient *http.Client
+
+	// jwt creates and parses auth tokens of login sessions
+	jwt *auth.JWTService
+
+	// workers background workers started by Start
+	workers sync.WaitGroup
 }
 
 func NewShortenerService(storage *storage.Storage, cfg *config.ConfigENV) *ShortenerService {
@@ -33,15 +83,68 @@ func NewShortenerService(storage *storage.Storage, cfg *config.ConfigENV) *Short
 		inChan:    make(chan itemDelete, 100),
 		closeChan: make(chan struct{}),
 		size:      100,
+		attempts:  make(map[string]passwordAttempts),
+		anonymous: make(map[uuid.UUID]time.Time),
+		domains:   parseDomains(cfg.BaseURL, cfg.Domains),
+		schemes:   parseSchemes(cfg.AllowedSchemes),
+		jwt:       auth.NewJwtService(cfg.SecretKey),
+
+		checkClient: newDestinationClient(linkCheckTimeout),
+		pageClient:  newDestinationClient(pageFetchTimeout),
 	}
 
 	for i := 0; i < 2; i++ {
 		go service.process()
 	}
 
+	if cfg.URLPolicyFile != "" {
+		if err := service.LoadURLPolicy(); err != nil {
+			logger.Log.Error("Ошибка загрузки политики URL", zap.Error(err))
+		}
+	}
+
+	if cfg.FetchPageMetadata && cfg.PageFetchWorkers > 0 {
+		service.pageQueue = make(chan pageFetch, pageFetchQueueSize)
+	}
+
 	return service
 }
 
+// Start function starts background workers of service: URL policy reload, links destinations checker,
+// destination pages fetchers and cleanup of password attempts, checked anonymous users and expired login sessions.
+// It's called once per process, workers are stopped when ctx is done
+func (s *ShortenerService) Start(ctx context.Context) {
+	s.goWorker(func() { s.runAttemptsPruner(ctx) })
+
+	if s.Cfg.URLPolicyFile != "" && s.Cfg.URLPolicyReload > 0 {
+		s.goWorker(func() { s.watchURLPolicy(ctx) })
+	}
+
+	if s.Cfg.LinkCheckInterval > 0 {
+		s.goWorker(func() { s.runLinkChecker(ctx) })
+	}
+
+	if s.pageQueue != nil {
+		for i := 0; i < s.Cfg.PageFetchWorkers; i++ {
+			s.goWorker(func() { s.runPageFetcher(ctx) })
+		}
+	}
+}
+
+// Wait function waits until background workers are stopped after ctx of Start is done
+func (s *ShortenerService) Wait() {
+	s.workers.Wait()
+}
+
+// goWorker function runs background worker in goroutine
+func (s *ShortenerService) goWorker(fn func()) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		fn()
+	}()
+}
+
 // ShortURL function for generate short name for URL
 func (s *ShortenerService) ShortURL(url string) string {
 	sum := md5.Sum([]byte(url))
@@ -55,11 +158,19 @@ func (s *ShortenerService) ShortURL(url string) string {
 func (s *ShortenerService) Encode(ctx context.Context, originalURL string) (string, error) {
 	userID := auth.UIDFromContext(ctx)
 	if userID == nil {
+		return "", ErrUnauthorized
+	}
 
-		return "", errors.Errorf("User unauthorized")
+	originalURL, err := s.normalizeURL(originalURL)
+	if err != nil {
+		return "", err
+	}
+	domain, err := s.newLinkDomain(ctx, userID, "")
+	if err != nil {
+		return "", err
 	}
 
-	hashID := s.ShortURL(originalURL)
+	hashID := linkKey(domain, s.ShortURL(originalURL))
 	shortID, err := s.storage.SaveURL(ctx, originalURL, hashID, userID)
 	if err != nil {
 		logger.Log.Debug("Ошибка добавления данных", zap.Error(err))
@@ -67,14 +178,15 @@ func (s *ShortenerService) Encode(ctx context.Context, originalURL string) (stri
 		var errConflict *storage.URLConflictError
 		if errors.As(err, &errConflict) {
 			shortID = errConflict.URL
-			resp := fmt.Sprintf("%s/%s", s.Cfg.BaseURL, shortID)
+			resp := s.shortLink(shortID)
 			return resp, err
 		} else {
 			return "", err
 		}
 	}
 
-	resp := fmt.Sprintf("%s/%s", s.Cfg.BaseURL, shortID)
+	s.enqueuePageFetch(shortID, originalURL)
+	resp := s.shortLink(shortID)
 
 	return resp, nil
 }
@@ -86,59 +198,141 @@ func (s *ShortenerService) Decode(id string) (string, error) {
 		return "", err
 	}
 
+	if fullURL != "" {
+		if err = s.CountClick(context.Background(), id); err != nil {
+			return "", err
+		}
+	}
+
 	return fullURL, nil
 }
 
 // Shorten handler for creating a shortened URL based on the original one
 func (s *ShortenerService) Shorten(ctx context.Context, originalURL string, userID *uuid.UUID) (string, error) {
-	hashID := s.ShortURL(originalURL)
-	shortID, err := s.storage.SaveURL(ctx, originalURL, hashID, userID)
+	return s.ShortenLink(ctx, models.StorageURL{
+		UserID:      userID,
+		OriginalURL: originalURL,
+	})
+}
+
+// ShortenLink function for creating a shortened URL with title, notes and tags.
+// Link is created on requested domain or on user's default domain
+func (s *ShortenerService) ShortenLink(ctx context.Context, link models.StorageURL) (string, error) {
+	originalURL, err := s.normalizeURL(link.OriginalURL)
+	if err != nil {
+		return "", err
+	}
+	if link.Domain, err = s.newLinkDomain(ctx, link.UserID, link.Domain); err != nil {
+		return "", err
+	}
+
+	link.OriginalURL = originalURL
+	shortID, err := s.saveLink(ctx, link)
 	if err != nil {
 		var errConflict *storage.URLConflictError
 		if errors.As(err, &errConflict) {
-			return fmt.Sprintf("%s/%s", s.Cfg.BaseURL, shortID), err
+			return s.shortLink(errConflict.URL), err
 		} else {
 			return "", err
 		}
 	}
 
-	shortURL := fmt.Sprintf("%s/%s", s.Cfg.BaseURL, shortID)
+	s.enqueuePageFetch(shortID, link.OriginalURL)
+	shortURL := s.shortLink(shortID)
 
 	return shortURL, nil
 }
 
-// SaveBatch handler for creating a shortened URL based on the original one
+// saveLink function saves link with code derived from original URL, so the same URL gets the same link.
+// Link with clicks limit or password gets random code, it isn't returned to other users shortening the same URL
+func (s *ShortenerService) saveLink(ctx context.Context, link models.StorageURL) (string, error) {
+	if !link.Private() {
+		link.ShortURL = linkKey(link.Domain, s.ShortURL(link.OriginalURL))
+		return s.storage.SaveLink(ctx, link)
+	}
+
+	for attempt := 1; ; attempt++ {
+		code, err := randomShortURL()
+		if err != nil {
+			return "", err
+		}
+
+		link.ShortURL = linkKey(link.Domain, code)
+		shortID, err := s.storage.SaveLink(ctx, link)
+		var errConflict *storage.URLConflictError
+		if err == nil || !errors.As(err, &errConflict) || attempt == maxRandomCodeAttempts {
+			return shortID, err
+		}
+	}
+}
+
+// randomShortURL function returns random short code
+func randomShortURL() (string, error) {
+	code := make([]byte, randomCodeLen)
+	if _, err := rand.Read(code); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(code), nil
+}
+
+// SaveBatch handler for creating a shortened URL based on the original one.
+// Every item gets its own status, valid items are saved even if other items are invalid
 func (s *ShortenerService) SaveBatch(ctx context.Context, batchReq []models.BatchShortenRequest, userID *uuid.UUID) ([]models.BatchShortenResponse, error) {
-	var err error
+	res := make([]models.BatchShortenResponse, len(batchReq))
+	positions := make(map[string][]int, len(batchReq))
+
+	domain, err := s.newLinkDomain(ctx, userID, "")
+	if err != nil {
+		return []models.BatchShortenResponse{}, err
+	}
 
 	var shortURLs []models.StorageURL
-	var hashID string
 
-	for _, value := range batchReq {
-		if _, err = url.ParseRequestURI(value.OriginalURL); err != nil {
-			//w.WriteHeader(http.StatusBadRequest)
-			return []models.BatchShortenResponse{}, err
+	for i, value := range batchReq {
+		res[i].CorrelationID = value.CorrelationID
+
+		originalURL, err := s.normalizeURL(value.OriginalURL)
+		if err != nil {
+			res[i].Status, res[i].Error = models.BatchStatusInvalid, err.Error()
+			continue
 		}
+		value.OriginalURL = originalURL
 
-		hashID, err = s.storage.GetURL(value.OriginalURL)
+		// повторы URL в пакете получают результат первого вхождения
+		if first, ok := positions[value.OriginalURL]; ok {
+			res[i].Status, res[i].ShortURL = res[first[0]].Status, res[first[0]].ShortURL
+			positions[value.OriginalURL] = append(first, i)
+			continue
+		}
+
+		hashID := linkKey(domain, s.ShortURL(value.OriginalURL))
+		savedURL, err := s.storage.GetURL(hashID)
 		if err != nil {
+			var errURLDeleted *storage.AlreadyDeleted
+			if errors.As(err, &errURLDeleted) {
+				res[i].Status, res[i].Error = models.BatchStatusInvalid, "URL удален"
+				continue
+			}
 			logger.Log.Debug("error get url response", zap.Error(err))
-			//w.WriteHeader(http.StatusBadRequest)
 			return []models.BatchShortenResponse{}, err
 		}
+		positions[value.OriginalURL] = []int{i}
 
-		if hashID == "" {
-			hashID = s.ShortURL(value.OriginalURL)
-			shortURLs = append(shortURLs, models.StorageURL{
-				OriginalURL: value.OriginalURL,
-				ShortURL:    hashID,
-			})
-		} else {
-			shortURLs = append(shortURLs, models.StorageURL{
-				OriginalURL: value.OriginalURL,
-				ShortURL:    hashID,
-			})
+		if savedURL != "" {
+			res[i].Status = models.BatchStatusExisting
+			res[i].ShortURL = s.shortLink(hashID)
+			continue
 		}
+
+		res[i].Status = models.BatchStatusCreated
+		shortURLs = append(shortURLs, models.StorageURL{
+			OriginalURL: value.OriginalURL,
+			ShortURL:    hashID,
+		})
+	}
+
+	if len(shortURLs) == 0 {
+		return res, nil
 	}
 
 	urls, err := s.storage.SaveBatchURL(ctx, shortURLs, userID)
@@ -147,13 +341,11 @@ func (s *ShortenerService) SaveBatch(ctx context.Context, batchReq []models.Batc
 		return []models.BatchShortenResponse{}, err
 	}
 
-	res := make([]models.BatchShortenResponse, 0, len(urls))
-
 	for i, shortURL := range urls {
-		res = append(res, models.BatchShortenResponse{
-			CorrelationID: batchReq[i].CorrelationID,
-			ShortURL:      fmt.Sprintf("%s/%s", s.Cfg.BaseURL, shortURL),
-		})
+		for _, position := range positions[shortURLs[i].OriginalURL] {
+			res[position].ShortURL = s.shortLink(shortURL)
+		}
+		s.enqueuePageFetch(shortURL, shortURLs[i].OriginalURL)
 	}
 
 	return res, nil
@@ -2,25 +2,86 @@ package shortenerservice
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"github.com/gofrs/uuid"
 	"github.com/romanp1989/go-shortener/internal/models"
+	"strings"
 )
 
+// searchLimit max count of URLs in search response
+const searchLimit = 100
+
+// ErrEmptySearchQuery search query is empty
+var ErrEmptySearchQuery = errors.New("пустой поисковый запрос")
+
 // GetURLs function for creating a shortened URL based on the original one
-func (s *ShortenerService) GetURLs(ctx context.Context, userID *uuid.UUID) ([]models.StorageURL, error) {
+func (s *ShortenerService) GetURLs(ctx context.Context, userID *uuid.UUID) ([]models.UserURL, error) {
 	urls, err := s.storage.GetAllUrlsByUser(ctx, userID)
 	if err != nil {
-		return []models.StorageURL{}, err
+		return []models.UserURL{}, err
 	}
 
-	allUrls := make([]models.StorageURL, 0, len(urls))
+	allUrls := make([]models.UserURL, 0, len(urls))
 	for _, v := range urls {
-		var store models.StorageURL
-		store.ShortURL = fmt.Sprintf("%s/%s", s.Cfg.BaseURL, v.ShortURL)
-		store.OriginalURL = v.OriginalURL
-		allUrls = append(allUrls, store)
+		allUrls = append(allUrls, s.listURL(v))
 	}
 
 	return allUrls, nil
 }
+
+// listURL function converts stored URL to item of user's URLs list with full short URL.
+// List of API v1 isn't changed, metadata of links is returned by API v2 only
+func (s *ShortenerService) listURL(v models.StorageURL) models.UserURL {
+	return models.UserURL{
+		OriginalURL: v.OriginalURL,
+		ShortURL:    s.shortLink(v.ShortURL),
+	}
+}
+
+// SearchURLs function for full-text search in user's URLs, results are ordered by rank
+func (s *ShortenerService) SearchURLs(ctx context.Context, userID *uuid.UUID, query string) ([]models.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	results, err := s.storage.Search(ctx, userID, query, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].ShortURL = s.shortLink(results[i].ShortURL)
+	}
+
+	return results, nil
+}
+
+// ExportURLs function calls fn for every user's URL with full short URL
+func (s *ShortenerService) ExportURLs(ctx context.Context, userID *uuid.UUID, fn func(models.ExportURL) error) error {
+	return s.exportURLs(ctx, userID, func(models.StorageURL) bool { return true }, fn)
+}
+
+// exportURLs function calls fn for every user's URL matching filter
+func (s *ShortenerService) exportURLs(ctx context.Context, userID *uuid.UUID, filter func(models.StorageURL) bool, fn func(models.ExportURL) error) error {
+	return s.storage.IterateUrlsByUser(ctx, userID, func(link models.StorageURL) error {
+		if !filter(link) {
+			return nil
+		}
+
+		tags := link.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		return fn(models.ExportURL{
+			ShortURL:    s.shortLink(link.ShortURL),
+			OriginalURL: link.OriginalURL,
+			Title:       link.Title,
+			Notes:       link.Notes,
+			Tags:        tags,
+			IsDeleted:   link.DeletedFlag,
+			CreatedAt:   link.CreatedAt,
+		})
+	})
+}
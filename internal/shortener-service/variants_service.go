@@ -0,0 +1,88 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/romanp1989/go-shortener/internal/logger"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"go.uber.org/zap"
+	"math/rand"
+	"net/url"
+	"time"
+)
+
+// maxVariants max count of A/B variants of link
+const maxVariants = 10
+
+// maxVariantWeight max weight of A/B variant
+const maxVariantWeight = 1000
+
+// maxVariantIDLength max length of A/B variant id
+const maxVariantIDLength = 64
+
+// VariantCookieTTL lifetime of cookie with assigned A/B variant of sticky link
+const VariantCookieTTL = 30 * 24 * time.Hour
+
+// ErrInvalidVariants A/B variants have invalid weight, URL or duplicate id
+var ErrInvalidVariants = errors.New("некорректные варианты перенаправления")
+
+// CountVariantClick function for counting redirect to A/B variant, error is logged only and doesn't stop redirect
+func (s *ShortenerService) CountVariantClick(ctx context.Context, id string, variantID string) {
+	if err := s.storage.IncrementVariantClicks(ctx, id, variantID); err != nil {
+		logger.Log.Debug("Ошибка подсчета переходов варианта", zap.Error(err))
+	}
+}
+
+// chooseVariant function returns A/B variant by weight, variant assigned to client before is kept for sticky link
+func chooseVariant(variants []models.Variant, sticky bool, assigned string) models.Variant {
+	total := 0
+	for _, variant := range variants {
+		if sticky && variant.ID == assigned {
+			return variant
+		}
+		total += variant.Weight
+	}
+
+	n := rand.Intn(total)
+	for _, variant := range variants {
+		if n < variant.Weight {
+			return variant
+		}
+		n -= variant.Weight
+	}
+	return variants[len(variants)-1]
+}
+
+// normalizeVariants function validates A/B variants and sets missing ids by position, clicks of request are ignored
+func normalizeVariants(variants []models.Variant) ([]models.Variant, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	if len(variants) > maxVariants {
+		return nil, ErrInvalidVariants
+	}
+
+	ids := make(map[string]bool, len(variants))
+	normalized := make([]models.Variant, 0, len(variants))
+	for i, variant := range variants {
+		if variant.ID == "" {
+			variant.ID = fmt.Sprintf("v%d", i+1)
+		}
+		if len(variant.ID) > maxVariantIDLength || ids[variant.ID] {
+			return nil, ErrInvalidVariants
+		}
+		if variant.Weight <= 0 || variant.Weight > maxVariantWeight {
+			return nil, ErrInvalidVariants
+		}
+		if _, err := url.ParseRequestURI(variant.URL); err != nil {
+			return nil, ErrInvalidVariants
+		}
+
+		ids[variant.ID] = true
+		variant.Clicks = 0
+		normalized = append(normalized, variant)
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,325 @@
+package shortenerservice
+
+import (
+	"context"
+	"errors"
+	"github.com/gofrs/uuid"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/romanp1989/go-shortener/internal/storage"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// workspaceNameLimit max length of workspace name in runes
+const workspaceNameLimit = 100
+
+// ErrWorkspaceNotFound workspace isn't found or user isn't its member
+var ErrWorkspaceNotFound = errors.New("рабочее пространство не найдено")
+
+// ErrInvalidWorkspace workspace name is empty or too long
+var ErrInvalidWorkspace = errors.New("некорректное название рабочего пространства")
+
+// ErrInvalidWorkspaceRole role of workspace member is unknown
+var ErrInvalidWorkspaceRole = errors.New("некорректная роль участника: owner, editor или viewer")
+
+// ErrWorkspaceForbidden user's role in workspace doesn't allow action
+var ErrWorkspaceForbidden = errors.New("недостаточно прав в рабочем пространстве")
+
+// ErrLastWorkspaceOwner the only owner of workspace can't leave it or lose owner role
+var ErrLastWorkspaceOwner = errors.New("в рабочем пространстве должен остаться владелец")
+
+// workspaceRoleRanks rank of workspace roles, action is allowed for role with rank not less than required one
+var workspaceRoleRanks = map[string]int{
+	models.WorkspaceRoleViewer: 1,
+	models.WorkspaceRoleEditor: 2,
+	models.WorkspaceRoleOwner:  3,
+}
+
+// CreateWorkspace function for creating workspace, user becomes its owner
+func (s *ShortenerService) CreateWorkspace(ctx context.Context, userID *uuid.UUID, req models.WorkspaceRequest) (models.Workspace, error) {
+	workspace := models.Workspace{
+		Name:      strings.TrimSpace(req.Name),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := validateWorkspace(workspace); err != nil {
+		return models.Workspace{}, err
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return models.Workspace{}, err
+	}
+	workspace.ID = id.String()
+
+	if err = s.storage.SaveWorkspace(ctx, workspace); err != nil {
+		return models.Workspace{}, err
+	}
+	member := models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: userID, Role: models.WorkspaceRoleOwner}
+	if err = s.storage.SaveWorkspaceMember(ctx, member); err != nil {
+		return models.Workspace{}, err
+	}
+
+	workspace.Role = models.WorkspaceRoleOwner
+	return workspace, nil
+}
+
+// ListWorkspaces function for getting workspaces of user with user's role
+func (s *ShortenerService) ListWorkspaces(ctx context.Context, userID *uuid.UUID) ([]models.Workspace, error) {
+	return s.storage.GetUserWorkspaces(ctx, userID)
+}
+
+// GetWorkspace function for getting workspace by ID with user's role, user must be its member
+func (s *ShortenerService) GetWorkspace(ctx context.Context, userID *uuid.UUID, id string) (models.Workspace, error) {
+	workspace, err := s.requireWorkspaceRole(ctx, userID, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return models.Workspace{}, err
+	}
+
+	return *workspace, nil
+}
+
+// UpdateWorkspace function for renaming workspace by its owner
+func (s *ShortenerService) UpdateWorkspace(ctx context.Context, userID *uuid.UUID, id string, req models.WorkspaceRequest) (models.Workspace, error) {
+	workspace, err := s.requireWorkspaceRole(ctx, userID, id, models.WorkspaceRoleOwner)
+	if err != nil {
+		return models.Workspace{}, err
+	}
+
+	workspace.Name = strings.TrimSpace(req.Name)
+	if err = validateWorkspace(*workspace); err != nil {
+		return models.Workspace{}, err
+	}
+
+	if err = s.storage.SaveWorkspace(ctx, *workspace); err != nil {
+		return models.Workspace{}, err
+	}
+
+	return *workspace, nil
+}
+
+// DeleteWorkspace function for deleting workspace by its owner, links of workspace are returned to their creators
+func (s *ShortenerService) DeleteWorkspace(ctx context.Context, userID *uuid.UUID, id string) error {
+	if _, err := s.requireWorkspaceRole(ctx, userID, id, models.WorkspaceRoleOwner); err != nil {
+		return err
+	}
+
+	return s.storage.DeleteWorkspace(ctx, id)
+}
+
+// WorkspaceMembers function for getting members of workspace, user must be its member
+func (s *ShortenerService) WorkspaceMembers(ctx context.Context, userID *uuid.UUID, id string) ([]models.WorkspaceMember, error) {
+	if _, err := s.requireWorkspaceRole(ctx, userID, id, models.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := s.storage.GetWorkspaceMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range members {
+		members[i].WorkspaceID = ""
+	}
+	return members, nil
+}
+
+// SetWorkspaceMember function for adding member to workspace or changing member's role by workspace owner
+func (s *ShortenerService) SetWorkspaceMember(ctx context.Context, userID *uuid.UUID, id string, memberID *uuid.UUID, role string) (models.WorkspaceMember, error) {
+	if _, ok := workspaceRoleRanks[role]; !ok {
+		return models.WorkspaceMember{}, ErrInvalidWorkspaceRole
+	}
+	if _, err := s.requireWorkspaceRole(ctx, userID, id, models.WorkspaceRoleOwner); err != nil {
+		return models.WorkspaceMember{}, err
+	}
+
+	if role != models.WorkspaceRoleOwner {
+		if err := s.keepWorkspaceOwner(ctx, id, memberID); err != nil {
+			return models.WorkspaceMember{}, err
+		}
+	}
+
+	member := models.WorkspaceMember{WorkspaceID: id, UserID: memberID, Role: role}
+	if err := s.storage.SaveWorkspaceMember(ctx, member); err != nil {
+		return models.WorkspaceMember{}, err
+	}
+
+	member.WorkspaceID = ""
+	return member, nil
+}
+
+// RemoveWorkspaceMember function for removing member from workspace by its owner, any member can leave workspace
+func (s *ShortenerService) RemoveWorkspaceMember(ctx context.Context, userID *uuid.UUID, id string, memberID *uuid.UUID) error {
+	required := models.WorkspaceRoleOwner
+	if userID != nil && memberID != nil && *userID == *memberID {
+		required = models.WorkspaceRoleViewer
+	}
+	if _, err := s.requireWorkspaceRole(ctx, userID, id, required); err != nil {
+		return err
+	}
+
+	if err := s.keepWorkspaceOwner(ctx, id, memberID); err != nil {
+		return err
+	}
+
+	return s.storage.DeleteWorkspaceMember(ctx, id, memberID)
+}
+
+// WorkspaceLinks function for getting not deleted links of workspace, newest links are first
+func (s *ShortenerService) WorkspaceLinks(ctx context.Context, userID *uuid.UUID, id string) ([]models.Link, error) {
+	if _, err := s.requireWorkspaceRole(ctx, userID, id, models.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+
+	links := make([]models.Link, 0)
+	err := s.storage.IterateUrlsByWorkspace(ctx, id, func(link models.StorageURL) error {
+		if !link.DeletedFlag {
+			links = append(links, s.link(link))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortLinks(links)
+	return links, nil
+}
+
+// GetWorkspaceURLs function for getting all URLs of workspace in format of user's URLs list
+func (s *ShortenerService) GetWorkspaceURLs(ctx context.Context, userID *uuid.UUID, id string) ([]models.UserURL, error) {
+	if _, err := s.requireWorkspaceRole(ctx, userID, id, models.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+
+	urls := make([]models.UserURL, 0)
+	err := s.storage.IterateUrlsByWorkspace(ctx, id, func(link models.StorageURL) error {
+		urls = append(urls, s.listURL(link))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// requireWorkspaceRole function returns workspace with user's role if user has required role or higher
+func (s *ShortenerService) requireWorkspaceRole(ctx context.Context, userID *uuid.UUID, id string, required string) (*models.Workspace, error) {
+	if id == "" || userID == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	workspace, err := s.storage.GetWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if workspace == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	role, err := s.workspaceRole(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	// чужое рабочее пространство не отличается от несуществующего
+	if role == "" {
+		return nil, ErrWorkspaceNotFound
+	}
+	if workspaceRoleRanks[role] < workspaceRoleRanks[required] {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	workspace.Role = role
+	return workspace, nil
+}
+
+// workspaceRole function returns role of user in workspace, empty role is returned if user isn't member
+func (s *ShortenerService) workspaceRole(ctx context.Context, userID *uuid.UUID, id string) (string, error) {
+	members, err := s.storage.GetWorkspaceMembers(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	for _, member := range members {
+		if sameUserID(member.UserID, userID) {
+			return member.Role, nil
+		}
+	}
+	return "", nil
+}
+
+// keepWorkspaceOwner function checks that workspace has other owner if member loses owner role
+func (s *ShortenerService) keepWorkspaceOwner(ctx context.Context, id string, memberID *uuid.UUID) error {
+	members, err := s.storage.GetWorkspaceMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	owners, memberIsOwner := 0, false
+	for _, member := range members {
+		if member.Role != models.WorkspaceRoleOwner {
+			continue
+		}
+		owners++
+		if sameUserID(member.UserID, memberID) {
+			memberIsOwner = true
+		}
+	}
+
+	if memberIsOwner && owners == 1 {
+		return ErrLastWorkspaceOwner
+	}
+	return nil
+}
+
+// checkLinkAccess function checks access of user to link: own links are available to creator,
+// links of workspace are available to its members with required role or higher
+func (s *ShortenerService) checkLinkAccess(ctx context.Context, userID *uuid.UUID, link models.StorageURL, required string) error {
+	if link.WorkspaceID == "" {
+		if !sameUserID(link.UserID, userID) {
+			return ErrLinkNotFound
+		}
+		return nil
+	}
+
+	_, err := s.requireWorkspaceRole(ctx, userID, link.WorkspaceID, required)
+	if errors.Is(err, ErrWorkspaceNotFound) {
+		return ErrLinkNotFound
+	}
+	return err
+}
+
+// accessLink function returns not deleted link available to user with required role
+func (s *ShortenerService) accessLink(ctx context.Context, userID *uuid.UUID, id string, required string) (*models.StorageURL, error) {
+	link, err := s.storage.GetLink(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+
+	// чужая ссылка не отличается от несуществующей
+	if err = s.checkLinkAccess(ctx, userID, *link, required); err != nil {
+		return nil, err
+	}
+	if link.DeletedFlag {
+		return nil, storage.NewAlreadyDeletedError(id)
+	}
+
+	return link, nil
+}
+
+// validateWorkspace function checks workspace name
+func validateWorkspace(workspace models.Workspace) error {
+	if workspace.Name == "" || utf8.RuneCountInString(workspace.Name) > workspaceNameLimit {
+		return ErrInvalidWorkspace
+	}
+	return nil
+}
+
+// sameUserID function compares user ids, nil id doesn't match any user
+func sameUserID(a *uuid.UUID, b *uuid.UUID) bool {
+	return a != nil && b != nil && *a == *b
+}
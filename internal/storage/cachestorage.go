@@ -4,46 +4,254 @@ import (
 	"context"
 	"github.com/gofrs/uuid"
 	"github.com/romanp1989/go-shortener/internal/models"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // CacheStorage Cache storage
 type CacheStorage struct {
-	storageURL map[string]string
+	mu         sync.RWMutex
+	storageURL map[string]*models.StorageURL
+	// originals keys of links by domain and original URL, links with clicks limit or password aren't included
+	originals map[originalKey]string
+	index     *searchIndex
+
+	idempotency map[string]models.IdempotencyRecord
+	settings    map[uuid.UUID]models.UserSettings
+	collections map[string]models.Collection
+	workspaces  map[string]models.Workspace
+	members     map[string]map[uuid.UUID]string
+	accounts    map[uuid.UUID]models.Account
+	emails      map[string]uuid.UUID
+	sessions    map[string]models.Session
+
+	// persist is called under lock for every changed URL, used by file storage
+	persist func(link models.StorageURL) error
+	// persistClicks is called under lock for every counted redirect, used by file storage
+	persistClicks func(record clicksRecord) error
+	// persistVariantClicks is called under lock for every counted redirect to A/B variant, used by file storage
+	persistVariantClicks func(record variantClicksRecord) error
+	// persistCheck is called under lock for check result that changes status of URL, used by file storage
+	persistCheck func(record checkRecord) error
+	// persistIdempotency is called under lock for every saved idempotency record, used by file storage
+	persistIdempotency func(record models.IdempotencyRecord) error
+	// persistSettings is called under lock for every saved user's settings, used by file storage
+	persistSettings func(settings models.UserSettings) error
+	// persistCollection is called under lock for every saved or deleted collection, used by file storage
+	persistCollection func(collection models.Collection, deleted bool) error
+	// persistWorkspace is called under lock for every changed workspace or member, used by file storage
+	persistWorkspace func(record workspaceRecord) error
+	// persistAccount is called under lock for every created account and saved or deleted session, used by file storage
+	persistAccount func(record accountRecord) error
+	// compactAccounts is called under lock after expired sessions are removed, used by file storage
+	compactAccounts func() error
+}
+
+// clicksRecord counted redirects of URL, written apart from URL record
+type clicksRecord struct {
+	ShortURL string `json:"short_url"`
+	Clicks   int64  `json:"clicks"`
+}
+
+// variantClicksRecord counted redirects to A/B variant of URL, written apart from URL record
+type variantClicksRecord struct {
+	ShortURL string `json:"short_url"`
+	Variant  string `json:"variant"`
+	Clicks   int64  `json:"clicks"`
+}
+
+// checkRecord result of destination check of URL, written apart from URL record
+type checkRecord struct {
+	ShortURL  string    `json:"short_url"`
+	Status    int       `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// originalKey key of link by domain and original URL, one URL is shortened once on every domain
+type originalKey struct {
+	domain string
+	url    string
+}
+
+// linkOriginalKey function returns key of link by original URL, domain is part of short URL key after @
+func linkOriginalKey(link models.StorageURL) originalKey {
+	_, domain, _ := strings.Cut(link.ShortURL, "@")
+	return originalKey{domain: domain, url: link.OriginalURL}
+}
+
+// accountRecord created account or change of login session, deleted session is written with deleted flag
+type accountRecord struct {
+	Account *models.Account `json:"account,omitempty"`
+	Session *models.Session `json:"session,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+// workspaceRecord change of workspace or its member, deleted workspace or member is written with deleted flag
+type workspaceRecord struct {
+	Workspace *models.Workspace       `json:"workspace,omitempty"`
+	Member    *models.WorkspaceMember `json:"member,omitempty"`
+	Deleted   bool                    `json:"deleted,omitempty"`
 }
 
 // NewCacheStorage factory for create cache storage
 func NewCacheStorage() *CacheStorage {
-	return &CacheStorage{storageURL: make(map[string]string)}
+	return &CacheStorage{
+		storageURL:  make(map[string]*models.StorageURL),
+		originals:   make(map[originalKey]string),
+		index:       newSearchIndex(),
+		idempotency: make(map[string]models.IdempotencyRecord),
+		settings:    make(map[uuid.UUID]models.UserSettings),
+		collections: make(map[string]models.Collection),
+		workspaces:  make(map[string]models.Workspace),
+		members:     make(map[string]map[uuid.UUID]string),
+		accounts:    make(map[uuid.UUID]models.Account),
+		emails:      make(map[string]uuid.UUID),
+		sessions:    make(map[string]models.Session),
+	}
 }
 
 // Get function for get URL from DB
 func (s *CacheStorage) Get(inputURL string) (string, error) {
-	if foundurl, ok := s.storageURL[inputURL]; ok {
-		return foundurl, nil
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if link, ok := s.storageURL[inputURL]; ok {
+		if link.DeletedFlag {
+			return "", NewAlreadyDeletedError(inputURL)
+		}
+		return link.OriginalURL, nil
+	}
+	// по исходному URL ищется ссылка домена по умолчанию
+	if shortURL, ok := s.originals[originalKey{url: inputURL}]; ok {
+		if s.storageURL[shortURL].DeletedFlag {
+			return "", NewAlreadyDeletedError(inputURL)
+		}
+		return shortURL, nil
 	}
 	return "", nil
 }
 
 // Save function for save URL in DB
 func (s *CacheStorage) Save(ctx context.Context, originalURL string, shortURL string, userID *uuid.UUID) (string, error) {
-	s.storageURL[shortURL] = originalURL
-	s.storageURL[originalURL] = shortURL
-	return shortURL, nil
+	return s.SaveLink(ctx, models.StorageURL{
+		UserID:      userID,
+		OriginalURL: originalURL,
+		ShortURL:    shortURL,
+	})
+}
+
+// SaveLink function for save URL with metadata in DB
+func (s *CacheStorage) SaveLink(ctx context.Context, link models.StorageURL) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// как и в БД, существующий URL не перезаписывается, публичная ссылка URL на домене одна
+	if existingURL, ok := s.conflictURL(link); ok {
+		return "", NewURLConflictError(existingURL, ErrConflict)
+	}
+
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now().UTC()
+	}
+	if err := s.put(link); err != nil {
+		return "", err
+	}
+	return link.ShortURL, nil
 }
 
 // SaveBatch function for saving URL list
 func (s *CacheStorage) SaveBatch(ctx context.Context, urls []models.StorageURL, userID *uuid.UUID) ([]string, error) {
-	return nil, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shortURLs := make([]string, 0, len(urls))
+	now := time.Now().UTC()
+	for _, url := range urls {
+		// как и в БД, существующий URL не перезаписывается и возвращается его код
+		if existingURL, ok := s.conflictURL(url); ok {
+			shortURLs = append(shortURLs, existingURL)
+			continue
+		}
+
+		url.UserID, url.CreatedAt = userID, now
+		if err := s.put(url); err != nil {
+			return nil, err
+		}
+		shortURLs = append(shortURLs, url.ShortURL)
+	}
+
+	return shortURLs, nil
 }
 
 // DeleteBatch function for delete URLs list
 func (s *CacheStorage) DeleteBatch(ctx context.Context, userID *uuid.UUID, urls []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, shortURL := range urls {
+		link, ok := s.storageURL[shortURL]
+		// ссылки рабочего пространства удаляются через DeleteWorkspaceBatch
+		if !ok || link.DeletedFlag || link.WorkspaceID != "" || !sameUser(link.UserID, userID) {
+			continue
+		}
+
+		deleted := *link
+		deleted.DeletedFlag = true
+		if err := s.put(deleted); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // GetAllUrlsByUser function for get all user's URLs
 func (s *CacheStorage) GetAllUrlsByUser(ctx context.Context, userID *uuid.UUID) ([]models.StorageURL, error) {
-	return nil, nil
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	storageURLs := make([]models.StorageURL, 0)
+	for _, link := range s.storageURL {
+		if sameUser(link.UserID, userID) {
+			storageURLs = append(storageURLs, *link)
+		}
+	}
+
+	sort.Slice(storageURLs, func(i, j int) bool {
+		return storageURLs[i].ShortURL < storageURLs[j].ShortURL
+	})
+
+	return storageURLs, nil
+}
+
+// IterateUrlsByUser function calls fn for every user's URL including deleted ones, iteration stops on fn error
+func (s *CacheStorage) IterateUrlsByUser(ctx context.Context, userID *uuid.UUID, fn func(models.StorageURL) error) error {
+	storageURLs, err := s.GetAllUrlsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range storageURLs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetUrlsPageByUser function for get page of user's not deleted URLs with count of all of them, newest URLs are first
+func (s *CacheStorage) GetUrlsPageByUser(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]models.StorageURL, int, error) {
+	urls, total := s.urlsPage(func(link *models.StorageURL) bool {
+		return sameUser(link.UserID, userID)
+	}, limit, offset)
+	return urls, total, nil
 }
 
 // Ping function for ping DB connection
@@ -54,3 +262,949 @@ func (s *CacheStorage) Ping(ctx context.Context) error {
 func (s *CacheStorage) GetStats(ctx context.Context) (models.StorageStats, error) {
 	return models.StorageStats{}, nil
 }
+
+// Search function for full-text search in user's URLs with inverted index
+func (s *CacheStorage) Search(ctx context.Context, userID *uuid.UUID, query string, limit int) ([]models.SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]models.SearchResult, 0)
+	for shortURL, rank := range s.index.search(query) {
+		link, ok := s.storageURL[shortURL]
+		if !ok || link.DeletedFlag || !sameUser(link.UserID, userID) {
+			continue
+		}
+
+		results = append(results, models.SearchResult{
+			ShortURL:    link.ShortURL,
+			OriginalURL: link.OriginalURL,
+			Title:       link.Title,
+			Notes:       link.Notes,
+			Tags:        link.Tags,
+			Rank:        rank,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].ShortURL < results[j].ShortURL
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// GetLink function for get URL with metadata by short URL, nil is returned if URL isn't found
+func (s *CacheStorage) GetLink(ctx context.Context, shortURL string) (*models.StorageURL, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link, ok := s.storageURL[shortURL]
+	if !ok {
+		return nil, nil
+	}
+
+	found := *link
+	return &found, nil
+}
+
+// UpdateLink function for update title, notes, tags and settings of URL
+func (s *CacheStorage) UpdateLink(ctx context.Context, link models.StorageURL) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.storageURL[link.ShortURL]
+	if !ok {
+		return nil
+	}
+
+	updated := *saved
+	updated.Title, updated.Notes, updated.Tags = link.Title, link.Notes, link.Tags
+	updated.Interstitial, updated.PasswordHash, updated.MaxClicks = link.Interstitial, link.PasswordHash, link.MaxClicks
+	updated.ActiveFrom, updated.ActiveUntil, updated.FallbackURL = link.ActiveFrom, link.ActiveUntil, link.FallbackURL
+	updated.Rules, updated.StickyVariants = link.Rules, link.StickyVariants
+	updated.ForwardQuery, updated.UTM = link.ForwardQuery, link.UTM
+	updated.Variants = keepVariantClicks(link.Variants, saved.Variants)
+	updated.CollectionID, updated.WorkspaceID = link.CollectionID, link.WorkspaceID
+
+	// как и в БД, ставшая публичной ссылка не заменяет публичную ссылку того же URL
+	if saved.Private() && !updated.Private() {
+		if existingURL, ok := s.originals[linkOriginalKey(updated)]; ok && existingURL != updated.ShortURL {
+			return NewURLConflictError(existingURL, ErrConflict)
+		}
+	}
+
+	return s.put(updated)
+}
+
+// IncrementVariantClicks function for counting redirect to A/B variant of short URL, unknown URL or variant is ignored
+func (s *CacheStorage) IncrementVariantClicks(ctx context.Context, shortURL string, variantID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.storageURL[shortURL]
+	if !ok {
+		return nil
+	}
+
+	i := slices.IndexFunc(saved.Variants, func(variant models.Variant) bool { return variant.ID == variantID })
+	if i < 0 {
+		return nil
+	}
+
+	record := variantClicksRecord{ShortURL: shortURL, Variant: variantID, Clicks: saved.Variants[i].Clicks + 1}
+	// счетчик сохраняется отдельно от ссылки, поисковый индекс не меняется
+	if s.persistVariantClicks != nil {
+		if err := s.persistVariantClicks(record); err != nil {
+			return err
+		}
+	}
+	s.putVariantClicks(record)
+
+	return nil
+}
+
+// putVariantClicks function sets counted redirects to A/B variant of URL, caller must hold the write lock
+func (s *CacheStorage) putVariantClicks(record variantClicksRecord) {
+	saved, ok := s.storageURL[record.ShortURL]
+	if !ok {
+		return
+	}
+	i := slices.IndexFunc(saved.Variants, func(variant models.Variant) bool { return variant.ID == record.Variant })
+	// переходы только растут, запись ссылки может быть сохранена позже счетчика
+	if i < 0 || saved.Variants[i].Clicks >= record.Clicks {
+		return
+	}
+
+	updated := *saved
+	updated.Variants = slices.Clone(saved.Variants)
+	updated.Variants[i].Clicks = record.Clicks
+	s.storageURL[record.ShortURL] = &updated
+}
+
+// IncrementClicks function for counting redirect by short URL, unknown URL is ignored.
+// ErrClicksExhausted is returned if clicks limit is reached
+func (s *CacheStorage) IncrementClicks(ctx context.Context, shortURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.storageURL[shortURL]
+	if !ok {
+		return nil
+	}
+	if saved.MaxClicks > 0 && saved.Clicks >= saved.MaxClicks {
+		return ErrClicksExhausted
+	}
+
+	updated := *saved
+	updated.Clicks++
+
+	// счетчик сохраняется отдельно от ссылки, поисковый индекс не меняется
+	if s.persistClicks != nil {
+		if err := s.persistClicks(clicksRecord{ShortURL: shortURL, Clicks: updated.Clicks}); err != nil {
+			return err
+		}
+	}
+	s.storageURL[shortURL] = &updated
+
+	return nil
+}
+
+// putClicks function sets counted redirects of URL, caller must hold the write lock
+func (s *CacheStorage) putClicks(record clicksRecord) {
+	saved, ok := s.storageURL[record.ShortURL]
+	// переходы только растут, запись ссылки может быть сохранена позже счетчика
+	if !ok || saved.Clicks >= record.Clicks {
+		return
+	}
+
+	updated := *saved
+	updated.Clicks = record.Clicks
+	s.storageURL[record.ShortURL] = &updated
+}
+
+// GetLinksForCheck function for get not deleted URLs not checked since checkedBefore, never checked URLs go first.
+// URLs with clicks limit or password aren't checked, request to destination can use up one-time secret
+func (s *CacheStorage) GetLinksForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.StorageURL, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	links := make([]models.StorageURL, 0)
+	for _, link := range s.storageURL {
+		if !link.DeletedFlag && link.ShortURL != "" && !link.Private() && (link.LastCheckedAt == nil || link.LastCheckedAt.Before(checkedBefore)) {
+			links = append(links, *link)
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		checked1, checked2 := links[i].LastCheckedAt, links[j].LastCheckedAt
+		if (checked1 == nil) != (checked2 == nil) {
+			return checked1 == nil
+		}
+		if checked1 != nil && !checked1.Equal(*checked2) {
+			return checked1.Before(*checked2)
+		}
+		return links[i].ShortURL < links[j].ShortURL
+	})
+
+	if limit > 0 && len(links) > limit {
+		links = links[:limit]
+	}
+	return links, nil
+}
+
+// SaveLinkCheck function for save status and time of URL destination check, unknown URL is ignored
+func (s *CacheStorage) SaveLinkCheck(ctx context.Context, shortURL string, status int, checkedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.storageURL[shortURL]
+	if !ok {
+		return nil
+	}
+
+	// время проверки без смены статуса хранится только в памяти, после перезапуска ссылка проверяется снова
+	if s.persistCheck != nil && saved.LastStatus != status {
+		if err := s.persistCheck(checkRecord{ShortURL: shortURL, Status: status, CheckedAt: checkedAt}); err != nil {
+			return err
+		}
+	}
+	s.putCheck(checkRecord{ShortURL: shortURL, Status: status, CheckedAt: checkedAt})
+
+	return nil
+}
+
+// putCheck function sets result of the last destination check of URL, caller must hold the write lock
+func (s *CacheStorage) putCheck(record checkRecord) {
+	saved, ok := s.storageURL[record.ShortURL]
+	if !ok || (saved.LastCheckedAt != nil && saved.LastCheckedAt.After(record.CheckedAt)) {
+		return
+	}
+
+	updated := *saved
+	updated.LastStatus, updated.LastCheckedAt = record.Status, &record.CheckedAt
+	s.storageURL[record.ShortURL] = &updated
+}
+
+// SavePageMetadata function for save title, description and image of URL destination page, unknown URL is ignored
+func (s *CacheStorage) SavePageMetadata(ctx context.Context, shortURL string, page models.PageMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.storageURL[shortURL]
+	if !ok {
+		return nil
+	}
+
+	updated := *saved
+	updated.Page = &page
+
+	return s.put(updated)
+}
+
+// GetScheduledLinks function for get not deleted URLs with active window of all users, ordered by window start
+func (s *CacheStorage) GetScheduledLinks(ctx context.Context) ([]models.StorageURL, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	links := make([]models.StorageURL, 0)
+	for _, link := range s.storageURL {
+		if !link.DeletedFlag && (link.ActiveFrom != nil || link.ActiveUntil != nil) {
+			links = append(links, *link)
+		}
+	}
+
+	// как и в БД, ссылки без начала окна идут первыми
+	sort.Slice(links, func(i, j int) bool {
+		from1, from2 := links[i].ActiveFrom, links[j].ActiveFrom
+		if (from1 == nil) != (from2 == nil) {
+			return from1 == nil
+		}
+		if from1 != nil && !from1.Equal(*from2) {
+			return from1.Before(*from2)
+		}
+		return links[i].ShortURL < links[j].ShortURL
+	})
+
+	return links, nil
+}
+
+// GetIdempotencyRecord function for get saved response by user and idempotency key, expired records aren't returned
+func (s *CacheStorage) GetIdempotencyRecord(ctx context.Context, userID *uuid.UUID, key string) (*models.IdempotencyRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.idempotency[idempotencyKey(userID, key)]
+	if !ok || !record.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// SaveIdempotencyRecord function for save response of request with idempotency key, expired records are removed
+func (s *CacheStorage) SaveIdempotencyRecord(ctx context.Context, record models.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.persistIdempotency != nil {
+		if err := s.persistIdempotency(record); err != nil {
+			return err
+		}
+	}
+
+	s.putIdempotency(record)
+
+	return nil
+}
+
+// putIdempotency function stores idempotency record, caller must hold the write lock
+func (s *CacheStorage) putIdempotency(record models.IdempotencyRecord) {
+	now := time.Now()
+	for key, saved := range s.idempotency {
+		if !saved.ExpiresAt.After(now) {
+			delete(s.idempotency, key)
+		}
+	}
+
+	if record.ExpiresAt.After(now) {
+		s.idempotency[idempotencyKey(record.UserID, record.Key)] = record
+	}
+}
+
+// conflictURL function returns short URL of saved link that conflicts with new link: link with the same short URL
+// or public link with the same domain and original URL. Caller must hold the lock
+func (s *CacheStorage) conflictURL(link models.StorageURL) (string, bool) {
+	if !link.Private() {
+		if existingURL, ok := s.originals[linkOriginalKey(link)]; ok {
+			return existingURL, true
+		}
+	}
+
+	_, ok := s.storageURL[link.ShortURL]
+	return link.ShortURL, ok
+}
+
+// put function stores URL, caller must hold the write lock
+func (s *CacheStorage) put(link models.StorageURL) error {
+	if s.persist != nil {
+		if err := s.persist(link); err != nil {
+			return err
+		}
+	}
+
+	if prev, ok := s.storageURL[link.ShortURL]; ok && s.originals[linkOriginalKey(*prev)] == prev.ShortURL {
+		delete(s.originals, linkOriginalKey(*prev))
+	}
+
+	s.storageURL[link.ShortURL] = &link
+	// ссылка с лимитом переходов или паролем не находится по исходному URL
+	if !link.Private() {
+		s.originals[linkOriginalKey(link)] = link.ShortURL
+	}
+	s.index.add(link)
+
+	return nil
+}
+
+// keepVariantClicks function copies counted clicks of saved variants to updated variants with the same id,
+// as in DB clicks aren't changed by link update
+func keepVariantClicks(variants []models.Variant, saved []models.Variant) []models.Variant {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	clicks := make(map[string]int64, len(saved))
+	for _, variant := range saved {
+		clicks[variant.ID] = variant.Clicks
+	}
+
+	updated := make([]models.Variant, 0, len(variants))
+	for _, variant := range variants {
+		variant.Clicks = clicks[variant.ID]
+		updated = append(updated, variant)
+	}
+	return updated
+}
+
+// GetUserSettings function for get user's settings, nil is returned if settings aren't saved
+func (s *CacheStorage) GetUserSettings(ctx context.Context, userID *uuid.UUID) (*models.UserSettings, error) {
+	if userID == nil {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings, ok := s.settings[*userID]
+	if !ok {
+		return nil, nil
+	}
+	return &settings, nil
+}
+
+// SaveUserSettings function for save user's settings
+func (s *CacheStorage) SaveUserSettings(ctx context.Context, settings models.UserSettings) error {
+	if settings.UserID == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.persistSettings != nil {
+		if err := s.persistSettings(settings); err != nil {
+			return err
+		}
+	}
+
+	s.putSettings(settings)
+
+	return nil
+}
+
+// putSettings function stores user's settings, caller must hold the write lock
+func (s *CacheStorage) putSettings(settings models.UserSettings) {
+	if settings.UserID != nil {
+		s.settings[*settings.UserID] = settings
+	}
+}
+
+// GetCollections function for get user's collections ordered by creation time
+func (s *CacheStorage) GetCollections(ctx context.Context, userID *uuid.UUID) ([]models.Collection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collections := make([]models.Collection, 0)
+	for _, collection := range s.collections {
+		if sameUser(collection.UserID, userID) {
+			collections = append(collections, collection)
+		}
+	}
+
+	sort.Slice(collections, func(i, j int) bool {
+		if !collections[i].CreatedAt.Equal(collections[j].CreatedAt) {
+			return collections[i].CreatedAt.Before(collections[j].CreatedAt)
+		}
+		return collections[i].ID < collections[j].ID
+	})
+
+	return collections, nil
+}
+
+// GetCollection function for get collection by id, nil is returned if collection isn't found
+func (s *CacheStorage) GetCollection(ctx context.Context, id string) (*models.Collection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collection, ok := s.collections[id]
+	if !ok {
+		return nil, nil
+	}
+	return &collection, nil
+}
+
+// SaveCollection function for create collection or update name and description of existing one
+func (s *CacheStorage) SaveCollection(ctx context.Context, collection models.Collection) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if saved, ok := s.collections[collection.ID]; ok {
+		saved.Name, saved.Description = collection.Name, collection.Description
+		collection = saved
+	}
+	if collection.CreatedAt.IsZero() {
+		collection.CreatedAt = time.Now().UTC()
+	}
+
+	if s.persistCollection != nil {
+		if err := s.persistCollection(collection, false); err != nil {
+			return err
+		}
+	}
+
+	s.putCollection(collection, false)
+
+	return nil
+}
+
+// DeleteCollection function for delete collection, its links are kept without collection
+func (s *CacheStorage) DeleteCollection(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	collection, ok := s.collections[id]
+	if !ok {
+		return nil
+	}
+
+	for _, link := range s.storageURL {
+		if link.CollectionID != id {
+			continue
+		}
+
+		updated := *link
+		updated.CollectionID = ""
+		if err := s.put(updated); err != nil {
+			return err
+		}
+	}
+
+	if s.persistCollection != nil {
+		if err := s.persistCollection(collection, true); err != nil {
+			return err
+		}
+	}
+
+	s.putCollection(collection, true)
+
+	return nil
+}
+
+// putCollection function stores or removes collection, caller must hold the write lock
+func (s *CacheStorage) putCollection(collection models.Collection, deleted bool) {
+	if deleted {
+		delete(s.collections, collection.ID)
+		return
+	}
+	s.collections[collection.ID] = collection
+}
+
+// GetUserWorkspaces function for get workspaces of user with user's role ordered by creation time
+func (s *CacheStorage) GetUserWorkspaces(ctx context.Context, userID *uuid.UUID) ([]models.Workspace, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workspaces := make([]models.Workspace, 0)
+	if userID == nil {
+		return workspaces, nil
+	}
+	for id, members := range s.members {
+		if role, ok := members[*userID]; ok {
+			workspace := s.workspaces[id]
+			workspace.Role = role
+			workspaces = append(workspaces, workspace)
+		}
+	}
+
+	sort.Slice(workspaces, func(i, j int) bool {
+		if !workspaces[i].CreatedAt.Equal(workspaces[j].CreatedAt) {
+			return workspaces[i].CreatedAt.Before(workspaces[j].CreatedAt)
+		}
+		return workspaces[i].ID < workspaces[j].ID
+	})
+
+	return workspaces, nil
+}
+
+// GetWorkspace function for get workspace by id, nil is returned if workspace isn't found
+func (s *CacheStorage) GetWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workspace, ok := s.workspaces[id]
+	if !ok {
+		return nil, nil
+	}
+	return &workspace, nil
+}
+
+// SaveWorkspace function for create workspace or rename existing one
+func (s *CacheStorage) SaveWorkspace(ctx context.Context, workspace models.Workspace) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if saved, ok := s.workspaces[workspace.ID]; ok {
+		saved.Name = workspace.Name
+		workspace = saved
+	}
+	if workspace.CreatedAt.IsZero() {
+		workspace.CreatedAt = time.Now().UTC()
+	}
+	workspace.Role = ""
+
+	return s.changeWorkspace(workspaceRecord{Workspace: &workspace})
+}
+
+// DeleteWorkspace function for delete workspace with members, its links are returned to their creators
+func (s *CacheStorage) DeleteWorkspace(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workspace, ok := s.workspaces[id]
+	if !ok {
+		return nil
+	}
+
+	for _, link := range s.storageURL {
+		if link.WorkspaceID != id {
+			continue
+		}
+
+		updated := *link
+		updated.WorkspaceID = ""
+		if err := s.put(updated); err != nil {
+			return err
+		}
+	}
+
+	return s.changeWorkspace(workspaceRecord{Workspace: &workspace, Deleted: true})
+}
+
+// GetWorkspaceMembers function for get members of workspace ordered by user id
+func (s *CacheStorage) GetWorkspaceMembers(ctx context.Context, workspaceID string) ([]models.WorkspaceMember, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := make([]models.WorkspaceMember, 0)
+	for userID, role := range s.members[workspaceID] {
+		userID := userID
+		members = append(members, models.WorkspaceMember{WorkspaceID: workspaceID, UserID: &userID, Role: role})
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID.String() < members[j].UserID.String()
+	})
+
+	return members, nil
+}
+
+// SaveWorkspaceMember function for add member to workspace or change member's role
+func (s *CacheStorage) SaveWorkspaceMember(ctx context.Context, member models.WorkspaceMember) error {
+	if member.UserID == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.changeWorkspace(workspaceRecord{Member: &member})
+}
+
+// DeleteWorkspaceMember function for remove member from workspace
+func (s *CacheStorage) DeleteWorkspaceMember(ctx context.Context, workspaceID string, userID *uuid.UUID) error {
+	if userID == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.members[workspaceID][*userID]; !ok {
+		return nil
+	}
+
+	return s.changeWorkspace(workspaceRecord{Member: &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID}, Deleted: true})
+}
+
+// IterateUrlsByWorkspace function calls fn for every URL of workspace including deleted ones, iteration stops on fn error
+func (s *CacheStorage) IterateUrlsByWorkspace(ctx context.Context, workspaceID string, fn func(models.StorageURL) error) error {
+	s.mu.RLock()
+	links := make([]models.StorageURL, 0)
+	for _, link := range s.storageURL {
+		if workspaceID != "" && link.WorkspaceID == workspaceID {
+			links = append(links, *link)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].ShortURL < links[j].ShortURL
+	})
+
+	for _, link := range links {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetUrlsPageByWorkspace function for get page of not deleted URLs of workspace with count of all of them, newest URLs are first
+func (s *CacheStorage) GetUrlsPageByWorkspace(ctx context.Context, workspaceID string, limit int, offset int) ([]models.StorageURL, int, error) {
+	urls, total := s.urlsPage(func(link *models.StorageURL) bool {
+		return workspaceID != "" && link.WorkspaceID == workspaceID
+	}, limit, offset)
+	return urls, total, nil
+}
+
+// urlsPage function returns page of not deleted URLs matching filter and count of all of them, newest URLs are first
+func (s *CacheStorage) urlsPage(match func(*models.StorageURL) bool, limit int, offset int) ([]models.StorageURL, int) {
+	s.mu.RLock()
+	links := make([]*models.StorageURL, 0)
+	for _, link := range s.storageURL {
+		if !link.DeletedFlag && match(link) {
+			links = append(links, link)
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.After(links[j].CreatedAt)
+		}
+		return links[i].ShortURL < links[j].ShortURL
+	})
+
+	page := make([]models.StorageURL, 0, limit)
+	for i := offset; i < len(links) && len(page) < limit; i++ {
+		page = append(page, *links[i])
+	}
+	s.mu.RUnlock()
+
+	return page, len(links)
+}
+
+// DeleteWorkspaceBatch function for delete URLs list of workspace
+func (s *CacheStorage) DeleteWorkspaceBatch(ctx context.Context, workspaceID string, urls []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, shortURL := range urls {
+		link, ok := s.storageURL[shortURL]
+		if !ok || link.DeletedFlag || workspaceID == "" || link.WorkspaceID != workspaceID {
+			continue
+		}
+
+		deleted := *link
+		deleted.DeletedFlag = true
+		if err := s.put(deleted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// changeWorkspace function persists and applies change of workspace or member, caller must hold the write lock
+func (s *CacheStorage) changeWorkspace(record workspaceRecord) error {
+	if s.persistWorkspace != nil {
+		if err := s.persistWorkspace(record); err != nil {
+			return err
+		}
+	}
+
+	s.putWorkspace(record)
+
+	return nil
+}
+
+// putWorkspace function applies change of workspace or member, members of deleted workspace are removed,
+// caller must hold the write lock
+func (s *CacheStorage) putWorkspace(record workspaceRecord) {
+	if workspace := record.Workspace; workspace != nil {
+		if record.Deleted {
+			delete(s.workspaces, workspace.ID)
+			delete(s.members, workspace.ID)
+		} else {
+			s.workspaces[workspace.ID] = *workspace
+		}
+	}
+
+	if member := record.Member; member != nil && member.UserID != nil {
+		if record.Deleted {
+			delete(s.members[member.WorkspaceID], *member.UserID)
+			return
+		}
+		if s.members[member.WorkspaceID] == nil {
+			s.members[member.WorkspaceID] = make(map[uuid.UUID]string)
+		}
+		s.members[member.WorkspaceID][*member.UserID] = member.Role
+	}
+}
+
+// GetAccount function for get account by user id, nil is returned if user isn't registered
+func (s *CacheStorage) GetAccount(ctx context.Context, userID *uuid.UUID) (*models.Account, error) {
+	if userID == nil {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.accounts[*userID]
+	if !ok {
+		return nil, nil
+	}
+	return &account, nil
+}
+
+// GetAccountByEmail function for get account by email, nil is returned if email isn't registered
+func (s *CacheStorage) GetAccountByEmail(ctx context.Context, email string) (*models.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	userID, ok := s.emails[email]
+	if !ok {
+		return nil, nil
+	}
+	account := s.accounts[userID]
+	return &account, nil
+}
+
+// SaveAccount function for create account, ErrConflict is returned if email or user id is taken
+func (s *CacheStorage) SaveAccount(ctx context.Context, account models.Account) error {
+	if account.UserID == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.emails[account.Email]; ok {
+		return ErrConflict
+	}
+	if _, ok := s.accounts[*account.UserID]; ok {
+		return ErrConflict
+	}
+	if account.CreatedAt.IsZero() {
+		account.CreatedAt = time.Now().UTC()
+	}
+
+	return s.changeAccount(accountRecord{Account: &account})
+}
+
+// GetSession function for get login session by id, nil is returned if session isn't found
+func (s *CacheStorage) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// SaveSession function for save login session
+func (s *CacheStorage) SaveSession(ctx context.Context, session models.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.changeAccount(accountRecord{Session: &session})
+}
+
+// DeleteSession function for delete login session
+func (s *CacheStorage) DeleteSession(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil
+	}
+
+	return s.changeAccount(accountRecord{Session: &session, Deleted: true})
+}
+
+// DeleteExpiredSessions function for delete expired login sessions
+func (s *CacheStorage) DeleteExpiredSessions(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	expired := 0
+	for id, session := range s.sessions {
+		if !now.Before(session.ExpiresAt) {
+			delete(s.sessions, id)
+			expired++
+		}
+	}
+
+	if expired == 0 || s.compactAccounts == nil {
+		return nil
+	}
+	return s.compactAccounts()
+}
+
+// ClaimUrls function for move URLs and collections of one user to another one,
+// count of moved not deleted URLs is returned
+func (s *CacheStorage) ClaimUrls(ctx context.Context, from *uuid.UUID, to *uuid.UUID) (int, error) {
+	if from == nil || to == nil || *from == *to {
+		return 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	claimed := 0
+	for _, link := range s.storageURL {
+		if !sameUser(link.UserID, from) {
+			continue
+		}
+
+		updated := *link
+		updated.UserID = to
+		if err := s.put(updated); err != nil {
+			return 0, err
+		}
+		if !updated.DeletedFlag {
+			claimed++
+		}
+	}
+
+	for _, collection := range s.collections {
+		if !sameUser(collection.UserID, from) {
+			continue
+		}
+
+		collection.UserID = to
+		if s.persistCollection != nil {
+			if err := s.persistCollection(collection, false); err != nil {
+				return 0, err
+			}
+		}
+		s.putCollection(collection, false)
+	}
+
+	return claimed, nil
+}
+
+// changeAccount function persists and applies created account or change of session, caller must hold the write lock
+func (s *CacheStorage) changeAccount(record accountRecord) error {
+	if s.persistAccount != nil {
+		if err := s.persistAccount(record); err != nil {
+			return err
+		}
+	}
+
+	s.putAccount(record)
+
+	return nil
+}
+
+// putAccount function applies created account or change of session, caller must hold the write lock
+func (s *CacheStorage) putAccount(record accountRecord) {
+	if account := record.Account; account != nil && account.UserID != nil {
+		s.accounts[*account.UserID] = *account
+		s.emails[account.Email] = *account.UserID
+	}
+
+	if session := record.Session; session != nil {
+		if record.Deleted {
+			delete(s.sessions, session.ID)
+			return
+		}
+		s.sessions[session.ID] = *session
+	}
+}
+
+// sameUser function compares URL owner with user
+func sameUser(owner *uuid.UUID, userID *uuid.UUID) bool {
+	return owner != nil && userID != nil && *owner == *userID
+}
+
+// idempotencyKey function returns map key of idempotency record
+func idempotencyKey(userID *uuid.UUID, key string) string {
+	if userID == nil {
+		return "/" + key
+	}
+	return userID.String() + "/" + key
+}
@@ -0,0 +1,647 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/romanp1989/go-shortener/internal/auth"
+	"github.com/romanp1989/go-shortener/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheStorage_Search(t *testing.T) {
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	otherUserID := jwtService.EnsureRandom()
+
+	store := NewCacheStorage()
+	links := []models.StorageURL{
+		{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", Title: "Yandex search"},
+		{UserID: &userID, ShortURL: "x+5vpM8W", OriginalURL: "https://dzen.ru/search", Notes: "news feed"},
+		{UserID: &userID, ShortURL: "Gi0zPCNe", OriginalURL: "https://mail.ru", Tags: []string{"mail", "searching"}},
+		{UserID: &otherUserID, ShortURL: "kHRxUF2s", OriginalURL: "https://google.com/search", Title: "Search"},
+	}
+	for _, link := range links {
+		_, err := store.SaveLink(context.Background(), link)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "Ranked_by_field_weight",
+			query: "search",
+			want:  []string{"6YGS4ZUF", "Gi0zPCNe", "x+5vpM8W"},
+		},
+		{
+			name:  "Limit",
+			query: "search",
+			limit: 1,
+			want:  []string{"6YGS4ZUF"},
+		},
+		{
+			name:  "Several_words",
+			query: "News DZEN",
+			want:  []string{"x+5vpM8W"},
+		},
+		{
+			name:  "Not_found",
+			query: "github",
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.Search(context.Background(), &userID, tt.query, tt.limit)
+			require.NoError(t, err)
+
+			got := make([]string, 0, len(results))
+			for _, result := range results {
+				got = append(got, result.ShortURL)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCacheStorage_SearchReindex(t *testing.T) {
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+
+	store := NewCacheStorage()
+	_, err := store.SaveLink(context.Background(), models.StorageURL{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", Title: "old"})
+	require.NoError(t, err)
+	// существующий URL не перезаписывается
+	_, err = store.SaveLink(context.Background(), models.StorageURL{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", Title: "new"})
+	var errConflict *URLConflictError
+	require.ErrorAs(t, err, &errConflict)
+	assert.Equal(t, "6YGS4ZUF", errConflict.URL)
+	require.NoError(t, store.UpdateLink(context.Background(), models.StorageURL{ShortURL: "6YGS4ZUF", Title: "new"}))
+
+	results, err := store.Search(context.Background(), &userID, "old", 0)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = store.Search(context.Background(), &userID, "new", 0)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestCacheStorage_DeleteBatch(t *testing.T) {
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	otherUserID := jwtService.EnsureRandom()
+
+	store := NewCacheStorage()
+	_, err := store.Save(context.Background(), "https://ya.ru", "6YGS4ZUF", &userID)
+	require.NoError(t, err)
+	_, err = store.Save(context.Background(), "https://dzen.ru", "x+5vpM8W", &otherUserID)
+	require.NoError(t, err)
+
+	err = store.DeleteBatch(context.Background(), &userID, []string{"6YGS4ZUF", "x+5vpM8W"})
+	require.NoError(t, err)
+
+	_, err = store.Get("6YGS4ZUF")
+	var errDeleted *AlreadyDeleted
+	assert.ErrorAs(t, err, &errDeleted)
+
+	original, err := store.Get("x+5vpM8W")
+	require.NoError(t, err)
+	assert.Equal(t, "https://dzen.ru", original)
+
+	var exported []models.StorageURL
+	err = store.IterateUrlsByUser(context.Background(), &userID, func(link models.StorageURL) error {
+		exported = append(exported, link)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	assert.True(t, exported[0].DeletedFlag)
+	assert.False(t, exported[0].CreatedAt.IsZero())
+}
+
+func TestCacheStorage_GetUrlsPageByUser(t *testing.T) {
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	otherUserID := jwtService.EnsureRandom()
+	createdAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewCacheStorage()
+	links := []models.StorageURL{
+		{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", CreatedAt: createdAt},
+		{UserID: &userID, ShortURL: "x+5vpM8W", OriginalURL: "https://dzen.ru", CreatedAt: createdAt.Add(time.Hour)},
+		{UserID: &userID, ShortURL: "xKh7DnOW", OriginalURL: "https://mail.ru", CreatedAt: createdAt.Add(2 * time.Hour)},
+		{UserID: &userID, ShortURL: "H14HxBQB", OriginalURL: "https://lenta.ru", CreatedAt: createdAt},
+		{UserID: &otherUserID, ShortURL: "kHRxUF2s", OriginalURL: "https://google.com", CreatedAt: createdAt},
+	}
+	for _, link := range links {
+		_, err := store.SaveLink(context.Background(), link)
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.DeleteBatch(context.Background(), &userID, []string{"xKh7DnOW"}))
+
+	// удаленные ссылки не считаются, при равном времени создания порядок по короткому URL
+	page, total, err := store.GetUrlsPageByUser(context.Background(), &userID, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, page, 2)
+	assert.Equal(t, "6YGS4ZUF", page[0].ShortURL)
+	assert.Equal(t, "H14HxBQB", page[1].ShortURL)
+
+	page, total, err = store.GetUrlsPageByUser(context.Background(), &userID, 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Empty(t, page)
+}
+
+func TestCacheStorage_GetByOriginal(t *testing.T) {
+	ctx := context.Background()
+	store := NewCacheStorage()
+
+	// один URL сокращен на домене по умолчанию, на дополнительном домене и с лимитом переходов
+	for _, link := range []models.StorageURL{
+		{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF"},
+		{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF@go.example.com"},
+		{OriginalURL: "https://ya.ru", ShortURL: "NUPLJNFJ", MaxClicks: 1},
+	} {
+		_, err := store.SaveLink(ctx, link)
+		require.NoError(t, err)
+	}
+
+	got, err := store.Get("https://ya.ru")
+	require.NoError(t, err)
+	assert.Equal(t, "6YGS4ZUF", got)
+
+	// изменение ссылки дополнительного домена не затрагивает ссылку домена по умолчанию
+	require.NoError(t, store.UpdateLink(ctx, models.StorageURL{ShortURL: "6YGS4ZUF@go.example.com", MaxClicks: 1}))
+	got, err = store.Get("https://ya.ru")
+	require.NoError(t, err)
+	assert.Equal(t, "6YGS4ZUF", got)
+}
+
+func TestCacheStorage_PublicLinkConflict(t *testing.T) {
+	ctx := context.Background()
+	store := NewCacheStorage()
+
+	for _, link := range []models.StorageURL{
+		{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF"},
+		{OriginalURL: "https://ya.ru", ShortURL: "NUPLJNFJ", MaxClicks: 1},
+	} {
+		_, err := store.SaveLink(ctx, link)
+		require.NoError(t, err)
+	}
+
+	// как и в БД, конфликт публичной ссылки возвращает код существующей ссылки URL
+	_, err := store.SaveLink(ctx, models.StorageURL{OriginalURL: "https://ya.ru", ShortURL: "Q0VLhuIm"})
+	var errConflict *URLConflictError
+	require.ErrorAs(t, err, &errConflict)
+	assert.Equal(t, "6YGS4ZUF", errConflict.URL)
+
+	got, err := store.SaveBatch(ctx, []models.StorageURL{{OriginalURL: "https://ya.ru", ShortURL: "Q0VLhuIm"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6YGS4ZUF"}, got)
+
+	err = store.UpdateLink(ctx, models.StorageURL{ShortURL: "NUPLJNFJ"})
+	require.ErrorAs(t, err, &errConflict)
+	assert.Equal(t, "6YGS4ZUF", errConflict.URL)
+
+	link, err := store.GetLink(ctx, "NUPLJNFJ")
+	require.NoError(t, err)
+	assert.True(t, link.Private())
+	original, err := store.Get("https://ya.ru")
+	require.NoError(t, err)
+	assert.Equal(t, "6YGS4ZUF", original)
+}
+
+func TestCacheStorage_IdempotencyRecord(t *testing.T) {
+	ctx := context.Background()
+	s := NewCacheStorage()
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	otherUserID := jwtService.EnsureRandom()
+
+	require.NoError(t, s.SaveIdempotencyRecord(ctx, models.IdempotencyRecord{
+		UserID:      &userID,
+		Key:         "active",
+		RequestHash: "hash",
+		StatusCode:  201,
+		Body:        []byte("body"),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}))
+	require.NoError(t, s.SaveIdempotencyRecord(ctx, models.IdempotencyRecord{
+		UserID:    &userID,
+		Key:       "expired",
+		ExpiresAt: time.Now().Add(-time.Second),
+	}))
+
+	record, err := s.GetIdempotencyRecord(ctx, &userID, "active")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, 201, record.StatusCode)
+	assert.Equal(t, []byte("body"), record.Body)
+
+	record, err = s.GetIdempotencyRecord(ctx, &otherUserID, "active")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	record, err = s.GetIdempotencyRecord(ctx, &userID, "expired")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestFileStorage_IdempotencyCompaction(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+	userID := auth.NewJwtService("verycomplexsecretkey").EnsureRandom()
+	lines := func() int {
+		data, err := os.ReadFile(path + idempotencyFileSuffix)
+		require.NoError(t, err)
+		return bytes.Count(data, []byte("\n"))
+	}
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveIdempotencyRecord(ctx, models.IdempotencyRecord{UserID: &userID, Key: "expired", ExpiresAt: time.Now().Add(time.Second)}))
+	// записи ключа перезаписываются, файл сжимается до действующих записей
+	for i := 0; i < idempotencyCompactLines; i++ {
+		require.NoError(t, store.SaveIdempotencyRecord(ctx, models.IdempotencyRecord{
+			UserID:      &userID,
+			Key:         "active",
+			RequestHash: strconv.Itoa(i),
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}))
+	}
+	assert.Less(t, lines(), idempotencyCompactLines)
+
+	time.Sleep(time.Second)
+	// при открытии просроченные записи удаляются из файла
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, lines())
+
+	record, err := reopened.GetIdempotencyRecord(ctx, &userID, "active")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, strconv.Itoa(idempotencyCompactLines-1), record.RequestHash)
+}
+
+func TestCacheStorage_IncrementClicks(t *testing.T) {
+	ctx := context.Background()
+	store := NewCacheStorage()
+	_, err := store.SaveLink(ctx, models.StorageURL{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF", MaxClicks: 3})
+	require.NoError(t, err)
+
+	// переходы по ссылке выполняются одновременно, лимит не должен быть превышен
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	counted, exhausted := 0, 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := store.IncrementClicks(ctx, "6YGS4ZUF")
+
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, ErrClicksExhausted) {
+				exhausted++
+				return
+			}
+			assert.NoError(t, err)
+			counted++
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 3, counted)
+	assert.Equal(t, 7, exhausted)
+
+	link, err := store.GetLink(ctx, "6YGS4ZUF")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), link.Clicks)
+}
+
+func TestFileStorage_Clicks(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+	lines := func(path string) int {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return bytes.Count(data, []byte("\n"))
+	}
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+	_, err = store.SaveLink(ctx, models.StorageURL{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF"})
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.IncrementClicks(ctx, "6YGS4ZUF"))
+	}
+
+	// переходы не дописывают ссылку в файл URL
+	assert.Equal(t, 1, lines(path))
+	assert.Equal(t, 5, lines(path+clicksFileSuffix))
+
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+	link, err := reopened.GetLink(ctx, "6YGS4ZUF")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), link.Clicks)
+	assert.Equal(t, 1, lines(path+clicksFileSuffix))
+}
+
+func TestFileStorage_VariantClicks(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+	lines := func(path string) int {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return bytes.Count(data, []byte("\n"))
+	}
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+	_, err = store.SaveLink(ctx, models.StorageURL{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF", Variants: []models.Variant{
+		{ID: "a", URL: "https://ya.ru/a", Weight: 1},
+		{ID: "b", URL: "https://ya.ru/b", Weight: 1},
+	}})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.IncrementVariantClicks(ctx, "6YGS4ZUF", "a"))
+	}
+	require.NoError(t, store.IncrementVariantClicks(ctx, "6YGS4ZUF", "b"))
+	require.NoError(t, store.IncrementVariantClicks(ctx, "6YGS4ZUF", "unknown"))
+
+	// переходы по вариантам не дописывают ссылку в файл URL
+	assert.Equal(t, 1, lines(path))
+	assert.Equal(t, 4, lines(path+variantClicksFileSuffix))
+
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+	link, err := reopened.GetLink(ctx, "6YGS4ZUF")
+	require.NoError(t, err)
+	require.Len(t, link.Variants, 2)
+	assert.Equal(t, int64(3), link.Variants[0].Clicks)
+	assert.Equal(t, int64(1), link.Variants[1].Clicks)
+	assert.Equal(t, 2, lines(path+variantClicksFileSuffix))
+}
+
+func TestCacheStorage_GetLinksForCheck(t *testing.T) {
+	ctx := context.Background()
+	store := NewCacheStorage()
+
+	for _, link := range []models.StorageURL{
+		{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF"},
+		{OriginalURL: "https://dzen.ru/once", ShortURL: "NUPLJNFJ", MaxClicks: 1},
+		{OriginalURL: "https://dzen.ru/secret", ShortURL: "Q0VLhuIm", PasswordHash: "hash"},
+	} {
+		_, err := store.SaveLink(ctx, link)
+		require.NoError(t, err)
+	}
+
+	// запрос к адресу ссылки с лимитом переходов или паролем может израсходовать одноразовый секрет
+	links, err := store.GetLinksForCheck(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "6YGS4ZUF", links[0].ShortURL)
+}
+
+func TestFileStorage_LinkChecks(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+	lines := func(path string) int {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return bytes.Count(data, []byte("\n"))
+	}
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+	_, err = store.SaveLink(ctx, models.StorageURL{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF"})
+	require.NoError(t, err)
+
+	// проверка без смены статуса не пишется в файлы
+	checkedAt := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.SaveLinkCheck(ctx, "6YGS4ZUF", http.StatusOK, checkedAt.Add(time.Duration(i)*time.Minute)))
+	}
+	require.NoError(t, store.SaveLinkCheck(ctx, "6YGS4ZUF", http.StatusNotFound, checkedAt.Add(time.Hour)))
+	assert.Equal(t, 1, lines(path))
+	assert.Equal(t, 2, lines(path+checksFileSuffix))
+
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+	link, err := reopened.GetLink(ctx, "6YGS4ZUF")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, link.LastStatus)
+	require.NotNil(t, link.LastCheckedAt)
+	assert.True(t, checkedAt.Add(time.Hour).Equal(*link.LastCheckedAt))
+	assert.Equal(t, 1, lines(path+checksFileSuffix))
+}
+
+func TestFileStorage_Collections(t *testing.T) {
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveCollection(ctx, models.Collection{ID: "news", UserID: &userID, Name: "News"}))
+	require.NoError(t, store.SaveCollection(ctx, models.Collection{ID: "mail", UserID: &userID, Name: "Mail"}))
+	require.NoError(t, store.SaveCollection(ctx, models.Collection{ID: "news", UserID: &userID, Name: "Новости", Description: "Ленты"}))
+	_, err = store.SaveLink(ctx, models.StorageURL{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", CollectionID: "news"})
+	require.NoError(t, err)
+	_, err = store.SaveLink(ctx, models.StorageURL{UserID: &userID, ShortURL: "xKh7DnOW", OriginalURL: "https://mail.ru", CollectionID: "mail"})
+	require.NoError(t, err)
+
+	// ссылки удаленной коллекции остаются без коллекции
+	require.NoError(t, store.DeleteCollection(ctx, "mail"))
+
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+
+	collections, err := reopened.GetCollections(ctx, &userID)
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+	assert.Equal(t, "Новости", collections[0].Name)
+	assert.Equal(t, "Ленты", collections[0].Description)
+
+	deleted, err := reopened.GetCollection(ctx, "mail")
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+
+	link, err := reopened.GetLink(ctx, "xKh7DnOW")
+	require.NoError(t, err)
+	assert.Empty(t, link.CollectionID)
+	link, err = reopened.GetLink(ctx, "6YGS4ZUF")
+	require.NoError(t, err)
+	assert.Equal(t, "news", link.CollectionID)
+}
+
+func TestFileStorage_Workspaces(t *testing.T) {
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	owner := jwtService.EnsureRandom()
+	editor := jwtService.EnsureRandom()
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveWorkspace(ctx, models.Workspace{ID: "team", Name: "Team"}))
+	require.NoError(t, store.SaveWorkspace(ctx, models.Workspace{ID: "old", Name: "Old"}))
+	require.NoError(t, store.SaveWorkspace(ctx, models.Workspace{ID: "team", Name: "Команда"}))
+	require.NoError(t, store.SaveWorkspaceMember(ctx, models.WorkspaceMember{WorkspaceID: "team", UserID: &owner, Role: models.WorkspaceRoleOwner}))
+	require.NoError(t, store.SaveWorkspaceMember(ctx, models.WorkspaceMember{WorkspaceID: "team", UserID: &editor, Role: models.WorkspaceRoleViewer}))
+	require.NoError(t, store.SaveWorkspaceMember(ctx, models.WorkspaceMember{WorkspaceID: "team", UserID: &editor, Role: models.WorkspaceRoleEditor}))
+	require.NoError(t, store.SaveWorkspaceMember(ctx, models.WorkspaceMember{WorkspaceID: "old", UserID: &owner, Role: models.WorkspaceRoleOwner}))
+	_, err = store.SaveLink(ctx, models.StorageURL{UserID: &owner, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", WorkspaceID: "team"})
+	require.NoError(t, err)
+	_, err = store.SaveLink(ctx, models.StorageURL{UserID: &owner, ShortURL: "xKh7DnOW", OriginalURL: "https://mail.ru", WorkspaceID: "old"})
+	require.NoError(t, err)
+
+	// ссылки пространства удаляются только пакетом пространства
+	require.NoError(t, store.DeleteBatch(ctx, &owner, []string{"6YGS4ZUF"}))
+	// ссылки удаленного пространства возвращаются создателям
+	require.NoError(t, store.DeleteWorkspace(ctx, "old"))
+
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+
+	workspaces, err := reopened.GetUserWorkspaces(ctx, &editor)
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+	assert.Equal(t, "Команда", workspaces[0].Name)
+	assert.Equal(t, models.WorkspaceRoleEditor, workspaces[0].Role)
+
+	members, err := reopened.GetWorkspaceMembers(ctx, "old")
+	require.NoError(t, err)
+	assert.Empty(t, members)
+
+	link, err := reopened.GetLink(ctx, "xKh7DnOW")
+	require.NoError(t, err)
+	assert.Empty(t, link.WorkspaceID)
+
+	link, err = reopened.GetLink(ctx, "6YGS4ZUF")
+	require.NoError(t, err)
+	assert.False(t, link.DeletedFlag)
+	require.NoError(t, reopened.DeleteWorkspaceBatch(ctx, "team", []string{"6YGS4ZUF"}))
+	link, err = reopened.GetLink(ctx, "6YGS4ZUF")
+	require.NoError(t, err)
+	assert.True(t, link.DeletedFlag)
+}
+
+func TestFileStorage_Accounts(t *testing.T) {
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	anonymous := jwtService.EnsureRandom()
+	registered := jwtService.EnsureRandom()
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+
+	account := models.Account{UserID: &registered, Email: "user@example.com", PasswordHash: "hash"}
+	require.NoError(t, store.SaveAccount(ctx, account))
+	other := jwtService.EnsureRandom()
+	err = store.SaveAccount(ctx, models.Account{UserID: &other, Email: "user@example.com", PasswordHash: "hash"})
+	assert.ErrorIs(t, err, ErrConflict)
+
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, store.SaveSession(ctx, models.Session{ID: "active", UserID: &registered, ExpiresAt: expiresAt}))
+	require.NoError(t, store.SaveSession(ctx, models.Session{ID: "finished", UserID: &registered, ExpiresAt: expiresAt}))
+	require.NoError(t, store.DeleteSession(ctx, "finished"))
+
+	_, err = store.SaveLink(ctx, models.StorageURL{UserID: &anonymous, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru"})
+	require.NoError(t, err)
+	_, err = store.SaveLink(ctx, models.StorageURL{UserID: &anonymous, ShortURL: "x+5vpM8W", OriginalURL: "https://dzen.ru"})
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteBatch(ctx, &anonymous, []string{"x+5vpM8W"}))
+
+	// удаленные ссылки переносятся, но не учитываются
+	claimed, err := store.ClaimUrls(ctx, &anonymous, &registered)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+
+	saved, err := reopened.GetAccountByEmail(ctx, "user@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, registered, *saved.UserID)
+	assert.Equal(t, "hash", saved.PasswordHash)
+
+	saved, err = reopened.GetAccount(ctx, &anonymous)
+	require.NoError(t, err)
+	assert.Nil(t, saved)
+
+	session, err := reopened.GetSession(ctx, "active")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.True(t, expiresAt.Equal(session.ExpiresAt))
+	session, err = reopened.GetSession(ctx, "finished")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+
+	for _, id := range []string{"6YGS4ZUF", "x+5vpM8W"} {
+		link, err := reopened.GetLink(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, registered, *link.UserID)
+	}
+}
+
+func TestFileStorage_DeleteExpiredSessions(t *testing.T) {
+	registered := auth.NewJwtService("verycomplexsecretkey").EnsureRandom()
+	ctx := context.Background()
+	path := t.TempDir() + "/urls.json"
+
+	store, err := NewFileStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveAccount(ctx, models.Account{UserID: &registered, Email: "user@example.com", PasswordHash: "hash"}))
+	require.NoError(t, store.SaveSession(ctx, models.Session{ID: "active", UserID: &registered, ExpiresAt: time.Now().Add(time.Hour)}))
+	require.NoError(t, store.SaveSession(ctx, models.Session{ID: "expired", UserID: &registered, ExpiresAt: time.Now().Add(-time.Second)}))
+	require.NoError(t, store.SaveSession(ctx, models.Session{ID: "finished", UserID: &registered, ExpiresAt: time.Now().Add(time.Hour)}))
+	require.NoError(t, store.DeleteSession(ctx, "finished"))
+
+	require.NoError(t, store.DeleteExpiredSessions(ctx))
+
+	session, err := store.GetSession(ctx, "expired")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+
+	// файл переписан с аккаунтом и действующей сессией
+	data, err := os.ReadFile(path + accountsFileSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+	info, err := os.Stat(path + accountsFileSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reopened, err := NewFileStorage(path)
+	require.NoError(t, err)
+	saved, err := reopened.GetAccountByEmail(ctx, "user@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	session, err = reopened.GetSession(ctx, "active")
+	require.NoError(t, err)
+	assert.NotNil(t, session)
+	for _, id := range []string{"expired", "finished"} {
+		session, err = reopened.GetSession(ctx, id)
+		require.NoError(t, err)
+		assert.Nil(t, session)
+	}
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gofrs/uuid"
@@ -13,6 +14,7 @@ import (
 	"github.com/romanp1989/go-shortener/internal/models"
 	"log"
 	"sync"
+	"time"
 )
 
 // SQLDB database operations interface
@@ -36,51 +38,338 @@ const SaveInsertQuery = `INSERT INTO urls(short_url, original_url, user_id)
 VALUES ($1, $2, $3)
 RETURNING short_url`
 
-// GetSelectQuery get url by short or original url
-const GetSelectQuery = `SELECT short_url, original_url, deleted_flag FROM urls WHERE short_url = $1 or original_url = $1`
+// GetSelectQuery get url by short url
+const GetSelectQuery = `SELECT short_url, original_url, deleted_flag FROM urls WHERE short_url = $1`
 
-// SaveBatchInsertQuery insert query for batch save urls
+// GetByOriginalSelectQuery get short url by domain and original url, url with clicks limit or password isn't found
+const GetByOriginalSelectQuery = `SELECT short_url, original_url, deleted_flag FROM urls
+WHERE domain = $1 and original_url = $2 and max_clicks = 0 and password_hash = ''`
+
+// SaveBatchInsertQuery insert query for batch save urls, code of existing public url isn't changed and is returned
 const SaveBatchInsertQuery = `INSERT INTO urls (short_url, original_url, user_id) 
 			 	VALUES %s
-				ON CONFLICT (original_url) DO UPDATE SET short_url = EXCLUDED.short_url, original_url = EXCLUDED.original_url
+				ON CONFLICT (domain, original_url) WHERE max_clicks = 0 AND password_hash = '' DO UPDATE SET original_url = EXCLUDED.original_url
 				RETURNING short_url`
 
-// DeleteBatchQuery delete urls by user
+// DeleteBatchQuery delete urls by user, urls of workspaces are deleted by DeleteWorkspaceBatchQuery
 const DeleteBatchQuery = `UPDATE urls
 			SET deleted_flag = true
-			WHERE user_id = $1 and short_url = ANY($2)`
+			WHERE user_id = $1 and workspace_id = '' and short_url = ANY($2)`
+
+// DeleteWorkspaceBatchQuery delete urls of workspace
+const DeleteWorkspaceBatchQuery = `UPDATE urls
+			SET deleted_flag = true
+			WHERE workspace_id = $1 and short_url = ANY($2)`
 
 // GetAllUrlsByUserSelectQuery get all urls by user
-const GetAllUrlsByUserSelectQuery = `SELECT short_url, original_url FROM urls WHERE user_id = $1 and length(short_url) > 0`
+const GetAllUrlsByUserSelectQuery = `SELECT short_url, original_url, title, page FROM urls WHERE user_id = $1 and length(short_url) > 0`
+
+// iterateColumns columns of urls with metadata read by iteration queries
+const iterateColumns = `user_id, short_url, original_url, title, notes, tags, coalesce(deleted_flag, false), created_at, interstitial, password_hash, clicks, max_clicks,
+       active_from, active_until, fallback_url, rules, variants, sticky_variants, forward_query, utm, last_status, last_checked_at, page, collection_id, workspace_id, ` + variantClicksSubquery
+
+// IterateUrlsByUserSelectQuery get all urls by user with metadata for export
+const IterateUrlsByUserSelectQuery = `SELECT ` + iterateColumns + `
+FROM urls
+WHERE user_id = $1 and length(short_url) > 0
+ORDER BY id`
+
+// IterateUrlsByWorkspaceSelectQuery get all urls of workspace with metadata
+const IterateUrlsByWorkspaceSelectQuery = `SELECT ` + iterateColumns + `
+FROM urls
+WHERE workspace_id = $1 and length(short_url) > 0
+ORDER BY id`
+
+// GetUrlsPageByUserSelectQuery get page of not deleted urls by user with metadata, newest urls are first
+const GetUrlsPageByUserSelectQuery = `SELECT ` + iterateColumns + `
+FROM urls
+WHERE user_id = $1 and length(short_url) > 0 and NOT coalesce(deleted_flag, false)
+ORDER BY created_at DESC, short_url
+LIMIT $2 OFFSET $3`
+
+// CountUrlsByUserSelectQuery count not deleted urls by user
+const CountUrlsByUserSelectQuery = `SELECT count(*) FROM urls WHERE user_id = $1 and length(short_url) > 0 and NOT coalesce(deleted_flag, false)`
+
+// GetUrlsPageByWorkspaceSelectQuery get page of not deleted urls of workspace with metadata, newest urls are first
+const GetUrlsPageByWorkspaceSelectQuery = `SELECT ` + iterateColumns + `
+FROM urls
+WHERE workspace_id = $1 and length(short_url) > 0 and NOT coalesce(deleted_flag, false)
+ORDER BY created_at DESC, short_url
+LIMIT $2 OFFSET $3`
+
+// CountUrlsByWorkspaceSelectQuery count not deleted urls of workspace
+const CountUrlsByWorkspaceSelectQuery = `SELECT count(*) FROM urls WHERE workspace_id = $1 and length(short_url) > 0 and NOT coalesce(deleted_flag, false)`
+
+// SaveLinkInsertQuery insert query for save url with metadata
+const SaveLinkInsertQuery = `INSERT INTO urls(short_url, original_url, user_id, title, notes, tags, interstitial, password_hash, max_clicks,
+	active_from, active_until, fallback_url, rules, variants, sticky_variants, forward_query, utm, collection_id, workspace_id) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+RETURNING short_url`
+
+// SearchSelectQuery full-text and trigram search in user's urls
+const SearchSelectQuery = `SELECT short_url, original_url, title, notes, tags,
+       ts_rank(urls_search_vector(title, notes, tags, original_url), plainto_tsquery('simple', $2)) + similarity(original_url, $2) AS rank
+FROM urls
+WHERE user_id = $1 AND NOT coalesce(deleted_flag, false)
+  AND (urls_search_vector(title, notes, tags, original_url) @@ plainto_tsquery('simple', $2) OR original_url % $2)
+ORDER BY rank DESC, short_url
+LIMIT $3`
+
+// GetLinkSelectQuery get url with metadata by short url
+const GetLinkSelectQuery = `SELECT user_id, short_url, original_url, title, notes, tags, coalesce(deleted_flag, false), created_at, clicks, interstitial, password_hash, max_clicks,
+       active_from, active_until, fallback_url, rules, variants, sticky_variants, forward_query, utm, page, collection_id, workspace_id, ` + variantClicksSubquery + `
+FROM urls
+WHERE short_url = $1`
+
+// UpdateLinkQuery update url metadata and settings
+const UpdateLinkQuery = `UPDATE urls SET title = $2, notes = $3, tags = $4, interstitial = $5, password_hash = $6, max_clicks = $7,
+	active_from = $8, active_until = $9, fallback_url = $10, rules = $11, variants = $12, sticky_variants = $13,
+	forward_query = $14, utm = $15, collection_id = $16, workspace_id = $17
+WHERE short_url = $1`
+
+// variantClicksSubquery clicks of url variants as json object with variant id keys
+const variantClicksSubquery = `(SELECT coalesce(jsonb_object_agg(variant, clicks), '{}') FROM variant_clicks WHERE variant_clicks.short_url = urls.short_url)`
+
+// IncrementVariantClicksQuery increment redirects count of url variant
+const IncrementVariantClicksQuery = `INSERT INTO variant_clicks(short_url, variant, clicks) VALUES ($1, $2, 1)
+ON CONFLICT (short_url, variant) DO UPDATE SET clicks = variant_clicks.clicks + 1`
+
+// IncrementClicksQuery increment redirects count of url if clicks limit isn't reached
+const IncrementClicksQuery = `UPDATE urls SET clicks = clicks + 1 WHERE short_url = $1 AND (max_clicks = 0 OR clicks < max_clicks)`
+
+// GetScheduledLinksSelectQuery get not deleted urls with active window of all users
+const GetScheduledLinksSelectQuery = `SELECT user_id, short_url, original_url, active_from, active_until, fallback_url
+FROM urls
+WHERE NOT coalesce(deleted_flag, false) AND (active_from IS NOT NULL OR active_until IS NOT NULL)
+ORDER BY active_from NULLS FIRST, short_url`
+
+// GetLinksForCheckSelectQuery get not deleted urls without clicks limit and password not checked since time, never checked urls go first
+const GetLinksForCheckSelectQuery = `SELECT user_id, short_url, original_url, last_status, last_checked_at
+FROM urls
+WHERE NOT coalesce(deleted_flag, false) AND length(short_url) > 0 AND max_clicks = 0 AND password_hash = ''
+  AND (last_checked_at IS NULL OR last_checked_at < $1)
+ORDER BY last_checked_at NULLS FIRST, id
+LIMIT $2`
+
+// SaveLinkCheckQuery save result of url destination check
+const SaveLinkCheckQuery = `UPDATE urls SET last_status = $2, last_checked_at = $3 WHERE short_url = $1`
+
+// SavePageMetadataQuery save title, description and image of url destination page
+const SavePageMetadataQuery = `UPDATE urls SET page = $2 WHERE short_url = $1`
+
+// GetUserSettingsSelectQuery get user's settings
+const GetUserSettingsSelectQuery = `SELECT domain FROM user_settings WHERE user_id = $1`
+
+// SaveUserSettingsQuery insert or update user's settings
+const SaveUserSettingsQuery = `INSERT INTO user_settings(user_id, domain) VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET domain = EXCLUDED.domain`
+
+// GetCollectionsSelectQuery get user's collections
+const GetCollectionsSelectQuery = `SELECT id, name, description, created_at FROM collections WHERE user_id = $1 ORDER BY created_at, id`
+
+// GetCollectionSelectQuery get collection by id
+const GetCollectionSelectQuery = `SELECT user_id, name, description, created_at FROM collections WHERE id = $1`
+
+// SaveCollectionQuery insert collection or update name and description of existing one
+const SaveCollectionQuery = `INSERT INTO collections(id, user_id, name, description) VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description`
+
+// ClearCollectionQuery remove urls from collection
+const ClearCollectionQuery = `UPDATE urls SET collection_id = '' WHERE collection_id = $1`
+
+// DeleteCollectionQuery delete collection
+const DeleteCollectionQuery = `DELETE FROM collections WHERE id = $1`
+
+// GetUserWorkspacesSelectQuery get workspaces of user with user's role
+const GetUserWorkspacesSelectQuery = `SELECT w.id, w.name, w.created_at, m.role
+FROM workspaces w
+JOIN workspace_members m ON m.workspace_id = w.id
+WHERE m.user_id = $1
+ORDER BY w.created_at, w.id`
+
+// GetWorkspaceSelectQuery get workspace by id
+const GetWorkspaceSelectQuery = `SELECT name, created_at FROM workspaces WHERE id = $1`
+
+// SaveWorkspaceQuery insert workspace or rename existing one
+const SaveWorkspaceQuery = `INSERT INTO workspaces(id, name) VALUES ($1, $2)
+ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name`
+
+// ClearWorkspaceQuery return urls of workspace to their creators
+const ClearWorkspaceQuery = `UPDATE urls SET workspace_id = '' WHERE workspace_id = $1`
+
+// DeleteWorkspaceMembersQuery delete all members of workspace
+const DeleteWorkspaceMembersQuery = `DELETE FROM workspace_members WHERE workspace_id = $1`
+
+// DeleteWorkspaceQuery delete workspace
+const DeleteWorkspaceQuery = `DELETE FROM workspaces WHERE id = $1`
+
+// GetWorkspaceMembersSelectQuery get members of workspace
+const GetWorkspaceMembersSelectQuery = `SELECT user_id, role FROM workspace_members WHERE workspace_id = $1 ORDER BY user_id`
+
+// SaveWorkspaceMemberQuery insert member of workspace or update member's role
+const SaveWorkspaceMemberQuery = `INSERT INTO workspace_members(workspace_id, user_id, role) VALUES ($1, $2, $3)
+ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+
+// DeleteWorkspaceMemberQuery delete member of workspace
+const DeleteWorkspaceMemberQuery = `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`
+
+// GetAccountSelectQuery get account by user id
+const GetAccountSelectQuery = `SELECT email, password_hash, created_at FROM accounts WHERE user_id = $1`
+
+// GetAccountByEmailSelectQuery get account by email
+const GetAccountByEmailSelectQuery = `SELECT user_id, password_hash, created_at FROM accounts WHERE email = $1`
+
+// SaveAccountQuery insert account
+const SaveAccountQuery = `INSERT INTO accounts(user_id, email, password_hash) VALUES ($1, $2, $3)`
+
+// GetSessionSelectQuery get login session by id
+const GetSessionSelectQuery = `SELECT user_id, expires_at FROM sessions WHERE id = $1`
+
+// SaveSessionQuery insert login session or prolong existing one
+const SaveSessionQuery = `INSERT INTO sessions(id, user_id, expires_at) VALUES ($1, $2, $3)
+ON CONFLICT (id) DO UPDATE SET expires_at = EXCLUDED.expires_at`
+
+// DeleteSessionQuery delete login session
+const DeleteSessionQuery = `DELETE FROM sessions WHERE id = $1`
+
+// DeleteExpiredSessionsQuery delete expired login sessions
+const DeleteExpiredSessionsQuery = `DELETE FROM sessions WHERE expires_at <= now()`
+
+// ClaimUrlsQuery move urls of one user to another one and count moved not deleted urls
+const ClaimUrlsQuery = `WITH moved AS (
+	UPDATE urls SET user_id = $2 WHERE user_id = $1 RETURNING deleted_flag
+)
+SELECT count(*) FROM moved WHERE NOT coalesce(deleted_flag, false)`
+
+// ClaimCollectionsQuery move collections of one user to another one
+const ClaimCollectionsQuery = `UPDATE collections SET user_id = $2 WHERE user_id = $1`
+
+// GetIdempotencySelectQuery get not expired saved response by user and idempotency key
+const GetIdempotencySelectQuery = `SELECT request_hash, status_code, content_type, body, expires_at
+FROM idempotency_keys
+WHERE user_id = $1 AND key = $2 AND expires_at > now()`
+
+// SaveIdempotencyInsertQuery insert or replace saved response for user and idempotency key
+const SaveIdempotencyInsertQuery = `INSERT INTO idempotency_keys(user_id, key, request_hash, status_code, content_type, body, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id, key) DO UPDATE SET request_hash = EXCLUDED.request_hash, status_code = EXCLUDED.status_code,
+	content_type = EXCLUDED.content_type, body = EXCLUDED.body, expires_at = EXCLUDED.expires_at`
+
+// DeleteExpiredIdempotencyQuery delete expired idempotency keys
+const DeleteExpiredIdempotencyQuery = `DELETE FROM idempotency_keys WHERE expires_at <= now()`
 
 // GetStats get users, urls count
 const GetStats = `SELECT count(distinct user_id), count(distinct short_ulr) FROM urls`
 
-// NewDB factory for create DB storage
-func NewDB(DBPath string) *DBStorage {
-	db, err := sql.Open("pgx", DBPath)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	createUrlsTableQuery := `CREATE TABLE IF NOT EXISTS urls(
+// migrations schema changes applied on start, each statement must be idempotent
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS urls(
 		id serial primary key,
 		user_id uuid not null,
 		short_url varchar(255) not null,
-		original_url varchar(255) not null);
-                               
-	    CREATE UNIQUE INDEX IF NOT EXISTS original_url_idx ON urls (original_url);`
-	_, err = db.Exec(createUrlsTableQuery)
+		original_url varchar(255) not null)`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS deleted_flag boolean`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS title text not null default '',
+		ADD COLUMN IF NOT EXISTS notes text not null default '',
+		ADD COLUMN IF NOT EXISTS tags text[] not null default '{}'`,
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE OR REPLACE FUNCTION urls_search_vector(title text, notes text, tags text[], original_url text) RETURNS tsvector AS $$
+		SELECT setweight(to_tsvector('simple', title), 'A') ||
+			setweight(to_tsvector('simple', array_to_string(tags, ' ')), 'B') ||
+			setweight(to_tsvector('simple', notes), 'C') ||
+			setweight(to_tsvector('simple', regexp_replace(original_url, '[^[:alnum:]]+', ' ', 'g')), 'D')
+	$$ LANGUAGE sql IMMUTABLE`,
+	`CREATE INDEX IF NOT EXISTS urls_search_idx ON urls USING gin (urls_search_vector(title, notes, tags, original_url))`,
+	`CREATE INDEX IF NOT EXISTS urls_original_url_trgm_idx ON urls USING gin (original_url gin_trgm_ops)`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS created_at timestamptz not null default now()`,
+	`CREATE TABLE IF NOT EXISTS idempotency_keys(
+		user_id uuid not null,
+		key varchar(255) not null,
+		request_hash varchar(64) not null,
+		status_code integer not null,
+		content_type varchar(255) not null default '',
+		body bytea not null,
+		expires_at timestamptz not null,
+		primary key (user_id, key))`,
+	`CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at)`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS clicks bigint not null default 0`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS interstitial boolean not null default false`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS password_hash text not null default ''`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS max_clicks bigint not null default 0`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS active_from timestamptz,
+		ADD COLUMN IF NOT EXISTS active_until timestamptz,
+		ADD COLUMN IF NOT EXISTS fallback_url text not null default ''`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS rules jsonb not null default '[]'`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS variants jsonb not null default '[]',
+		ADD COLUMN IF NOT EXISTS sticky_variants boolean not null default false`,
+	`CREATE TABLE IF NOT EXISTS variant_clicks(
+		short_url varchar(255) not null,
+		variant varchar(64) not null,
+		clicks bigint not null default 0,
+		primary key (short_url, variant))`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS forward_query boolean not null default false,
+		ADD COLUMN IF NOT EXISTS utm jsonb not null default '{}'`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS last_status integer not null default 0,
+		ADD COLUMN IF NOT EXISTS last_checked_at timestamptz`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS page jsonb not null default '{}'`,
+	// домен хранится в ключе короткой ссылки, один URL может быть сокращен на каждом домене
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS domain text GENERATED ALWAYS AS (split_part(short_url, '@', 2)) STORED`,
+	`DROP INDEX IF EXISTS original_url_idx`,
+	// ссылки с лимитом переходов или паролем получают случайный код и не занимают URL на домене
+	`DROP INDEX IF EXISTS urls_domain_original_url_idx`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS urls_domain_public_original_url_idx ON urls (domain, original_url)
+		WHERE max_clicks = 0 AND password_hash = ''`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS urls_short_url_idx ON urls (short_url)`,
+	`CREATE TABLE IF NOT EXISTS user_settings(
+		user_id uuid primary key,
+		domain text not null default '')`,
+	`CREATE TABLE IF NOT EXISTS collections(
+		id varchar(36) primary key,
+		user_id uuid not null,
+		name text not null,
+		description text not null default '',
+		created_at timestamptz not null default now())`,
+	`CREATE INDEX IF NOT EXISTS collections_user_id_idx ON collections (user_id)`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS collection_id varchar(36) not null default ''`,
+	`CREATE INDEX IF NOT EXISTS urls_collection_id_idx ON urls (collection_id)`,
+	`CREATE TABLE IF NOT EXISTS workspaces(
+		id varchar(36) primary key,
+		name text not null,
+		created_at timestamptz not null default now())`,
+	`CREATE TABLE IF NOT EXISTS workspace_members(
+		workspace_id varchar(36) not null,
+		user_id uuid not null,
+		role varchar(16) not null,
+		primary key (workspace_id, user_id))`,
+	`CREATE INDEX IF NOT EXISTS workspace_members_user_id_idx ON workspace_members (user_id)`,
+	`ALTER TABLE urls ADD COLUMN IF NOT EXISTS workspace_id varchar(36) not null default ''`,
+	`CREATE INDEX IF NOT EXISTS urls_workspace_id_idx ON urls (workspace_id)`,
+	`CREATE TABLE IF NOT EXISTS accounts(
+		user_id uuid primary key,
+		email text not null unique,
+		password_hash text not null,
+		created_at timestamptz not null default now())`,
+	`CREATE TABLE IF NOT EXISTS sessions(
+		id varchar(64) primary key,
+		user_id uuid not null,
+		expires_at timestamptz not null)`,
+	`CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id)`,
+	`CREATE INDEX IF NOT EXISTS urls_user_id_created_at_idx ON urls (user_id, created_at DESC)`,
+}
+
+// NewDB factory for create DB storage
+func NewDB(DBPath string) *DBStorage {
+	db, err := sql.Open("pgx", DBPath)
 	if err != nil {
 		log.Fatal(err)
-		return nil
 	}
 
-	addColumnDeletedFlag := `ALTER TABLE urls ADD COLUMN IF NOT EXISTS deleted_flag boolean`
-	_, err = db.Exec(addColumnDeletedFlag)
-	if err != nil {
-		log.Fatal(err)
-		return nil
+	for _, migration := range migrations {
+		_, err = db.Exec(migration)
+		if err != nil {
+			log.Fatal(err)
+			return nil
+		}
 	}
 
 	return &DBStorage{
@@ -102,21 +391,86 @@ func (d *DBStorage) Save(ctx context.Context, originalURL string, shortURL strin
 	err := d.db.QueryRowContext(ctx, SaveInsertQuery, shortURL, originalURL, userID).Scan(&insertedURL)
 	if err != nil {
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			err = ErrConflict
-			return "", NewURLConflictError(shortURL, err)
+			existingURL, lookupErr := d.conflictURL(ctx, models.StorageURL{ShortURL: shortURL, OriginalURL: originalURL})
+			if lookupErr != nil {
+				return "", lookupErr
+			}
+			return "", NewURLConflictError(existingURL, ErrConflict)
 		}
 		return "", err
 	}
 	return insertedURL, nil
 }
 
+// SaveLink function for save URL with metadata in DB
+func (d *DBStorage) SaveLink(ctx context.Context, link models.StorageURL) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var insertedURL string
+	var pgErr *pgconn.PgError
+
+	rules, err := jsonRules(link.Rules)
+	if err != nil {
+		return "", err
+	}
+	variants, err := jsonVariants(link.Variants)
+	if err != nil {
+		return "", err
+	}
+	utm, err := jsonUTM(link.UTM)
+	if err != nil {
+		return "", err
+	}
+
+	err = d.db.QueryRowContext(ctx, SaveLinkInsertQuery, link.ShortURL, link.OriginalURL, link.UserID,
+		link.Title, link.Notes, textArray(link.Tags), link.Interstitial, link.PasswordHash, link.MaxClicks,
+		link.ActiveFrom, link.ActiveUntil, link.FallbackURL, rules, variants, link.StickyVariants, link.ForwardQuery, utm, link.CollectionID, link.WorkspaceID).Scan(&insertedURL)
+	if err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			existingURL, lookupErr := d.conflictURL(ctx, link)
+			if lookupErr != nil {
+				return "", lookupErr
+			}
+			return "", NewURLConflictError(existingURL, ErrConflict)
+		}
+		return "", err
+	}
+	return insertedURL, nil
+}
+
+// conflictURL function returns short URL of existing public link with the same domain and original URL,
+// short URL of link is returned for private link or if such link isn't found
+func (d *DBStorage) conflictURL(ctx context.Context, link models.StorageURL) (string, error) {
+	if link.Private() {
+		return link.ShortURL, nil
+	}
+
+	var short, original string
+	var deletedFlag sql.NullBool
+	err := d.db.QueryRowContext(ctx, GetByOriginalSelectQuery, linkOriginalKey(link).domain, link.OriginalURL).Scan(&short, &original, &deletedFlag)
+	if errors.Is(err, sql.ErrNoRows) {
+		return link.ShortURL, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot scan row: %w", err)
+	}
+	return short, nil
+}
+
 // Get function for get URL from DB
 func (d *DBStorage) Get(inputURL string) (string, error) {
 	var short, original string
 	var deletedFlag sql.NullBool
 
 	row := d.db.QueryRowContext(context.Background(), GetSelectQuery, inputURL)
-	if err := row.Scan(&short, &original, &deletedFlag); err != nil {
+	err := row.Scan(&short, &original, &deletedFlag)
+	if errors.Is(err, sql.ErrNoRows) {
+		// по исходному URL ищется ссылка домена по умолчанию
+		row = d.db.QueryRowContext(context.Background(), GetByOriginalSelectQuery, "", inputURL)
+		err = row.Scan(&short, &original, &deletedFlag)
+	}
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", nil
 		}
@@ -233,13 +587,17 @@ func (d *DBStorage) GetAllUrlsByUser(ctx context.Context, userID *uuid.UUID) ([]
 
 	for rows.Next() {
 		var store models.StorageURL
-		err = rows.Scan(&store.ShortURL, &store.OriginalURL)
+		var page []byte
+		err = rows.Scan(&store.ShortURL, &store.OriginalURL, &store.Title, &page)
 		if err != nil {
 			if !errors.Is(err, sql.ErrNoRows) {
 				return nil, err
 			}
 			return nil, nil
 		}
+		if store.Page, err = parsePage(page); err != nil {
+			return nil, err
+		}
 		storageURLs = append(storageURLs, store)
 	}
 
@@ -250,6 +608,93 @@ func (d *DBStorage) GetAllUrlsByUser(ctx context.Context, userID *uuid.UUID) ([]
 	return storageURLs, nil
 }
 
+// IterateUrlsByUser function calls fn for every user's URL including deleted ones, rows are read from DB one by one
+func (d *DBStorage) IterateUrlsByUser(ctx context.Context, userID *uuid.UUID, fn func(models.StorageURL) error) error {
+	return d.iterateUrls(ctx, IterateUrlsByUserSelectQuery, fn, userID)
+}
+
+// IterateUrlsByWorkspace function calls fn for every URL of workspace including deleted ones, rows are read from DB one by one
+func (d *DBStorage) IterateUrlsByWorkspace(ctx context.Context, workspaceID string, fn func(models.StorageURL) error) error {
+	return d.iterateUrls(ctx, IterateUrlsByWorkspaceSelectQuery, fn, workspaceID)
+}
+
+// GetUrlsPageByUser function for get page of user's not deleted URLs with count of all of them, newest URLs are first
+func (d *DBStorage) GetUrlsPageByUser(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]models.StorageURL, int, error) {
+	return d.getUrlsPage(ctx, GetUrlsPageByUserSelectQuery, CountUrlsByUserSelectQuery, userID, limit, offset)
+}
+
+// GetUrlsPageByWorkspace function for get page of not deleted URLs of workspace with count of all of them, newest URLs are first
+func (d *DBStorage) GetUrlsPageByWorkspace(ctx context.Context, workspaceID string, limit int, offset int) ([]models.StorageURL, int, error) {
+	return d.getUrlsPage(ctx, GetUrlsPageByWorkspaceSelectQuery, CountUrlsByWorkspaceSelectQuery, workspaceID, limit, offset)
+}
+
+// getUrlsPage function selects page of URLs by page query and count of all URLs by count query
+func (d *DBStorage) getUrlsPage(ctx context.Context, pageQuery string, countQuery string, arg any, limit int, offset int) ([]models.StorageURL, int, error) {
+	var total int
+	if err := d.db.QueryRowContext(ctx, countQuery, arg).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	storageURLs := make([]models.StorageURL, 0, limit)
+	if offset >= total {
+		return storageURLs, total, nil
+	}
+
+	err := d.iterateUrls(ctx, pageQuery, func(link models.StorageURL) error {
+		storageURLs = append(storageURLs, link)
+		return nil
+	}, arg, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return storageURLs, total, nil
+}
+
+// iterateUrls function calls fn for every URL selected by iteration query
+func (d *DBStorage) iterateUrls(ctx context.Context, query string, fn func(models.StorageURL) error, args ...any) error {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var store models.StorageURL
+		var userID uuid.UUID
+		var tags pgtype.TextArray
+		var rules, variants, utm, page, variantClicks []byte
+		err = rows.Scan(&userID, &store.ShortURL, &store.OriginalURL, &store.Title, &store.Notes, &tags, &store.DeletedFlag, &store.CreatedAt, &store.Interstitial, &store.PasswordHash, &store.Clicks, &store.MaxClicks,
+			&store.ActiveFrom, &store.ActiveUntil, &store.FallbackURL, &rules, &variants, &store.StickyVariants, &store.ForwardQuery, &utm,
+			&store.LastStatus, &store.LastCheckedAt, &page, &store.CollectionID, &store.WorkspaceID, &variantClicks)
+		if err != nil {
+			return err
+		}
+		if err = tags.AssignTo(&store.Tags); err != nil {
+			return err
+		}
+		if store.Rules, err = parseRules(rules); err != nil {
+			return err
+		}
+		if store.Variants, err = parseVariants(variants, variantClicks); err != nil {
+			return err
+		}
+		if store.UTM, err = parseUTM(utm); err != nil {
+			return err
+		}
+		if store.Page, err = parsePage(page); err != nil {
+			return err
+		}
+		store.UserID = &userID
+
+		if err = fn(store); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // Ping function for ping DB connection
 func (d *DBStorage) Ping(ctx context.Context) error {
 	if err := d.db.PingContext(ctx); err != nil {
@@ -268,3 +713,634 @@ func (d *DBStorage) GetStats(ctx context.Context) (models.StorageStats, error) {
 	}
 	return stats, nil
 }
+
+// Search function for full-text search in user's URLs
+func (d *DBStorage) Search(ctx context.Context, userID *uuid.UUID, query string, limit int) ([]models.SearchResult, error) {
+	results := make([]models.SearchResult, 0)
+	rows, err := d.db.QueryContext(ctx, SearchSelectQuery, userID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result models.SearchResult
+		var tags pgtype.TextArray
+		if err = rows.Scan(&result.ShortURL, &result.OriginalURL, &result.Title, &result.Notes, &tags, &result.Rank); err != nil {
+			return nil, err
+		}
+		if err = tags.AssignTo(&result.Tags); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetLink function for get URL with metadata by short URL, nil is returned if URL isn't found
+func (d *DBStorage) GetLink(ctx context.Context, shortURL string) (*models.StorageURL, error) {
+	var link models.StorageURL
+	var userID uuid.UUID
+	var tags pgtype.TextArray
+	var rules, variants, utm, page, variantClicks []byte
+
+	row := d.db.QueryRowContext(ctx, GetLinkSelectQuery, shortURL)
+	err := row.Scan(&userID, &link.ShortURL, &link.OriginalURL, &link.Title, &link.Notes, &tags, &link.DeletedFlag, &link.CreatedAt, &link.Clicks, &link.Interstitial, &link.PasswordHash, &link.MaxClicks,
+		&link.ActiveFrom, &link.ActiveUntil, &link.FallbackURL, &rules, &variants, &link.StickyVariants, &link.ForwardQuery, &utm, &page, &link.CollectionID, &link.WorkspaceID, &variantClicks)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+	if err = tags.AssignTo(&link.Tags); err != nil {
+		return nil, err
+	}
+	if link.Rules, err = parseRules(rules); err != nil {
+		return nil, err
+	}
+	if link.Variants, err = parseVariants(variants, variantClicks); err != nil {
+		return nil, err
+	}
+	if link.UTM, err = parseUTM(utm); err != nil {
+		return nil, err
+	}
+	if link.Page, err = parsePage(page); err != nil {
+		return nil, err
+	}
+	link.UserID = &userID
+
+	return &link, nil
+}
+
+// UpdateLink function for update title, notes, tags and settings of URL
+func (d *DBStorage) UpdateLink(ctx context.Context, link models.StorageURL) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rules, err := jsonRules(link.Rules)
+	if err != nil {
+		return err
+	}
+	variants, err := jsonVariants(link.Variants)
+	if err != nil {
+		return err
+	}
+	utm, err := jsonUTM(link.UTM)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.db.ExecContext(ctx, UpdateLinkQuery, link.ShortURL, link.Title, link.Notes, textArray(link.Tags), link.Interstitial, link.PasswordHash, link.MaxClicks,
+		link.ActiveFrom, link.ActiveUntil, link.FallbackURL, rules, variants, link.StickyVariants, link.ForwardQuery, utm, link.CollectionID, link.WorkspaceID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		// ставшая публичной ссылка конфликтует с публичной ссылкой того же URL
+		existingURL, lookupErr := d.conflictURL(ctx, link)
+		if lookupErr != nil {
+			return lookupErr
+		}
+		return NewURLConflictError(existingURL, ErrConflict)
+	}
+	return err
+}
+
+// IncrementVariantClicks function for counting redirect to A/B variant of short URL
+func (d *DBStorage) IncrementVariantClicks(ctx context.Context, shortURL string, variantID string) error {
+	_, err := d.db.ExecContext(ctx, IncrementVariantClicksQuery, shortURL, variantID)
+	return err
+}
+
+// IncrementClicks function for counting redirect by short URL, ErrClicksExhausted is returned if clicks limit is reached.
+// Limit is checked in the same UPDATE, so concurrent redirects can't exceed it
+func (d *DBStorage) IncrementClicks(ctx context.Context, shortURL string) error {
+	res, err := d.db.ExecContext(ctx, IncrementClicksQuery, shortURL)
+	if err != nil {
+		return err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	// URL проверяется до перехода, поэтому не обновленная строка означает исчерпанный лимит
+	if updated == 0 {
+		return ErrClicksExhausted
+	}
+
+	return nil
+}
+
+// GetLinksForCheck function for get not deleted URLs not checked since checkedBefore, never checked URLs go first.
+// URLs with clicks limit or password aren't checked, request to destination can use up one-time secret
+func (d *DBStorage) GetLinksForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.StorageURL, error) {
+	links := make([]models.StorageURL, 0)
+	rows, err := d.db.QueryContext(ctx, GetLinksForCheckSelectQuery, checkedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var link models.StorageURL
+		var userID uuid.UUID
+		if err = rows.Scan(&userID, &link.ShortURL, &link.OriginalURL, &link.LastStatus, &link.LastCheckedAt); err != nil {
+			return nil, err
+		}
+		link.UserID = &userID
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// SaveLinkCheck function for save status and time of URL destination check
+func (d *DBStorage) SaveLinkCheck(ctx context.Context, shortURL string, status int, checkedAt time.Time) error {
+	_, err := d.db.ExecContext(ctx, SaveLinkCheckQuery, shortURL, status, checkedAt)
+	return err
+}
+
+// GetUserSettings function for get user's settings, nil is returned if settings aren't saved
+func (d *DBStorage) GetUserSettings(ctx context.Context, userID *uuid.UUID) (*models.UserSettings, error) {
+	settings := models.UserSettings{UserID: userID}
+	err := d.db.QueryRowContext(ctx, GetUserSettingsSelectQuery, userID).Scan(&settings.Domain)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveUserSettings function for save user's settings
+func (d *DBStorage) SaveUserSettings(ctx context.Context, settings models.UserSettings) error {
+	_, err := d.db.ExecContext(ctx, SaveUserSettingsQuery, settings.UserID, settings.Domain)
+	return err
+}
+
+// GetCollections function for get user's collections ordered by creation time
+func (d *DBStorage) GetCollections(ctx context.Context, userID *uuid.UUID) ([]models.Collection, error) {
+	collections := make([]models.Collection, 0)
+	rows, err := d.db.QueryContext(ctx, GetCollectionsSelectQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		collection := models.Collection{UserID: userID}
+		if err = rows.Scan(&collection.ID, &collection.Name, &collection.Description, &collection.CreatedAt); err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// GetCollection function for get collection by id, nil is returned if collection isn't found
+func (d *DBStorage) GetCollection(ctx context.Context, id string) (*models.Collection, error) {
+	collection := models.Collection{ID: id}
+	var userID uuid.UUID
+
+	err := d.db.QueryRowContext(ctx, GetCollectionSelectQuery, id).Scan(&userID, &collection.Name, &collection.Description, &collection.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+	collection.UserID = &userID
+
+	return &collection, nil
+}
+
+// SaveCollection function for create collection or update name and description of existing one
+func (d *DBStorage) SaveCollection(ctx context.Context, collection models.Collection) error {
+	_, err := d.db.ExecContext(ctx, SaveCollectionQuery, collection.ID, collection.UserID, collection.Name, collection.Description)
+	return err
+}
+
+// DeleteCollection function for delete collection, its links are kept without collection
+func (d *DBStorage) DeleteCollection(ctx context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, ClearCollectionQuery, id); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, DeleteCollectionQuery, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetUserWorkspaces function for get workspaces of user with user's role ordered by creation time
+func (d *DBStorage) GetUserWorkspaces(ctx context.Context, userID *uuid.UUID) ([]models.Workspace, error) {
+	workspaces := make([]models.Workspace, 0)
+	rows, err := d.db.QueryContext(ctx, GetUserWorkspacesSelectQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workspace models.Workspace
+		if err = rows.Scan(&workspace.ID, &workspace.Name, &workspace.CreatedAt, &workspace.Role); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// GetWorkspace function for get workspace by id, nil is returned if workspace isn't found
+func (d *DBStorage) GetWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
+	workspace := models.Workspace{ID: id}
+
+	err := d.db.QueryRowContext(ctx, GetWorkspaceSelectQuery, id).Scan(&workspace.Name, &workspace.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+// SaveWorkspace function for create workspace or rename existing one
+func (d *DBStorage) SaveWorkspace(ctx context.Context, workspace models.Workspace) error {
+	_, err := d.db.ExecContext(ctx, SaveWorkspaceQuery, workspace.ID, workspace.Name)
+	return err
+}
+
+// DeleteWorkspace function for delete workspace with members, its links are returned to their creators
+func (d *DBStorage) DeleteWorkspace(ctx context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{ClearWorkspaceQuery, DeleteWorkspaceMembersQuery, DeleteWorkspaceQuery} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetWorkspaceMembers function for get members of workspace ordered by user id
+func (d *DBStorage) GetWorkspaceMembers(ctx context.Context, workspaceID string) ([]models.WorkspaceMember, error) {
+	members := make([]models.WorkspaceMember, 0)
+	rows, err := d.db.QueryContext(ctx, GetWorkspaceMembersSelectQuery, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		member := models.WorkspaceMember{WorkspaceID: workspaceID}
+		var userID uuid.UUID
+		if err = rows.Scan(&userID, &member.Role); err != nil {
+			return nil, err
+		}
+		member.UserID = &userID
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// SaveWorkspaceMember function for add member to workspace or change member's role
+func (d *DBStorage) SaveWorkspaceMember(ctx context.Context, member models.WorkspaceMember) error {
+	_, err := d.db.ExecContext(ctx, SaveWorkspaceMemberQuery, member.WorkspaceID, member.UserID, member.Role)
+	return err
+}
+
+// DeleteWorkspaceMember function for remove member from workspace
+func (d *DBStorage) DeleteWorkspaceMember(ctx context.Context, workspaceID string, userID *uuid.UUID) error {
+	_, err := d.db.ExecContext(ctx, DeleteWorkspaceMemberQuery, workspaceID, userID)
+	return err
+}
+
+// DeleteWorkspaceBatch function for delete URLs list of workspace
+func (d *DBStorage) DeleteWorkspaceBatch(ctx context.Context, workspaceID string, urls []string) error {
+	urlList := new(pgtype.VarcharArray)
+	if err := urlList.Set(urls); err != nil {
+		return fmt.Errorf("ошибка при формировании списка url для удаления: %v", err)
+	}
+
+	_, err := d.db.ExecContext(ctx, DeleteWorkspaceBatchQuery, workspaceID, urlList)
+	return err
+}
+
+// GetAccount function for get account by user id, nil is returned if user isn't registered
+func (d *DBStorage) GetAccount(ctx context.Context, userID *uuid.UUID) (*models.Account, error) {
+	if userID == nil {
+		return nil, nil
+	}
+
+	account := models.Account{UserID: userID}
+	err := d.db.QueryRowContext(ctx, GetAccountSelectQuery, userID).Scan(&account.Email, &account.PasswordHash, &account.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+
+	return &account, nil
+}
+
+// GetAccountByEmail function for get account by email, nil is returned if email isn't registered
+func (d *DBStorage) GetAccountByEmail(ctx context.Context, email string) (*models.Account, error) {
+	account := models.Account{Email: email}
+	var userID uuid.UUID
+
+	err := d.db.QueryRowContext(ctx, GetAccountByEmailSelectQuery, email).Scan(&userID, &account.PasswordHash, &account.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+
+	account.UserID = &userID
+	return &account, nil
+}
+
+// SaveAccount function for create account, ErrConflict is returned if email or user id is taken
+func (d *DBStorage) SaveAccount(ctx context.Context, account models.Account) error {
+	_, err := d.db.ExecContext(ctx, SaveAccountQuery, account.UserID, account.Email, account.PasswordHash)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// GetSession function for get login session by id, nil is returned if session isn't found
+func (d *DBStorage) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	session := models.Session{ID: id}
+	var userID uuid.UUID
+
+	err := d.db.QueryRowContext(ctx, GetSessionSelectQuery, id).Scan(&userID, &session.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+
+	session.UserID = &userID
+	return &session, nil
+}
+
+// SaveSession function for save login session
+func (d *DBStorage) SaveSession(ctx context.Context, session models.Session) error {
+	_, err := d.db.ExecContext(ctx, SaveSessionQuery, session.ID, session.UserID, session.ExpiresAt)
+	return err
+}
+
+// DeleteSession function for delete login session
+func (d *DBStorage) DeleteSession(ctx context.Context, id string) error {
+	_, err := d.db.ExecContext(ctx, DeleteSessionQuery, id)
+	return err
+}
+
+// DeleteExpiredSessions function for delete expired login sessions
+func (d *DBStorage) DeleteExpiredSessions(ctx context.Context) error {
+	_, err := d.db.ExecContext(ctx, DeleteExpiredSessionsQuery)
+	return err
+}
+
+// ClaimUrls function for move URLs and collections of one user to another one,
+// count of moved not deleted URLs is returned
+func (d *DBStorage) ClaimUrls(ctx context.Context, from *uuid.UUID, to *uuid.UUID) (int, error) {
+	if from == nil || to == nil || *from == *to {
+		return 0, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var claimed int
+	if err = tx.QueryRowContext(ctx, ClaimUrlsQuery, from, to).Scan(&claimed); err != nil {
+		return 0, err
+	}
+	if _, err = tx.ExecContext(ctx, ClaimCollectionsQuery, from, to); err != nil {
+		return 0, err
+	}
+
+	return claimed, tx.Commit()
+}
+
+// SavePageMetadata function for save title, description and image of URL destination page
+func (d *DBStorage) SavePageMetadata(ctx context.Context, shortURL string, page models.PageMetadata) error {
+	value, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.db.ExecContext(ctx, SavePageMetadataQuery, shortURL, value)
+	return err
+}
+
+// GetScheduledLinks function for get not deleted URLs with active window of all users
+func (d *DBStorage) GetScheduledLinks(ctx context.Context) ([]models.StorageURL, error) {
+	links := make([]models.StorageURL, 0)
+	rows, err := d.db.QueryContext(ctx, GetScheduledLinksSelectQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var link models.StorageURL
+		var userID uuid.UUID
+		if err = rows.Scan(&userID, &link.ShortURL, &link.OriginalURL, &link.ActiveFrom, &link.ActiveUntil, &link.FallbackURL); err != nil {
+			return nil, err
+		}
+		link.UserID = &userID
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// GetIdempotencyRecord function for get saved response by user and idempotency key
+func (d *DBStorage) GetIdempotencyRecord(ctx context.Context, userID *uuid.UUID, key string) (*models.IdempotencyRecord, error) {
+	record := models.IdempotencyRecord{UserID: userID, Key: key}
+
+	row := d.db.QueryRowContext(ctx, GetIdempotencySelectQuery, userID, key)
+	err := row.Scan(&record.RequestHash, &record.StatusCode, &record.ContentType, &record.Body, &record.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot scan row: %w", err)
+	}
+
+	return &record, nil
+}
+
+// SaveIdempotencyRecord function for save response of request with idempotency key, expired keys are removed
+func (d *DBStorage) SaveIdempotencyRecord(ctx context.Context, record models.IdempotencyRecord) error {
+	if _, err := d.db.ExecContext(ctx, DeleteExpiredIdempotencyQuery); err != nil {
+		return err
+	}
+
+	_, err := d.db.ExecContext(ctx, SaveIdempotencyInsertQuery, record.UserID, record.Key, record.RequestHash,
+		record.StatusCode, record.ContentType, record.Body, record.ExpiresAt)
+
+	return err
+}
+
+// textArray function converts tags to postgres text array, empty array is used instead of NULL
+func textArray(values []string) *pgtype.TextArray {
+	array := new(pgtype.TextArray)
+	if values == nil {
+		values = []string{}
+	}
+	_ = array.Set(values)
+	return array
+}
+
+// jsonRules function converts redirect rules to jsonb value, empty array is used instead of NULL
+func jsonRules(rules []models.RedirectRule) ([]byte, error) {
+	if rules == nil {
+		rules = []models.RedirectRule{}
+	}
+	return json.Marshal(rules)
+}
+
+// parseRules function reads redirect rules from jsonb value
+func parseRules(value []byte) ([]models.RedirectRule, error) {
+	var rules []models.RedirectRule
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(value, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse redirect rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return rules, nil
+}
+
+// jsonVariants function converts A/B variants to jsonb value, clicks are kept in variant_clicks table
+func jsonVariants(variants []models.Variant) ([]byte, error) {
+	stored := make([]models.Variant, 0, len(variants))
+	for _, variant := range variants {
+		variant.Clicks = 0
+		stored = append(stored, variant)
+	}
+	return json.Marshal(stored)
+}
+
+// parseVariants function reads A/B variants from jsonb value and sets their clicks
+func parseVariants(value []byte, clicksValue []byte) ([]models.Variant, error) {
+	var variants []models.Variant
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(value, &variants); err != nil {
+		return nil, fmt.Errorf("cannot parse variants: %w", err)
+	}
+	if len(variants) == 0 {
+		return nil, nil
+	}
+
+	clicks := make(map[string]int64)
+	if len(clicksValue) > 0 {
+		if err := json.Unmarshal(clicksValue, &clicks); err != nil {
+			return nil, fmt.Errorf("cannot parse variant clicks: %w", err)
+		}
+	}
+	for i := range variants {
+		variants[i].Clicks = clicks[variants[i].ID]
+	}
+	return variants, nil
+}
+
+// jsonUTM function converts UTM parameters to jsonb value, empty object is used instead of NULL
+func jsonUTM(utm *models.UTMParams) ([]byte, error) {
+	if utm == nil {
+		utm = &models.UTMParams{}
+	}
+	return json.Marshal(utm)
+}
+
+// parseUTM function reads UTM parameters from jsonb value, nil is returned if no parameter is set
+func parseUTM(value []byte) (*models.UTMParams, error) {
+	var utm models.UTMParams
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(value, &utm); err != nil {
+		return nil, fmt.Errorf("cannot parse utm parameters: %w", err)
+	}
+	if utm == (models.UTMParams{}) {
+		return nil, nil
+	}
+	return &utm, nil
+}
+
+// parsePage function reads destination page metadata from jsonb value, nil is returned if page isn't fetched yet
+func parsePage(value []byte) (*models.PageMetadata, error) {
+	var page models.PageMetadata
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(value, &page); err != nil {
+		return nil, fmt.Errorf("cannot parse page metadata: %w", err)
+	}
+	if page == (models.PageMetadata{}) {
+		return nil, nil
+	}
+	return &page, nil
+}
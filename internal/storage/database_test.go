@@ -2,14 +2,19 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/pgtype"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/romanp1989/go-shortener/internal/auth"
 	"github.com/romanp1989/go-shortener/internal/models"
+	"reflect"
 	"slices"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestDBStorage_Save(t *testing.T) {
@@ -72,6 +77,47 @@ func TestDBStorage_Save(t *testing.T) {
 	}
 }
 
+func TestDBStorage_SaveLinkConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	uniqueViolation := &pgconn.PgError{Code: pgerrcode.UniqueViolation}
+	// код существующей публичной ссылки URL отличается от запрошенного
+	mock.ExpectQuery("INSERT INTO urls").
+		WillReturnError(uniqueViolation)
+	mock.ExpectQuery("SELECT short_url, original_url, deleted_flag FROM urls").
+		WithArgs("go.example.com", "https://ya.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"short_url", "original_url", "deleted_flag"}).AddRow("NUPLJNFJ@go.example.com", "https://ya.ru", false))
+	mock.ExpectExec("UPDATE urls SET title").
+		WillReturnError(uniqueViolation)
+	mock.ExpectQuery("SELECT short_url, original_url, deleted_flag FROM urls").
+		WithArgs("", "https://ya.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"short_url", "original_url", "deleted_flag"}).AddRow("6YGS4ZUF", "https://ya.ru", false))
+
+	var errConflict *URLConflictError
+	_, err = store.SaveLink(context.Background(), models.StorageURL{ShortURL: "6YGS4ZUF@go.example.com", OriginalURL: "https://ya.ru"})
+	if !errors.As(err, &errConflict) || errConflict.URL != "NUPLJNFJ@go.example.com" {
+		t.Errorf("SaveLink() error = %v, want conflict with NUPLJNFJ@go.example.com", err)
+	}
+
+	err = store.UpdateLink(context.Background(), models.StorageURL{ShortURL: "Q0VLhuIm", OriginalURL: "https://ya.ru"})
+	if !errors.As(err, &errConflict) || errConflict.URL != "6YGS4ZUF" {
+		t.Errorf("UpdateLink() error = %v, want conflict with 6YGS4ZUF", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestDBStorage_Get(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -91,8 +137,12 @@ func TestDBStorage_Get(t *testing.T) {
 	mock.ExpectQuery("SELECT short_url, original_url, deleted_flag FROM urls").
 		WithArgs("6YGS4ZUF").
 		WillReturnRows(sqlmock.NewRows([]string{"short_url", "original_url", "deleted_flag"}).AddRow("6YGS4ZUF", "https://ya.ru", false))
-	mock.ExpectQuery("SELECT short_url, original_url, deleted_flag FROM urls").
+	// по исходному URL ищется ссылка домена по умолчанию
+	mock.ExpectQuery("SELECT short_url, original_url, deleted_flag FROM urls WHERE short_url").
 		WithArgs("https://ya.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"short_url", "original_url", "deleted_flag"}))
+	mock.ExpectQuery("SELECT short_url, original_url, deleted_flag FROM urls\\s+WHERE domain").
+		WithArgs("", "https://ya.ru").
 		WillReturnRows(sqlmock.NewRows([]string{"short_url", "original_url", "deleted_flag"}).AddRow("6YGS4ZUF", "https://ya.ru", false))
 
 	tests := []struct {
@@ -192,6 +242,37 @@ func TestDBStorage_SaveBatch(t *testing.T) {
 	}
 }
 
+func TestDBStorage_SaveBatchConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	// код существующей публичной ссылки не перезаписывается и возвращается
+	mock.ExpectBegin()
+	mock.ExpectQuery("ON CONFLICT \\(domain, original_url\\) WHERE max_clicks = 0 AND password_hash = '' DO UPDATE SET original_url = EXCLUDED.original_url\\s+RETURNING short_url").
+		WithArgs("6YGS4ZUF", "https://ya.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"short_url"}).AddRow("NUPLJNFJ"))
+	mock.ExpectCommit()
+
+	got, err := store.SaveBatch(context.Background(), []models.StorageURL{{OriginalURL: "https://ya.ru", ShortURL: "6YGS4ZUF"}}, nil)
+	if err != nil {
+		t.Fatalf("SaveBatch() error = %v", err)
+	}
+	if !slices.Equal(got, []string{"NUPLJNFJ"}) {
+		t.Errorf("SaveBatch() got = %v, want %v", got, []string{"NUPLJNFJ"})
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestDBStorage_DeleteBatch(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -250,3 +331,407 @@ func TestDBStorage_DeleteBatch(t *testing.T) {
 		})
 	}
 }
+
+func TestDBStorage_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+
+	mock.ExpectQuery("SELECT short_url, original_url, title, notes, tags").
+		WithArgs(&userID, "search", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"short_url", "original_url", "title", "notes", "tags", "rank"}).
+			AddRow("6YGS4ZUF", "https://ya.ru", "Yandex search", "", "{search,main}", 0.6))
+
+	got, err := store.Search(context.Background(), &userID, "search", 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	want := []models.SearchResult{
+		{
+			ShortURL:    "6YGS4ZUF",
+			OriginalURL: "https://ya.ru",
+			Title:       "Yandex search",
+			Tags:        []string{"search", "main"},
+			Rank:        0.6,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Search() got = %v, want %v", got, want)
+	}
+}
+
+func TestDBStorage_IterateUrlsByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	createdAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT user_id, short_url, original_url, title, notes, tags, coalesce").
+		WithArgs(&userID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "short_url", "original_url", "title", "notes", "tags", "deleted_flag", "created_at", "interstitial", "password_hash", "clicks", "max_clicks",
+			"active_from", "active_until", "fallback_url", "rules", "variants", "sticky_variants", "forward_query", "utm", "last_status", "last_checked_at", "page", "collection_id", "workspace_id", "variant_clicks"}).
+			AddRow(userID.String(), "6YGS4ZUF", "https://ya.ru", "", "", "{}", false, createdAt, true, "", 3, 0, nil, nil, "", []byte(`[{"device":"ios","url":"https://apps.apple.com"}]`),
+				[]byte(`[{"id":"a","url":"https://ya.ru/a","weight":70},{"id":"b","url":"https://ya.ru/b","weight":30}]`), true, true, []byte(`{"utm_source":"news","utm_campaign":"{id}"}`), 404, createdAt, []byte(`{"title":"Яндекс"}`), "", "", []byte(`{"a":2}`)).
+			AddRow(userID.String(), "x+5vpM8W", "https://dzen.ru", "Dzen", "", "{news}", true, createdAt, false, "$argon2id$hash", 1, 1, createdAt, nil, "https://ya.ru", []byte(`[]`),
+				[]byte(`[]`), false, false, []byte(`{}`), 0, nil, []byte(`{}`), "b1c5a0a4-3c3e-4d5e-9f1a-0a6b2c7d8e9f", "team", []byte(`{}`)))
+
+	var got []models.StorageURL
+	err = store.IterateUrlsByUser(context.Background(), &userID, func(link models.StorageURL) error {
+		got = append(got, link)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("IterateUrlsByUser() error = %v", err)
+	}
+
+	want := []models.StorageURL{
+		{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", Tags: []string{}, CreatedAt: createdAt, Interstitial: true, Clicks: 3,
+			Rules:          []models.RedirectRule{{Device: "ios", URL: "https://apps.apple.com"}},
+			Variants:       []models.Variant{{ID: "a", URL: "https://ya.ru/a", Weight: 70, Clicks: 2}, {ID: "b", URL: "https://ya.ru/b", Weight: 30}},
+			StickyVariants: true, ForwardQuery: true, UTM: &models.UTMParams{Source: "news", Campaign: "{id}"},
+			LastStatus: 404, LastCheckedAt: &createdAt, Page: &models.PageMetadata{Title: "Яндекс"}},
+		{UserID: &userID, ShortURL: "x+5vpM8W", OriginalURL: "https://dzen.ru", Title: "Dzen", Tags: []string{"news"}, DeletedFlag: true, CreatedAt: createdAt, PasswordHash: "$argon2id$hash", Clicks: 1, MaxClicks: 1,
+			ActiveFrom: &createdAt, FallbackURL: "https://ya.ru", CollectionID: "b1c5a0a4-3c3e-4d5e-9f1a-0a6b2c7d8e9f", WorkspaceID: "team"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IterateUrlsByUser() got = %v, want %v", got, want)
+	}
+}
+
+func TestDBStorage_GetUrlsPageByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	createdAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT count").
+		WithArgs(&userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT user_id, short_url, original_url, title, notes, tags, coalesce(.+)ORDER BY created_at DESC, short_url(.+)LIMIT").
+		WithArgs(&userID, 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "short_url", "original_url", "title", "notes", "tags", "deleted_flag", "created_at", "interstitial", "password_hash", "clicks", "max_clicks",
+			"active_from", "active_until", "fallback_url", "rules", "variants", "sticky_variants", "forward_query", "utm", "last_status", "last_checked_at", "page", "collection_id", "workspace_id", "variant_clicks"}).
+			AddRow(userID.String(), "6YGS4ZUF", "https://ya.ru", "", "", "{}", false, createdAt, false, "", 0, 0, nil, nil, "", []byte(`[]`),
+				[]byte(`[]`), false, false, []byte(`{}`), 0, nil, []byte(`{}`), "", "", []byte(`{}`)))
+
+	got, total, err := store.GetUrlsPageByUser(context.Background(), &userID, 1, 2)
+	if err != nil {
+		t.Fatalf("GetUrlsPageByUser() error = %v", err)
+	}
+	if total != 3 {
+		t.Errorf("GetUrlsPageByUser() total = %v, want %v", total, 3)
+	}
+	want := []models.StorageURL{{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru", Tags: []string{}, CreatedAt: createdAt}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUrlsPageByUser() got = %v, want %v", got, want)
+	}
+
+	// страница за пределами списка не запрашивается
+	mock.ExpectQuery("SELECT count").
+		WithArgs(&userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	got, total, err = store.GetUrlsPageByUser(context.Background(), &userID, 1, 3)
+	if err != nil || total != 3 || len(got) != 0 {
+		t.Errorf("GetUrlsPageByUser() got = %v, %v, %v, want empty page of 3", got, total, err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_IncrementClicks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	mock.ExpectExec("UPDATE urls SET clicks = clicks \\+ 1").
+		WithArgs("6YGS4ZUF").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE urls SET clicks = clicks \\+ 1").
+		WithArgs("6YGS4ZUF").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err = store.IncrementClicks(context.Background(), "6YGS4ZUF"); err != nil {
+		t.Fatalf("IncrementClicks() error = %v", err)
+	}
+	if err = store.IncrementClicks(context.Background(), "6YGS4ZUF"); !errors.Is(err, ErrClicksExhausted) {
+		t.Errorf("IncrementClicks() error = %v, want %v", err, ErrClicksExhausted)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_LinkCheck(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+	checkedAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	checkedBefore := checkedAt.Add(time.Hour)
+
+	mock.ExpectQuery("SELECT user_id, short_url, original_url, last_status, last_checked_at .+ max_clicks = 0 AND password_hash = ''").
+		WithArgs(checkedBefore, 500).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "short_url", "original_url", "last_status", "last_checked_at"}).
+			AddRow(userID, "6YGS4ZUF", "https://ya.ru", 0, nil).
+			AddRow(userID, "x+5vpM8W", "https://dzen.ru", 500, checkedAt))
+	mock.ExpectExec("UPDATE urls SET last_status").
+		WithArgs("6YGS4ZUF", 404, checkedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	got, err := store.GetLinksForCheck(context.Background(), checkedBefore, 500)
+	if err != nil {
+		t.Fatalf("GetLinksForCheck() error = %v", err)
+	}
+	want := []models.StorageURL{
+		{UserID: &userID, ShortURL: "6YGS4ZUF", OriginalURL: "https://ya.ru"},
+		{UserID: &userID, ShortURL: "x+5vpM8W", OriginalURL: "https://dzen.ru", LastStatus: 500, LastCheckedAt: &checkedAt},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetLinksForCheck() got = %v, want %v", got, want)
+	}
+
+	if err = store.SaveLinkCheck(context.Background(), "6YGS4ZUF", 404, checkedBefore); err != nil {
+		t.Fatalf("SaveLinkCheck() error = %v", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_SavePageMetadata(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	mock.ExpectExec("UPDATE urls SET page").
+		WithArgs("6YGS4ZUF", []byte(`{"title":"Яндекс","image":"https://ya.ru/logo.png"}`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = store.SavePageMetadata(context.Background(), "6YGS4ZUF", models.PageMetadata{Title: "Яндекс", Image: "https://ya.ru/logo.png"})
+	if err != nil {
+		t.Fatalf("SavePageMetadata() error = %v", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_UserSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	jwtService := auth.NewJwtService("verycomplexsecretkey")
+	userID := jwtService.EnsureRandom()
+
+	mock.ExpectQuery("SELECT domain FROM user_settings").
+		WithArgs(&userID).
+		WillReturnRows(sqlmock.NewRows([]string{"domain"}))
+	mock.ExpectExec("INSERT INTO user_settings").
+		WithArgs(&userID, "go.brand.ru").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT domain FROM user_settings").
+		WithArgs(&userID).
+		WillReturnRows(sqlmock.NewRows([]string{"domain"}).AddRow("go.brand.ru"))
+
+	got, err := store.GetUserSettings(context.Background(), &userID)
+	if err != nil || got != nil {
+		t.Fatalf("GetUserSettings() got = %v, error = %v, want nil", got, err)
+	}
+	if err = store.SaveUserSettings(context.Background(), models.UserSettings{UserID: &userID, Domain: "go.brand.ru"}); err != nil {
+		t.Fatalf("SaveUserSettings() error = %v", err)
+	}
+	got, err = store.GetUserSettings(context.Background(), &userID)
+	if err != nil {
+		t.Fatalf("GetUserSettings() error = %v", err)
+	}
+	want := &models.UserSettings{UserID: &userID, Domain: "go.brand.ru"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUserSettings() got = %v, want %v", got, want)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_DeleteCollection(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE urls SET collection_id = ''").
+		WithArgs("news").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM collections").
+		WithArgs("news").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err = store.DeleteCollection(context.Background(), "news"); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_DeleteWorkspace(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE urls SET workspace_id = ''").
+		WithArgs("team").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM workspace_members").
+		WithArgs("team").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM workspaces").
+		WithArgs("team").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err = store.DeleteWorkspace(context.Background(), "team"); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_DeleteExpiredSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+
+	mock.ExpectExec("DELETE FROM sessions WHERE expires_at <= now\\(\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err = store.DeleteExpiredSessions(context.Background()); err != nil {
+		t.Fatalf("DeleteExpiredSessions() error = %v", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBStorage_ClaimUrls(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	store := DBStorage{
+		db: db,
+		mu: sync.RWMutex{},
+	}
+	from := uuid.Must(uuid.NewV4())
+	to := uuid.Must(uuid.NewV4())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("WITH moved AS").
+		WithArgs(&from, &to).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectExec("UPDATE collections SET user_id").
+		WithArgs(&from, &to).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	claimed, err := store.ClaimUrls(context.Background(), &from, &to)
+	if err != nil {
+		t.Fatalf("ClaimUrls() error = %v", err)
+	}
+	if claimed != 2 {
+		t.Errorf("ClaimUrls() claimed = %d, want 2", claimed)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -8,6 +8,9 @@ import (
 // ErrConflict data already exists
 var ErrConflict = errors.New("данные уже существуют")
 
+// ErrClicksExhausted all redirects of link with clicks limit are used
+var ErrClicksExhausted = errors.New("лимит переходов по ссылке исчерпан")
+
 // URLConflictError structure for url conflict error, if URL already exists in DB
 type URLConflictError struct {
 	URL string
@@ -2,19 +2,61 @@ package storage
 
 import (
 	"bufio"
-	"context"
 	"encoding/json"
-	"github.com/gofrs/uuid"
 	"github.com/romanp1989/go-shortener/internal/models"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 )
 
-// FileStorage File storage
+// clicksFileSuffix suffix of file with counted redirects of URLs near URLs file
+const clicksFileSuffix = ".clicks"
+
+// variantClicksFileSuffix suffix of file with counted redirects to A/B variants of URLs near URLs file
+const variantClicksFileSuffix = ".variants"
+
+// checksFileSuffix suffix of file with changed statuses of URLs destination checks near URLs file
+const checksFileSuffix = ".checks"
+
+// idempotencyFileSuffix suffix of file with idempotency records near URLs file
+const idempotencyFileSuffix = ".idempotency"
+
+// idempotencyCompactLines min count of lines in idempotency file for its compaction
+const idempotencyCompactLines = 1000
+
+// settingsFileSuffix suffix of file with users' settings near URLs file
+const settingsFileSuffix = ".settings"
+
+// collectionsFileSuffix suffix of file with users' collections near URLs file
+const collectionsFileSuffix = ".collections"
+
+// workspacesFileSuffix suffix of file with workspaces and their members near URLs file
+const workspacesFileSuffix = ".workspaces"
+
+// accountsFileSuffix suffix of file with accounts and login sessions near URLs file
+const accountsFileSuffix = ".accounts"
+
+// collectionRecord record of collections file, deleted collection is written with deleted flag
+type collectionRecord struct {
+	models.Collection
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// FileStorage File storage.
+// URLs are loaded into memory on start, every change is appended to the file, the last record of URL wins.
+// Counted redirects, redirects to A/B variants and changed statuses of destination checks are appended to separate files,
+// they are rewritten with one record per URL or variant on start.
+// Idempotency records, users' settings, collections, workspaces and accounts are kept the same way in separate files.
+// Idempotency file is rewritten without expired records on start and when most of its lines are outdated,
+// accounts file is rewritten without finished sessions when expired sessions are removed.
+// Files are owned by one storage of process, other storages of the same files don't see its changes
 type FileStorage struct {
+	*CacheStorage
 	FileStoragePath string
+	// idempotencyLines count of lines in idempotency file, guarded by the lock of cache storage
+	idempotencyLines int
 }
 
 // NewFileStorage factory for create file storage
@@ -28,114 +70,265 @@ func NewFileStorage(path string) (*FileStorage, error) {
 		}
 	}
 
-	return &FileStorage{FileStoragePath: path}, nil
+	storage := &FileStorage{
+		CacheStorage:    NewCacheStorage(),
+		FileStoragePath: path,
+	}
+
+	// put functions of cache storage require the write lock
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	if err := storage.load(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadClicks(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadVariantClicks(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadChecks(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadIdempotency(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadSettings(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadCollections(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadWorkspaces(); err != nil {
+		return &FileStorage{}, err
+	}
+	if err := storage.loadAccounts(); err != nil {
+		return &FileStorage{}, err
+	}
+	storage.persist = storage.append
+	storage.persistClicks = storage.appendClicks
+	storage.persistVariantClicks = storage.appendVariantClicks
+	storage.persistCheck = storage.appendCheck
+	storage.persistIdempotency = storage.appendIdempotency
+	storage.persistSettings = storage.appendSettings
+	storage.persistCollection = storage.appendCollection
+	storage.persistWorkspace = storage.appendWorkspace
+	storage.persistAccount = storage.appendAccount
+	storage.compactAccounts = storage.rewriteAccounts
+
+	return storage, nil
 }
 
-// Save function for save URL in DB
-func (s *FileStorage) Save(ctx context.Context, originalURL string, shortURL string, userID *uuid.UUID) (string, error) {
-	var urlStorage models.StorageURL
+// load function reads all URLs from file
+func (s *FileStorage) load() error {
+	return readJSONLines(s.FileStoragePath, 0666, func(link models.StorageURL) { _ = s.put(link) })
+}
 
-	file, err := os.OpenFile(s.FileStoragePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+// loadClicks function reads counted redirects of URLs from file, file is rewritten with the last record of every URL
+func (s *FileStorage) loadClicks() error {
+	lines := 0
+	err := readJSONLines(s.FileStoragePath+clicksFileSuffix, 0666, func(record clicksRecord) {
+		lines++
+		s.putClicks(record)
+	})
 	if err != nil {
-		log.Printf("Ошибка при открытии: %s", err)
-		return "", err
+		return err
 	}
 
-	defer file.Close()
+	records := make([]clicksRecord, 0)
+	for _, link := range s.storageURL {
+		if link.Clicks > 0 {
+			records = append(records, clicksRecord{ShortURL: link.ShortURL, Clicks: link.Clicks})
+		}
+	}
+	if lines > len(records) {
+		return rewriteJSON(s.FileStoragePath+clicksFileSuffix, 0666, records)
+	}
+	return nil
+}
 
-	urlStorage.OriginalURL, urlStorage.ShortURL, urlStorage.UserID = originalURL, shortURL, userID
-	encoder := json.NewEncoder(file)
+// loadVariantClicks function reads counted redirects to A/B variants from file,
+// file is rewritten with the last record of every variant
+func (s *FileStorage) loadVariantClicks() error {
+	lines := 0
+	err := readJSONLines(s.FileStoragePath+variantClicksFileSuffix, 0666, func(record variantClicksRecord) {
+		lines++
+		s.putVariantClicks(record)
+	})
+	if err != nil {
+		return err
+	}
 
-	if err = encoder.Encode(urlStorage); err != nil {
-		return "", err
+	records := make([]variantClicksRecord, 0)
+	for _, link := range s.storageURL {
+		for _, variant := range link.Variants {
+			if variant.Clicks > 0 {
+				records = append(records, variantClicksRecord{ShortURL: link.ShortURL, Variant: variant.ID, Clicks: variant.Clicks})
+			}
+		}
+	}
+	if lines > len(records) {
+		return rewriteJSON(s.FileStoragePath+variantClicksFileSuffix, 0666, records)
+	}
+	return nil
+}
+
+// loadChecks function reads statuses of URLs destination checks from file, file is rewritten with the last record of every URL
+func (s *FileStorage) loadChecks() error {
+	lines := 0
+	err := readJSONLines(s.FileStoragePath+checksFileSuffix, 0666, func(record checkRecord) {
+		lines++
+		s.putCheck(record)
+	})
+	if err != nil {
+		return err
 	}
 
-	return shortURL, nil
+	records := make([]checkRecord, 0)
+	for _, link := range s.storageURL {
+		if link.LastCheckedAt != nil {
+			records = append(records, checkRecord{ShortURL: link.ShortURL, Status: link.LastStatus, CheckedAt: *link.LastCheckedAt})
+		}
+	}
+	if lines > len(records) {
+		return rewriteJSON(s.FileStoragePath+checksFileSuffix, 0666, records)
+	}
+	return nil
 }
 
-// Get function for get URL from DB
-func (s *FileStorage) Get(inputURL string) (string, error) {
-	var (
-		read       [][]byte
-		storageURL []models.StorageURL
-	)
-	file, err := os.OpenFile(s.FileStoragePath, os.O_RDONLY|os.O_CREATE, 0666)
+// loadIdempotency function reads not expired idempotency records from file
+func (s *FileStorage) loadIdempotency() error {
+	err := readJSONLines(s.FileStoragePath+idempotencyFileSuffix, 0666, func(record models.IdempotencyRecord) {
+		s.idempotencyLines++
+		s.putIdempotency(record)
+	})
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	defer file.Close()
+	if s.idempotencyLines > len(s.idempotency) {
+		return s.compactIdempotency()
+	}
+	return nil
+}
 
-	reader := bufio.NewReader(file)
+// loadSettings function reads users' settings from file, the last record of user wins
+func (s *FileStorage) loadSettings() error {
+	return readJSONLines(s.FileStoragePath+settingsFileSuffix, 0666, s.putSettings)
+}
 
-	for {
-		data, err := reader.ReadBytes('\n')
-		if err == io.EOF {
-			break
-		}
+// loadCollections function reads users' collections from file, the last record of collection wins
+func (s *FileStorage) loadCollections() error {
+	return readJSONLines(s.FileStoragePath+collectionsFileSuffix, 0666, func(record collectionRecord) {
+		s.putCollection(record.Collection, record.Deleted)
+	})
+}
+
+// loadWorkspaces function reads changes of workspaces and their members from file in order of writing
+func (s *FileStorage) loadWorkspaces() error {
+	return readJSONLines(s.FileStoragePath+workspacesFileSuffix, 0666, s.putWorkspace)
+}
+
+// loadAccounts function reads accounts and changes of login sessions from file in order of writing
+func (s *FileStorage) loadAccounts() error {
+	return readJSONLines(s.FileStoragePath+accountsFileSuffix, 0600, s.putAccount)
+}
+
+// append function writes URL record to the end of file
+func (s *FileStorage) append(link models.StorageURL) error {
+	return appendJSON(s.FileStoragePath, link)
+}
 
-		read = append(read, data)
+// appendIdempotency function writes idempotency record to the end of file,
+// file is compacted when expired and replaced records are more than half of it
+func (s *FileStorage) appendIdempotency(record models.IdempotencyRecord) error {
+	if err := appendJSON(s.FileStoragePath+idempotencyFileSuffix, record); err != nil {
+		return err
 	}
+	s.idempotencyLines++
 
-	for _, line := range read {
-		urls := models.StorageURL{}
-		err := json.Unmarshal(line, &urls)
-		if err == nil {
-			storageURL = append(storageURL, urls)
-		}
+	if s.idempotencyLines < idempotencyCompactLines || s.idempotencyLines <= 2*(len(s.idempotency)+1) {
+		return nil
 	}
 
-	// Поиск соотвествия полученного url сокращенному или полному url в хранилище, в зависимости от типа запроса.
-	// Для POST запросов ищем по OriginalURL, для GET - ShortURL
-	for _, ur := range storageURL {
-		if ur.ShortURL == inputURL {
-			return ur.OriginalURL, nil
-		} else if ur.OriginalURL == inputURL {
-			return ur.ShortURL, nil
+	// запись еще не добавлена в кэш, она сохраняется вместе с действующими
+	s.putIdempotency(record)
+	return s.compactIdempotency()
+}
+
+// compactIdempotency function rewrites idempotency file with not expired records only, caller must hold the write lock
+func (s *FileStorage) compactIdempotency() error {
+	now := time.Now()
+	records := make([]models.IdempotencyRecord, 0, len(s.idempotency))
+	for _, record := range s.idempotency {
+		if record.ExpiresAt.After(now) {
+			records = append(records, record)
 		}
 	}
 
-	return "", nil
+	if err := rewriteJSON(s.FileStoragePath+idempotencyFileSuffix, 0666, records); err != nil {
+		return err
+	}
+
+	s.idempotencyLines = len(records)
+	return nil
 }
 
-// SaveBatch function for saving URL list
-func (s *FileStorage) SaveBatch(ctx context.Context, urls []models.StorageURL, userID *uuid.UUID) ([]string, error) {
-	var urlStorage models.StorageURL
+// appendCheck function writes changed status of URL destination check to the end of file
+func (s *FileStorage) appendCheck(record checkRecord) error {
+	return appendJSON(s.FileStoragePath+checksFileSuffix, record)
+}
 
-	file, err := os.OpenFile(s.FileStoragePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		log.Printf("Ошибка при открытии: %s", err)
-		return nil, err
-	}
+// appendClicks function writes counted redirects of URL to the end of file
+func (s *FileStorage) appendClicks(record clicksRecord) error {
+	return appendJSON(s.FileStoragePath+clicksFileSuffix, record)
+}
 
-	defer file.Close()
+// appendVariantClicks function writes counted redirects to A/B variant of URL to the end of file
+func (s *FileStorage) appendVariantClicks(record variantClicksRecord) error {
+	return appendJSON(s.FileStoragePath+variantClicksFileSuffix, record)
+}
 
-	for _, url := range urls {
-		urlStorage.OriginalURL, urlStorage.ShortURL, urlStorage.UserID = url.OriginalURL, url.ShortURL, userID
-		encoder := json.NewEncoder(file)
+// appendSettings function writes user's settings to the end of file
+func (s *FileStorage) appendSettings(settings models.UserSettings) error {
+	return appendJSON(s.FileStoragePath+settingsFileSuffix, settings)
+}
 
-		if err = encoder.Encode(urlStorage); err != nil {
-			return nil, err
-		}
-	}
+// appendCollection function writes collection or its deletion to the end of file
+func (s *FileStorage) appendCollection(collection models.Collection, deleted bool) error {
+	return appendJSON(s.FileStoragePath+collectionsFileSuffix, collectionRecord{Collection: collection, Deleted: deleted})
+}
 
-	return nil, nil
+// appendWorkspace function writes change of workspace or member to the end of file
+func (s *FileStorage) appendWorkspace(record workspaceRecord) error {
+	return appendJSON(s.FileStoragePath+workspacesFileSuffix, record)
 }
 
-// DeleteBatch function for delete URLs list
-func (s *FileStorage) DeleteBatch(ctx context.Context, userID *uuid.UUID, urls []string) error {
-	return nil
+// appendAccount function writes created account or change of session to the end of file
+func (s *FileStorage) appendAccount(record accountRecord) error {
+	return appendJSON(s.FileStoragePath+accountsFileSuffix, record)
 }
 
-// GetAllUrlsByUser function for get all user's URLs
-func (s *FileStorage) GetAllUrlsByUser(ctx context.Context, userID *uuid.UUID) ([]models.StorageURL, error) {
-	var (
-		read       [][]byte
-		storageURL []models.StorageURL
-	)
-	file, err := os.OpenFile(s.FileStoragePath, os.O_RDONLY|os.O_CREATE, 0666)
+// rewriteAccounts function rewrites accounts file with accounts and current sessions only, caller must hold the write lock
+func (s *FileStorage) rewriteAccounts() error {
+	records := make([]accountRecord, 0, len(s.accounts)+len(s.sessions))
+	for _, account := range s.accounts {
+		records = append(records, accountRecord{Account: &account})
+	}
+	for _, session := range s.sessions {
+		records = append(records, accountRecord{Session: &session})
+	}
+
+	return rewriteJSON(s.FileStoragePath+accountsFileSuffix, 0600, records)
+}
+
+// readJSONLines function passes every decoded JSON line of file to put, file is created with perm if it doesn't exist,
+// lines which can't be decoded are skipped
+func readJSONLines[T any](path string, perm os.FileMode, put func(T)) error {
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, perm)
 	if err != nil {
-		return []models.StorageURL{}, err
+		return err
 	}
 
 	defer file.Close()
@@ -144,29 +337,59 @@ func (s *FileStorage) GetAllUrlsByUser(ctx context.Context, userID *uuid.UUID) (
 
 	for {
 		data, err := reader.ReadBytes('\n')
-		if err == io.EOF {
-			break
+		if len(data) > 0 {
+			var value T
+			if err := json.Unmarshal(data, &value); err == nil {
+				put(value)
+			}
 		}
 
-		read = append(read, data)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 	}
+}
 
-	for _, line := range read {
-		urls := models.StorageURL{}
-		err := json.Unmarshal(line, &urls)
-		if err == nil {
-			storageURL = append(storageURL, urls)
-		}
+// appendJSON function writes value as JSON line to the end of file
+func appendJSON(path string, value any) error {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		log.Printf("Ошибка при открытии: %s", err)
+		return err
 	}
 
-	return storageURL, nil
-}
+	defer file.Close()
 
-// Ping function for ping DB connection
-func (s *FileStorage) Ping(ctx context.Context) error {
-	return nil
+	encoder := json.NewEncoder(file)
+
+	return encoder.Encode(value)
 }
 
-func (s *FileStorage) GetStats(ctx context.Context) (models.StorageStats, error) {
-	return models.StorageStats{}, nil
+// rewriteJSON function replaces file with values as JSON lines, file is written to temporary file and renamed
+func rewriteJSON[T any](path string, perm os.FileMode, values []T) error {
+	file, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, value := range values {
+		if err = encoder.Encode(value); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
 }
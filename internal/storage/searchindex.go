@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"github.com/romanp1989/go-shortener/internal/models"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Веса полей совпадают со значениями по умолчанию для ts_rank в Postgres (A, B, C, D)
+const (
+	titleWeight    = 1.0
+	tagsWeight     = 0.4
+	notesWeight    = 0.2
+	originalWeight = 0.1
+
+	// prefixFactor share of field weight for token prefix matches
+	prefixFactor = 0.5
+)
+
+// searchIndex in-memory inverted index for user's URLs full-text search.
+// Sorted vocabulary is kept for prefix matches, tokens with the same prefix are adjacent in it
+type searchIndex struct {
+	tokens map[string]map[string]float64
+	sorted []string
+	docs   map[string][]string
+}
+
+// newSearchIndex factory for create search index
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		tokens: make(map[string]map[string]float64),
+		docs:   make(map[string][]string),
+	}
+}
+
+// tokenize function splits text into lower case words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// add function for index URL fields, previous index entries of the URL are replaced
+func (idx *searchIndex) add(link models.StorageURL) {
+	idx.remove(link.ShortURL)
+
+	weights := make(map[string]float64)
+	fields := []struct {
+		text   string
+		weight float64
+	}{
+		{link.Title, titleWeight},
+		{strings.Join(link.Tags, " "), tagsWeight},
+		{link.Notes, notesWeight},
+		{link.OriginalURL, originalWeight},
+	}
+
+	for _, field := range fields {
+		for _, token := range tokenize(field.text) {
+			if weights[token] < field.weight {
+				weights[token] = field.weight
+			}
+		}
+	}
+
+	docTokens := make([]string, 0, len(weights))
+	for token, weight := range weights {
+		if idx.tokens[token] == nil {
+			idx.tokens[token] = make(map[string]float64)
+			i := sort.SearchStrings(idx.sorted, token)
+			idx.sorted = append(idx.sorted, "")
+			copy(idx.sorted[i+1:], idx.sorted[i:])
+			idx.sorted[i] = token
+		}
+		idx.tokens[token][link.ShortURL] = weight
+		docTokens = append(docTokens, token)
+	}
+	idx.docs[link.ShortURL] = docTokens
+}
+
+// remove function for delete URL from index
+func (idx *searchIndex) remove(shortURL string) {
+	for _, token := range idx.docs[shortURL] {
+		delete(idx.tokens[token], shortURL)
+		if len(idx.tokens[token]) == 0 {
+			delete(idx.tokens, token)
+			if i := sort.SearchStrings(idx.sorted, token); i < len(idx.sorted) && idx.sorted[i] == token {
+				idx.sorted = append(idx.sorted[:i], idx.sorted[i+1:]...)
+			}
+		}
+	}
+	delete(idx.docs, shortURL)
+}
+
+// search function returns rank of short URLs matching the query.
+// Each query word adds the weight of the best matching field, prefix matches are ranked lower
+func (idx *searchIndex) search(query string) map[string]float64 {
+	ranks := make(map[string]float64)
+
+	for _, word := range tokenize(query) {
+		best := make(map[string]float64)
+		for shortURL, weight := range idx.tokens[word] {
+			best[shortURL] = weight
+		}
+
+		// слова с префиксом запроса идут в словаре подряд, начиная с самого запроса
+		for i := sort.SearchStrings(idx.sorted, word); i < len(idx.sorted) && strings.HasPrefix(idx.sorted[i], word); i++ {
+			if idx.sorted[i] == word {
+				continue
+			}
+			for shortURL, weight := range idx.tokens[idx.sorted[i]] {
+				if rank := weight * prefixFactor; rank > best[shortURL] {
+					best[shortURL] = rank
+				}
+			}
+		}
+
+		for shortURL, rank := range best {
+			ranks[shortURL] += rank
+		}
+	}
+
+	return ranks
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/romanp1989/go-shortener/internal/models"
 	"log"
+	"time"
 )
 
 // Storage structure for storage
@@ -51,6 +52,16 @@ func (s *Storage) GetAllUrlsByUser(ctx context.Context, userID *uuid.UUID) ([]mo
 	return s.Storage.GetAllUrlsByUser(ctx, userID)
 }
 
+// IterateUrlsByUser function calls fn for every user's URL without loading all of them in memory
+func (s *Storage) IterateUrlsByUser(ctx context.Context, userID *uuid.UUID, fn func(models.StorageURL) error) error {
+	return s.Storage.IterateUrlsByUser(ctx, userID, fn)
+}
+
+// GetUrlsPageByUser function for get page of user's not deleted URLs with count of all of them, newest URLs are first
+func (s *Storage) GetUrlsPageByUser(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]models.StorageURL, int, error) {
+	return s.Storage.GetUrlsPageByUser(ctx, userID, limit, offset)
+}
+
 // DeleteUrlsBatch function for delete URLs list
 func (s *Storage) DeleteUrlsBatch(ctx context.Context, userID *uuid.UUID, urls []string) error {
 	return s.Storage.DeleteBatch(ctx, userID, urls)
@@ -65,3 +76,183 @@ func (s *Storage) Ping(ctx context.Context) error {
 func (s *Storage) GetStats(ctx context.Context) (models.StorageStats, error) {
 	return s.Storage.GetStats(ctx)
 }
+
+// SaveLink function for save URL with metadata in storage
+func (s *Storage) SaveLink(ctx context.Context, link models.StorageURL) (string, error) {
+	return s.Storage.SaveLink(ctx, link)
+}
+
+// Search function for full-text search in user's URLs
+func (s *Storage) Search(ctx context.Context, userID *uuid.UUID, query string, limit int) ([]models.SearchResult, error) {
+	return s.Storage.Search(ctx, userID, query, limit)
+}
+
+// GetIdempotencyRecord function for get saved response by user and idempotency key
+func (s *Storage) GetIdempotencyRecord(ctx context.Context, userID *uuid.UUID, key string) (*models.IdempotencyRecord, error) {
+	return s.Storage.GetIdempotencyRecord(ctx, userID, key)
+}
+
+// SaveIdempotencyRecord function for save response of request with idempotency key
+func (s *Storage) SaveIdempotencyRecord(ctx context.Context, record models.IdempotencyRecord) error {
+	return s.Storage.SaveIdempotencyRecord(ctx, record)
+}
+
+// GetLink function for get URL with metadata by short URL
+func (s *Storage) GetLink(ctx context.Context, shortURL string) (*models.StorageURL, error) {
+	return s.Storage.GetLink(ctx, shortURL)
+}
+
+// UpdateLink function for update title, notes and tags of URL
+func (s *Storage) UpdateLink(ctx context.Context, link models.StorageURL) error {
+	return s.Storage.UpdateLink(ctx, link)
+}
+
+// IncrementClicks function for counting redirect by short URL
+func (s *Storage) IncrementClicks(ctx context.Context, shortURL string) error {
+	return s.Storage.IncrementClicks(ctx, shortURL)
+}
+
+// IncrementVariantClicks function for counting redirect to A/B variant of short URL
+func (s *Storage) IncrementVariantClicks(ctx context.Context, shortURL string, variantID string) error {
+	return s.Storage.IncrementVariantClicks(ctx, shortURL, variantID)
+}
+
+// GetLinksForCheck function for get URLs without clicks limit and password not checked since checkedBefore
+func (s *Storage) GetLinksForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.StorageURL, error) {
+	return s.Storage.GetLinksForCheck(ctx, checkedBefore, limit)
+}
+
+// SaveLinkCheck function for save result of URL destination check
+func (s *Storage) SaveLinkCheck(ctx context.Context, shortURL string, status int, checkedAt time.Time) error {
+	return s.Storage.SaveLinkCheck(ctx, shortURL, status, checkedAt)
+}
+
+// SavePageMetadata function for save title, description and image of URL destination page
+func (s *Storage) SavePageMetadata(ctx context.Context, shortURL string, page models.PageMetadata) error {
+	return s.Storage.SavePageMetadata(ctx, shortURL, page)
+}
+
+// GetUserSettings function for get user's settings
+func (s *Storage) GetUserSettings(ctx context.Context, userID *uuid.UUID) (*models.UserSettings, error) {
+	return s.Storage.GetUserSettings(ctx, userID)
+}
+
+// SaveUserSettings function for save user's settings
+func (s *Storage) SaveUserSettings(ctx context.Context, settings models.UserSettings) error {
+	return s.Storage.SaveUserSettings(ctx, settings)
+}
+
+// GetCollections function for get user's collections
+func (s *Storage) GetCollections(ctx context.Context, userID *uuid.UUID) ([]models.Collection, error) {
+	return s.Storage.GetCollections(ctx, userID)
+}
+
+// GetCollection function for get collection by id
+func (s *Storage) GetCollection(ctx context.Context, id string) (*models.Collection, error) {
+	return s.Storage.GetCollection(ctx, id)
+}
+
+// SaveCollection function for create or update collection
+func (s *Storage) SaveCollection(ctx context.Context, collection models.Collection) error {
+	return s.Storage.SaveCollection(ctx, collection)
+}
+
+// DeleteCollection function for delete collection, its links are kept without collection
+func (s *Storage) DeleteCollection(ctx context.Context, id string) error {
+	return s.Storage.DeleteCollection(ctx, id)
+}
+
+// GetUserWorkspaces function for get workspaces of user with user's role
+func (s *Storage) GetUserWorkspaces(ctx context.Context, userID *uuid.UUID) ([]models.Workspace, error) {
+	return s.Storage.GetUserWorkspaces(ctx, userID)
+}
+
+// GetWorkspace function for get workspace by id
+func (s *Storage) GetWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
+	return s.Storage.GetWorkspace(ctx, id)
+}
+
+// SaveWorkspace function for create or rename workspace
+func (s *Storage) SaveWorkspace(ctx context.Context, workspace models.Workspace) error {
+	return s.Storage.SaveWorkspace(ctx, workspace)
+}
+
+// DeleteWorkspace function for delete workspace with members, its links are returned to their creators
+func (s *Storage) DeleteWorkspace(ctx context.Context, id string) error {
+	return s.Storage.DeleteWorkspace(ctx, id)
+}
+
+// GetWorkspaceMembers function for get members of workspace
+func (s *Storage) GetWorkspaceMembers(ctx context.Context, workspaceID string) ([]models.WorkspaceMember, error) {
+	return s.Storage.GetWorkspaceMembers(ctx, workspaceID)
+}
+
+// SaveWorkspaceMember function for add member to workspace or change member's role
+func (s *Storage) SaveWorkspaceMember(ctx context.Context, member models.WorkspaceMember) error {
+	return s.Storage.SaveWorkspaceMember(ctx, member)
+}
+
+// DeleteWorkspaceMember function for remove member from workspace
+func (s *Storage) DeleteWorkspaceMember(ctx context.Context, workspaceID string, userID *uuid.UUID) error {
+	return s.Storage.DeleteWorkspaceMember(ctx, workspaceID, userID)
+}
+
+// IterateUrlsByWorkspace function calls fn for every URL of workspace without loading all of them in memory
+func (s *Storage) IterateUrlsByWorkspace(ctx context.Context, workspaceID string, fn func(models.StorageURL) error) error {
+	return s.Storage.IterateUrlsByWorkspace(ctx, workspaceID, fn)
+}
+
+// GetUrlsPageByWorkspace function for get page of not deleted URLs of workspace with count of all of them, newest URLs are first
+func (s *Storage) GetUrlsPageByWorkspace(ctx context.Context, workspaceID string, limit int, offset int) ([]models.StorageURL, int, error) {
+	return s.Storage.GetUrlsPageByWorkspace(ctx, workspaceID, limit, offset)
+}
+
+// DeleteWorkspaceUrlsBatch function for delete URLs list of workspace
+func (s *Storage) DeleteWorkspaceUrlsBatch(ctx context.Context, workspaceID string, urls []string) error {
+	return s.Storage.DeleteWorkspaceBatch(ctx, workspaceID, urls)
+}
+
+// GetAccount function for get account by user id
+func (s *Storage) GetAccount(ctx context.Context, userID *uuid.UUID) (*models.Account, error) {
+	return s.Storage.GetAccount(ctx, userID)
+}
+
+// GetAccountByEmail function for get account by email
+func (s *Storage) GetAccountByEmail(ctx context.Context, email string) (*models.Account, error) {
+	return s.Storage.GetAccountByEmail(ctx, email)
+}
+
+// SaveAccount function for create account, ErrConflict is returned if email or user id is taken
+func (s *Storage) SaveAccount(ctx context.Context, account models.Account) error {
+	return s.Storage.SaveAccount(ctx, account)
+}
+
+// GetSession function for get login session by id
+func (s *Storage) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	return s.Storage.GetSession(ctx, id)
+}
+
+// SaveSession function for save login session
+func (s *Storage) SaveSession(ctx context.Context, session models.Session) error {
+	return s.Storage.SaveSession(ctx, session)
+}
+
+// DeleteSession function for delete login session
+func (s *Storage) DeleteSession(ctx context.Context, id string) error {
+	return s.Storage.DeleteSession(ctx, id)
+}
+
+// DeleteExpiredSessions function for delete expired login sessions
+func (s *Storage) DeleteExpiredSessions(ctx context.Context) error {
+	return s.Storage.DeleteExpiredSessions(ctx)
+}
+
+// ClaimUrls function for move URLs and collections of one user to another one
+func (s *Storage) ClaimUrls(ctx context.Context, from *uuid.UUID, to *uuid.UUID) (int, error) {
+	return s.Storage.ClaimUrls(ctx, from, to)
+}
+
+// GetScheduledLinks function for get URLs with active window of all users
+func (s *Storage) GetScheduledLinks(ctx context.Context) ([]models.StorageURL, error) {
+	return s.Storage.GetScheduledLinks(ctx)
+}